@@ -0,0 +1,214 @@
+package lcsc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Key parameter names used when comparing alternatives. LCSC is not fully
+// consistent in naming, so several spellings are listed for each.
+var (
+	valueParamNames     = []string{"Capacitance", "Resistance", "Inductance", "Frequency"}
+	toleranceParamNames = []string{"Tolerance"}
+	voltageParamNames   = []string{"Voltage Rated", "Rated Voltage", "Voltage Rating", "Voltage - Rated", "Voltage Rating (DC)"}
+	tempcoParamNames    = []string{"Temperature Coefficient"}
+)
+
+// valueTolerance is the relative difference allowed when comparing values.
+const valueTolerance = 1e-3
+
+// AlternativeOptions configures FindAlternatives.
+type AlternativeOptions struct {
+	Quantity    int  // Quantity used for stock and price ranking (default: MinPacketNumber or 1)
+	InStockOnly bool // Exclude candidates with insufficient stock for Quantity
+	MaxResults  int  // Maximum number of alternatives to return (0 for no limit)
+}
+
+// Alternative is a candidate replacement for a product.
+type Alternative struct {
+	Product   Product
+	UnitPrice float64 // Unit price at the requested quantity
+	InStock   bool    // Whether stock covers the requested quantity
+}
+
+// FindAlternatives searches for drop-in replacements for product. Candidates
+// must share the product's CatalogName and EncapStandard and have compatible
+// key parameters: the same value, an equal or tighter tolerance, an equal or
+// higher voltage rating and the same temperature coefficient.
+//
+// Results are ranked with in-stock parts first, then by unit price at the
+// requested quantity and finally by stock.
+func (c *Client) FindAlternatives(ctx context.Context, product Product, opts AlternativeOptions) ([]Alternative, error) {
+	keyword := alternativesKeyword(product)
+	if keyword == "" {
+		return nil, fmt.Errorf("product has no catalog or package information")
+	}
+
+	qty := opts.Quantity
+	if qty <= 0 {
+		qty = int(product.MinPacketNumber)
+	}
+	if qty <= 0 {
+		qty = 1
+	}
+
+	resp, err := c.KeywordSearch(ctx, SearchRequest{Keyword: keyword})
+	if err != nil {
+		return nil, err
+	}
+
+	var alternatives []Alternative
+	for _, candidate := range resp.Products {
+		if candidate.ProductCode == product.ProductCode {
+			continue
+		}
+		if !IsCompatibleAlternative(product, candidate) {
+			continue
+		}
+		alt := Alternative{
+			Product:   candidate,
+			UnitPrice: candidate.UnitPrice(qty),
+			InStock:   int(candidate.StockNumber) >= qty,
+		}
+		if opts.InStockOnly && !alt.InStock {
+			continue
+		}
+		alternatives = append(alternatives, alt)
+	}
+
+	sort.SliceStable(alternatives, func(i, j int) bool {
+		a, b := alternatives[i], alternatives[j]
+		if a.InStock != b.InStock {
+			return a.InStock
+		}
+		if a.UnitPrice != b.UnitPrice {
+			// Parts without pricing sort last.
+			if a.UnitPrice == 0 || b.UnitPrice == 0 {
+				return b.UnitPrice == 0
+			}
+			return a.UnitPrice < b.UnitPrice
+		}
+		return a.Product.StockNumber > b.Product.StockNumber
+	})
+
+	if opts.MaxResults > 0 && len(alternatives) > opts.MaxResults {
+		alternatives = alternatives[:opts.MaxResults]
+	}
+
+	return alternatives, nil
+}
+
+// IsCompatibleAlternative reports whether candidate can replace original:
+// same category and package, and compatible key parameters. Parameters the
+// original does not specify are not checked.
+func IsCompatibleAlternative(original, candidate Product) bool {
+	if !strings.EqualFold(strings.TrimSpace(original.CatalogName), strings.TrimSpace(candidate.CatalogName)) {
+		return false
+	}
+	if !strings.EqualFold(strings.TrimSpace(original.EncapStandard), strings.TrimSpace(candidate.EncapStandard)) {
+		return false
+	}
+
+	// Value: must be the same.
+	if name, raw, ok := original.firstParam(valueParamNames); ok {
+		want := ParseParamValue(raw)
+		have, found := candidate.ParsedParam(name)
+		if !found || !sameParamValue(want, have) {
+			return false
+		}
+	}
+
+	// Tolerance: candidate must be equal or tighter.
+	if _, raw, ok := original.firstParam(toleranceParamNames); ok {
+		want := ParseParamValue(raw)
+		_, candRaw, found := candidate.firstParam(toleranceParamNames)
+		if !found {
+			return false
+		}
+		have := ParseParamValue(candRaw)
+		if !comparableUnits(want, have) || have.Value > want.Value*(1+valueTolerance) {
+			return false
+		}
+	}
+
+	// Voltage rating: candidate must be equal or higher.
+	if _, raw, ok := original.firstParam(voltageParamNames); ok {
+		want := ParseParamValue(raw)
+		_, candRaw, found := candidate.firstParam(voltageParamNames)
+		if !found {
+			return false
+		}
+		have := ParseParamValue(candRaw)
+		if !comparableUnits(want, have) || have.Value < want.Value*(1-valueTolerance) {
+			return false
+		}
+	}
+
+	// Temperature coefficient: same dielectric class, or equal or lower ppm.
+	if _, raw, ok := original.firstParam(tempcoParamNames); ok {
+		_, candRaw, found := candidate.firstParam(tempcoParamNames)
+		if !found || !compatibleTempco(raw, candRaw) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// alternativesKeyword builds a search keyword for finding alternatives.
+func alternativesKeyword(p Product) string {
+	var parts []string
+	if _, value, ok := p.firstParam(valueParamNames); ok {
+		parts = append(parts, strings.TrimSpace(value))
+	} else if p.CatalogName != "" {
+		parts = append(parts, strings.TrimSpace(p.CatalogName))
+	}
+	if p.EncapStandard != "" {
+		parts = append(parts, strings.TrimSpace(p.EncapStandard))
+	}
+	return strings.Join(parts, " ")
+}
+
+// sameParamValue reports whether two parsed values are equal. Unparsed
+// values are compared as text.
+func sameParamValue(a, b ParamValue) bool {
+	if !a.Valid || !b.Valid {
+		return strings.EqualFold(strings.TrimSpace(a.Raw), strings.TrimSpace(b.Raw))
+	}
+	if a.Unit != b.Unit {
+		return false
+	}
+	if a.Value == b.Value {
+		return true
+	}
+	return math.Abs(a.Value-b.Value) <= valueTolerance*math.Max(math.Abs(a.Value), math.Abs(b.Value))
+}
+
+// comparableUnits reports whether two values were parsed with the same unit.
+func comparableUnits(a, b ParamValue) bool {
+	return a.Valid && b.Valid && a.Unit == b.Unit
+}
+
+// compatibleTempco compares temperature coefficients. Numeric coefficients
+// ("±100ppm/℃") are compatible when the candidate is equal or lower;
+// dielectric classes ("X7R") must match, treating C0G and NP0 as equal.
+func compatibleTempco(original, candidate string) bool {
+	want, have := ParseParamValue(original), ParseParamValue(candidate)
+	if want.Valid && have.Valid {
+		return want.Unit == have.Unit && have.Value <= want.Value*(1+valueTolerance)
+	}
+	return dielectricKey(original) == dielectricKey(candidate)
+}
+
+// dielectricKey normalizes a ceramic dielectric class name.
+func dielectricKey(s string) string {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.ReplaceAll(s, "NPO", "NP0")
+	if s == "NP0" || s == "C0G/NP0" || s == "NP0/C0G" {
+		return "C0G"
+	}
+	return s
+}
@@ -0,0 +1,124 @@
+package lcsc
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+// mlcc builds a test capacitor product.
+func mlcc(code, value, tolerance, voltage, tempco string, stock int, price float64) Product {
+	return Product{
+		ProductCode:      code,
+		CatalogName:      "Multilayer Ceramic Capacitors MLCC - SMD/SMT",
+		EncapStandard:    "0603",
+		StockNumber:      FlexInt(stock),
+		MinPacketNumber:  100,
+		ProductPriceList: []PriceBreak{{Ladder: 100, ProductPrice: FlexFloat64(price)}},
+		ParamVOList: []Parameter{
+			{ParamNameEn: "Capacitance", ParamValueEn: value},
+			{ParamNameEn: "Tolerance", ParamValueEn: tolerance},
+			{ParamNameEn: "Voltage Rated", ParamValueEn: voltage},
+			{ParamNameEn: "Temperature Coefficient", ParamValueEn: tempco},
+		},
+	}
+}
+
+// TestIsCompatibleAlternative tests key parameter compatibility rules.
+func TestIsCompatibleAlternative(t *testing.T) {
+	original := mlcc("C1", "100nF", "±10%", "50V", "X7R", 0, 0.01)
+
+	tests := []struct {
+		name      string
+		candidate Product
+		expected  bool
+	}{
+		{"identical", mlcc("C2", "100nF", "±10%", "50V", "X7R", 0, 0), true},
+		{"same value other spelling", mlcc("C2", "0.1uF", "±10%", "50V", "X7R", 0, 0), true},
+		{"tighter tolerance", mlcc("C2", "100nF", "±5%", "50V", "X7R", 0, 0), true},
+		{"higher voltage", mlcc("C2", "100nF", "±10%", "100V", "X7R", 0, 0), true},
+		{"different value", mlcc("C2", "1uF", "±10%", "50V", "X7R", 0, 0), false},
+		{"looser tolerance", mlcc("C2", "100nF", "±20%", "50V", "X7R", 0, 0), false},
+		{"lower voltage", mlcc("C2", "100nF", "±10%", "25V", "X7R", 0, 0), false},
+		{"different dielectric", mlcc("C2", "100nF", "±10%", "50V", "X5R", 0, 0), false},
+	}
+
+	for _, test := range tests {
+		if got := IsCompatibleAlternative(original, test.candidate); got != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+
+	otherPackage := mlcc("C2", "100nF", "±10%", "50V", "X7R", 0, 0)
+	otherPackage.EncapStandard = "0805"
+	if IsCompatibleAlternative(original, otherPackage) {
+		t.Error("expected different package to be incompatible")
+	}
+}
+
+// TestCompatibleTempco tests temperature coefficient comparison.
+func TestCompatibleTempco(t *testing.T) {
+	if !compatibleTempco("C0G", "NP0") {
+		t.Error("expected C0G and NP0 to be compatible")
+	}
+	if !compatibleTempco("±100ppm/℃", "±50ppm/℃") {
+		t.Error("expected lower ppm to be compatible")
+	}
+	if compatibleTempco("±50ppm/℃", "±100ppm/℃") {
+		t.Error("expected higher ppm to be incompatible")
+	}
+}
+
+// TestFindAlternatives tests searching, filtering and ranking of alternatives.
+func TestFindAlternatives(t *testing.T) {
+	original := mlcc("C1", "100nF", "±10%", "50V", "X7R", 0, 0.01)
+
+	var keyword string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body searchRequestBody
+		_ = decodeJSONBody(r, &body)
+		keyword = body.Keyword
+		writeAPIResult(t, w, map[string]interface{}{
+			"productSearchResultVO": map[string]interface{}{
+				"productList": []Product{
+					original,
+					mlcc("C2", "100nF", "±10%", "50V", "X7R", 50, 0.005),
+					mlcc("C3", "100nF", "±10%", "50V", "X7R", 10000, 0.008),
+					mlcc("C4", "100nF", "±10%", "50V", "X7R", 10000, 0.006),
+					mlcc("C5", "1uF", "±10%", "50V", "X7R", 10000, 0.001),
+				},
+			},
+		})
+	})
+
+	alts, err := client.FindAlternatives(context.Background(), original, AlternativeOptions{})
+	if err != nil {
+		t.Fatalf("FindAlternatives failed: %v", err)
+	}
+
+	if keyword != "100nF 0603" {
+		t.Errorf("expected keyword %q, got %q", "100nF 0603", keyword)
+	}
+
+	var codes []string
+	for _, alt := range alts {
+		codes = append(codes, alt.Product.ProductCode)
+	}
+	expected := []string{"C4", "C3", "C2"}
+	if len(codes) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, codes)
+	}
+	for i := range expected {
+		if codes[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, codes)
+		}
+	}
+
+	inStock, err := client.FindAlternatives(context.Background(), original, AlternativeOptions{InStockOnly: true, MaxResults: 1})
+	if err != nil {
+		t.Fatalf("FindAlternatives failed: %v", err)
+	}
+	if len(inStock) != 1 || inStock[0].Product.ProductCode != "C4" {
+		t.Errorf("expected only C4, got %+v", inStock)
+	}
+}
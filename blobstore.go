@@ -0,0 +1,220 @@
+package lcsc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// BlobStore is a local content-addressed store for downloaded files.
+// Content is stored once per SHA-256 hash under dir/<hh>/<hash>, and an
+// index maps source URLs to their content so repeated downloads of the same
+// URL are skipped. It is safe for concurrent use.
+type BlobStore struct {
+	mu      sync.Mutex
+	dir     string
+	index   map[string]Download
+	mirrors map[string]*mirrorLock // Per-URL locks held while mirroring
+}
+
+// mirrorLock serializes mirrors of one URL.
+type mirrorLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// NewBlobStore opens or creates a blob store in dir.
+func NewBlobStore(dir string) (*BlobStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, ".partial"), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	s := &BlobStore{dir: dir, index: make(map[string]Download), mirrors: make(map[string]*mirrorLock)}
+	data, err := os.ReadFile(s.indexFile())
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read blob index: %w", err)
+	default:
+		if err := json.Unmarshal(data, &s.index); err != nil {
+			return nil, fmt.Errorf("failed to decode blob index: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// Path returns the path content with the given hex SHA-256 hash is stored at.
+func (s *BlobStore) Path(hash string) string {
+	if len(hash) < 2 {
+		return filepath.Join(s.dir, hash)
+	}
+	return filepath.Join(s.dir, hash[:2], hash)
+}
+
+// Has reports whether content with the given hash is stored.
+func (s *BlobStore) Has(hash string) bool {
+	_, err := os.Stat(s.Path(hash))
+	return err == nil
+}
+
+// Lookup returns the stored download of rawURL.
+func (s *BlobStore) Lookup(rawURL string) (*Download, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.index[rawURL]
+	if !ok || !s.Has(d.SHA256) {
+		return nil, false
+	}
+	d.Path = s.Path(d.SHA256)
+	return &d, true
+}
+
+// put moves a downloaded file into the store, keeping the existing copy if
+// the content is already stored, and records its URL in the index.
+func (s *BlobStore) put(tmp string, d *Download) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.Path(d.SHA256)
+	if s.Has(d.SHA256) {
+		_ = os.Remove(tmp)
+	} else {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create blob directory: %w", err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			return fmt.Errorf("failed to store blob: %w", err)
+		}
+	}
+	d.Path = path
+
+	s.index[d.URL] = *d
+	return s.saveIndex()
+}
+
+// saveIndex writes the URL index. The caller must hold s.mu.
+func (s *BlobStore) saveIndex() error {
+	data, err := json.MarshalIndent(s.index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode blob index: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".index-*")
+	if err != nil {
+		return fmt.Errorf("failed to write blob index: %w", err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.indexFile())
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write blob index: %w", err)
+	}
+	return nil
+}
+
+func (s *BlobStore) indexFile() string {
+	return filepath.Join(s.dir, "index.json")
+}
+
+// lockURL locks rawURL for mirroring and returns the function unlocking it.
+func (s *BlobStore) lockURL(rawURL string) func() {
+	s.mu.Lock()
+	l, ok := s.mirrors[rawURL]
+	if !ok {
+		l = &mirrorLock{}
+		s.mirrors[rawURL] = l
+	}
+	l.refs++
+	s.mu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		s.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(s.mirrors, rawURL)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// partialFile returns the resumable download file for rawURL.
+func (s *BlobStore) partialFile(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(s.dir, ".partial", hex.EncodeToString(sum[:]))
+}
+
+// Mirror downloads rawURL into store unless it is already stored, returning
+// the stored download. Interrupted downloads resume on the next call, and
+// content already stored from another URL is not stored again. Concurrent
+// mirrors of the same URL wait for each other and download it once.
+func (c *Client) Mirror(ctx context.Context, store *BlobStore, rawURL string, opts DownloadOptions) (*Download, error) {
+	if d, ok := store.Lookup(rawURL); ok {
+		return d, nil
+	}
+
+	unlock := store.lockURL(rawURL)
+	defer unlock()
+	if d, ok := store.Lookup(rawURL); ok {
+		return d, nil
+	}
+
+	tmp := store.partialFile(rawURL)
+	d, err := c.DownloadFile(ctx, rawURL, tmp, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.put(tmp, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// MirrorProduct mirrors a product's datasheet and images into store,
+// returning the stored downloads in that order. URLs are fetched once each;
+// the first failure stops the mirror.
+func (c *Client) MirrorProduct(ctx context.Context, store *BlobStore, product *Product) ([]*Download, error) {
+	if product == nil {
+		return nil, fmt.Errorf("product is required")
+	}
+
+	type item struct {
+		url   string
+		types []string
+	}
+	var items []item
+	seen := make(map[string]bool)
+	add := func(url string, types []string) {
+		if url != "" && !seen[url] {
+			seen[url] = true
+			items = append(items, item{url, types})
+		}
+	}
+	add(product.PdfUrl, DatasheetTypes)
+	add(product.ProductImageUrl, ImageTypes)
+	for _, url := range product.ProductImages {
+		add(url, ImageTypes)
+	}
+
+	downloads := make([]*Download, 0, len(items))
+	for _, it := range items {
+		d, err := c.Mirror(ctx, store, it.url, DownloadOptions{ContentTypes: it.types})
+		if err != nil {
+			return downloads, fmt.Errorf("%s: %w", it.url, err)
+		}
+		downloads = append(downloads, d)
+	}
+	return downloads, nil
+}
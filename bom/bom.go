@@ -0,0 +1,50 @@
+// Package bom provides bill of materials types and costing on top of the
+// go-lcsc client.
+//
+// # Usage
+//
+//	b := &bom.BOM{Lines: []bom.Line{
+//	    {Designators: []string{"C1", "C2"}, LCSCCode: "C14663"},
+//	    {Designators: []string{"U1"}, MPN: "STM32F103C8T6", Manufacturer: "ST"},
+//	}}
+//
+//	costing, err := bom.Cost(ctx, client, b, bom.CostOptions{BuildQuantity: 50})
+//	fmt.Printf("Total: %s%.2f\n", costing.CurrencySymbol, costing.Total)
+package bom
+
+import "strings"
+
+// Line is a single BOM line: one part placed at one or more designators.
+type Line struct {
+	Designators  []string // Reference designators like "R1", "R2"
+	Quantity     int      // Quantity per board (default: number of designators)
+	LCSCCode     string   // LCSC part number like "C12345"
+	MPN          string   // Manufacturer part number, used when LCSCCode is empty
+	Manufacturer string   // Manufacturer name, used to disambiguate MPN matches
+	Value        string   // Component value or comment, like "100nF"
+	Footprint    string   // Footprint or package name
+	Description  string   // Free-form description
+}
+
+// QuantityPerBoard returns the number of parts needed for one board.
+func (l Line) QuantityPerBoard() int {
+	if l.Quantity > 0 {
+		return l.Quantity
+	}
+	return len(l.Designators)
+}
+
+// Key returns the identifier used to look the line up: the LCSC code if set,
+// otherwise the MPN.
+func (l Line) Key() string {
+	if code := strings.TrimSpace(l.LCSCCode); code != "" {
+		return strings.ToUpper(code)
+	}
+	return strings.TrimSpace(l.MPN)
+}
+
+// BOM is a bill of materials for one board.
+type BOM struct {
+	Name  string
+	Lines []Line
+}
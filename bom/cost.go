@@ -0,0 +1,262 @@
+package bom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	lcsc "github.com/PatrickWalther/go-lcsc"
+)
+
+// DefaultMinConfidence is the minimum MPN match confidence accepted when
+// resolving lines without an LCSC code.
+const DefaultMinConfidence = 0.85
+
+// ErrNoMatch is returned for lines whose MPN has no sufficiently confident match.
+var ErrNoMatch = errors.New("bom: no matching LCSC product")
+
+// LineStatus describes the availability of a costed line.
+type LineStatus string
+
+const (
+	StatusOK                LineStatus = "ok"                 // Enough stock at a known price
+	StatusInsufficientStock LineStatus = "insufficient_stock" // Some stock, but less than the order quantity
+	StatusOutOfStock        LineStatus = "out_of_stock"       // No stock
+	StatusNoPricing         LineStatus = "no_pricing"         // Product is in stock but has no price breaks
+	StatusUnresolved        LineStatus = "unresolved"         // Line could not be mapped to an LCSC product
+)
+
+// CostOptions configures Cost.
+type CostOptions struct {
+	BuildQuantity int     // Number of boards to build (required)
+	MinConfidence float64 // Minimum MPN match confidence (default: DefaultMinConfidence)
+}
+
+// CostedLine is a BOM line resolved to an LCSC product and priced.
+type CostedLine struct {
+	Line          Line
+	Product       *lcsc.Product // Nil when the line is unresolved
+	Confidence    float64       // 1 for LCSC codes, MPN match confidence otherwise
+	RequiredQty   int           // Quantity per board times build quantity
+	OrderQty      int           // RequiredQty raised to the minimum order and rounded to the packet size
+	BelowMinimum  bool          // RequiredQty is below the minimum order, so OrderQty buys spares
+	PriceTier     int           // Ladder of the price break applied at OrderQty
+	UnitPrice     float64       // Unit price at OrderQty
+	ExtendedPrice float64       // UnitPrice times OrderQty
+	Status        LineStatus
+	Err           error // Resolution error, set when Status is StatusUnresolved
+}
+
+// Costing is the result of pricing a BOM.
+type Costing struct {
+	BOM            *BOM
+	BuildQuantity  int
+	Currency       string // Currency code of the client, like "USD"
+	CurrencySymbol string // Currency symbol reported by LCSC, like "US$"
+	Lines          []CostedLine
+	Total          float64 // Sum of all extended prices
+}
+
+// PerBoard returns the total cost divided by the build quantity.
+func (c *Costing) PerBoard() float64 {
+	if c.BuildQuantity <= 0 {
+		return 0
+	}
+	return c.Total / float64(c.BuildQuantity)
+}
+
+// Convert returns a copy of the costing with unit prices, extended prices
+// and the total converted to currency using rates. Line products keep their
+// price breaks in the original currency. Converting to the costing's own
+// currency needs no rates.
+func (c *Costing) Convert(rates *lcsc.RateTable, currency string) (*Costing, error) {
+	if strings.EqualFold(strings.TrimSpace(currency), c.Currency) {
+		converted := *c
+		converted.Lines = append([]CostedLine(nil), c.Lines...)
+		return &converted, nil
+	}
+	if rates == nil {
+		return nil, fmt.Errorf("%w: no rate table", lcsc.ErrNoRate)
+	}
+	rate, err := rates.Rate(c.Currency, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	converted := *c
+	converted.Currency = strings.ToUpper(strings.TrimSpace(currency))
+	converted.CurrencySymbol = converted.Currency
+	if cur, ok := lcsc.LookupCurrency(currency); ok {
+		converted.CurrencySymbol = cur.Symbol
+	}
+	converted.Lines = make([]CostedLine, len(c.Lines))
+	converted.Total = 0
+	for i, line := range c.Lines {
+		line.UnitPrice *= rate
+		line.ExtendedPrice *= rate
+		converted.Total += line.ExtendedPrice
+		converted.Lines[i] = line
+	}
+	return &converted, nil
+}
+
+// Problems returns the lines whose status is not StatusOK.
+func (c *Costing) Problems() []CostedLine {
+	var problems []CostedLine
+	for _, line := range c.Lines {
+		if line.Status != StatusOK {
+			problems = append(problems, line)
+		}
+	}
+	return problems
+}
+
+// Cost resolves every line of b through client and prices it for the build
+// quantity. Lines with an LCSC code are fetched directly; other lines are
+// resolved by MPN with Client.ResolveMPN. Lines that cannot be resolved are
+// reported with StatusUnresolved rather than failing the whole BOM; only
+// context cancellation aborts costing.
+func Cost(ctx context.Context, client *lcsc.Client, b *BOM, opts CostOptions) (*Costing, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+	if b == nil {
+		return nil, fmt.Errorf("bom is required")
+	}
+	if opts.BuildQuantity <= 0 {
+		return nil, fmt.Errorf("build quantity must be positive")
+	}
+	if opts.MinConfidence <= 0 {
+		opts.MinConfidence = DefaultMinConfidence
+	}
+
+	costing := &Costing{
+		BOM:           b,
+		BuildQuantity: opts.BuildQuantity,
+		Currency:      client.Currency(),
+		Lines:         make([]CostedLine, 0, len(b.Lines)),
+	}
+
+	for _, line := range b.Lines {
+		product, confidence, err := resolveLine(ctx, client, line, opts.MinConfidence)
+		if err != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		cl := priceLine(line, product, line.QuantityPerBoard()*opts.BuildQuantity)
+		cl.Confidence = confidence
+		if err != nil {
+			cl.Status, cl.Err = StatusUnresolved, err
+		}
+
+		if costing.CurrencySymbol == "" && product != nil && len(product.ProductPriceList) > 0 {
+			costing.CurrencySymbol = product.ProductPriceList[0].CurrencySymbol
+		}
+		costing.Total += cl.ExtendedPrice
+		costing.Lines = append(costing.Lines, cl)
+	}
+
+	return costing, nil
+}
+
+// resolveLine maps a BOM line to an LCSC product.
+func resolveLine(ctx context.Context, client *lcsc.Client, line Line, minConfidence float64) (*lcsc.Product, float64, error) {
+	if code := strings.TrimSpace(line.LCSCCode); code != "" {
+		product, err := client.GetProductDetails(ctx, code)
+		if err != nil {
+			return nil, 0, err
+		}
+		return product, 1, nil
+	}
+
+	if strings.TrimSpace(line.MPN) == "" {
+		return nil, 0, fmt.Errorf("line %s has neither LCSC code nor MPN", strings.Join(line.Designators, ","))
+	}
+
+	candidates, err := client.ResolveMPN(ctx, line.MPN, line.Manufacturer)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(candidates) == 0 || candidates[0].Confidence < minConfidence {
+		return nil, 0, fmt.Errorf("%w for %q", ErrNoMatch, line.MPN)
+	}
+	best := candidates[0]
+	return &best.Product, best.Confidence, nil
+}
+
+// priceLine computes order quantity, price and stock status for a line.
+func priceLine(line Line, product *lcsc.Product, required int) CostedLine {
+	cl := CostedLine{
+		Line:        line,
+		Product:     product,
+		RequiredQty: required,
+		Status:      StatusUnresolved,
+	}
+	if product == nil {
+		return cl
+	}
+
+	cl.OrderQty = productOrderQuantity(product, required)
+	cl.BelowMinimum = required > 0 && required < minimumOrder(product)
+	cl.Status = stockStatus(product, cl.OrderQty)
+
+	pb, ok := product.PriceAt(cl.OrderQty)
+	if !ok {
+		// Stock problems are reported first; a price is moot without stock.
+		if cl.Status == StatusOK {
+			cl.Status = StatusNoPricing
+		}
+		return cl
+	}
+	cl.PriceTier = int(pb.Ladder)
+	cl.UnitPrice = float64(pb.ProductPrice)
+	cl.ExtendedPrice = cl.UnitPrice * float64(cl.OrderQty)
+	return cl
+}
+
+// minimumOrder returns the smallest quantity LCSC sells of a product: its
+// first price break or its packet size, whichever is larger.
+func minimumOrder(product *lcsc.Product) int {
+	first := 0
+	for i, pb := range product.ProductPriceList {
+		if i == 0 || int(pb.Ladder) < first {
+			first = int(pb.Ladder)
+		}
+	}
+	return max(first, int(product.MinPacketNumber), 1)
+}
+
+// productOrderQuantity returns the quantity to order for required parts:
+// at least the minimum order, rounded up to a multiple of the packet size.
+func productOrderQuantity(product *lcsc.Product, required int) int {
+	if required <= 0 {
+		return 0
+	}
+	return orderQuantity(max(required, minimumOrder(product)), int(product.MinPacketNumber))
+}
+
+// orderQuantity rounds required up to a whole multiple of the minimum order
+// quantity. LCSC sells cut tape and bags in multiples of the packet size.
+func orderQuantity(required, moq int) int {
+	if required <= 0 {
+		return 0
+	}
+	if moq <= 1 {
+		return required
+	}
+	return (required + moq - 1) / moq * moq
+}
+
+// stockStatus classifies product stock against an order quantity.
+func stockStatus(product *lcsc.Product, qty int) LineStatus {
+	stock := int(product.StockNumber)
+	switch {
+	case stock <= 0:
+		return StatusOutOfStock
+	case stock < qty:
+		return StatusInsufficientStock
+	default:
+		return StatusOK
+	}
+}
@@ -0,0 +1,282 @@
+package bom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	lcsc "github.com/PatrickWalther/go-lcsc"
+)
+
+// testProducts are served by newTestClient keyed by product code.
+var testProducts = map[string]lcsc.Product{
+	"C1": {
+		ProductCode:     "C1",
+		ProductModel:    "CL10B104KB8NNNC",
+		BrandNameEn:     "Samsung Electro-Mechanics",
+		EncapStandard:   "0603",
+		StockNumber:     100000,
+		MinPacketNumber: 100,
+		ProductPriceList: []lcsc.PriceBreak{
+			{Ladder: 100, ProductPrice: 0.002, CurrencySymbol: "US$"},
+			{Ladder: 1000, ProductPrice: 0.001, CurrencySymbol: "US$"},
+		},
+	},
+	"C2": {
+		ProductCode:      "C2",
+		ProductModel:     "STM32F103C8T6",
+		BrandNameEn:      "ST(STMicroelectronics)",
+		EncapStandard:    "LQFP-48",
+		StockNumber:      30,
+		MinPacketNumber:  1,
+		ProductPriceList: []lcsc.PriceBreak{{Ladder: 1, ProductPrice: 2.5, CurrencySymbol: "US$"}},
+	},
+	"C3": {
+		ProductCode:      "C3",
+		ProductModel:     "NE555P",
+		StockNumber:      0,
+		MinPacketNumber:  1,
+		ProductPriceList: []lcsc.PriceBreak{{Ladder: 1, ProductPrice: 0.3, CurrencySymbol: "US$"}},
+	},
+}
+
+// newTestClient returns a client backed by a stand-in LCSC server serving
+// testProducts for detail lookups and MPN searches.
+func newTestClient(t *testing.T, opts ...lcsc.ClientOption) *lcsc.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var result interface{}
+		switch r.URL.Path {
+		case "/product/detail":
+			product, ok := testProducts[r.URL.Query().Get("productCode")]
+			if !ok {
+				writeEnvelope(w, 404, nil)
+				return
+			}
+			result = product
+		case "/search/v2/global":
+			var body struct {
+				Keyword string `json:"keyword"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			var list []lcsc.Product
+			for _, p := range testProducts {
+				if p.ProductModel == body.Keyword {
+					list = append(list, p)
+				}
+			}
+			result = map[string]interface{}{
+				"productSearchResultVO": map[string]interface{}{"productList": list, "totalCount": len(list)},
+			}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		writeEnvelope(w, 200, result)
+	}))
+	t.Cleanup(server.Close)
+
+	return lcsc.NewClient(append([]lcsc.ClientOption{
+		lcsc.WithBaseURL(server.URL),
+		lcsc.WithRetryConfig(lcsc.NoRetry()),
+		lcsc.WithRateLimit(1000),
+	}, opts...)...)
+}
+
+// writeEnvelope writes result in the LCSC response envelope.
+func writeEnvelope(w http.ResponseWriter, code int, result interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": code, "msg": "", "result": result})
+}
+
+// TestLineQuantityPerBoard tests the designator-count default.
+func TestLineQuantityPerBoard(t *testing.T) {
+	if q := (Line{Designators: []string{"R1", "R2", "R3"}}).QuantityPerBoard(); q != 3 {
+		t.Errorf("expected 3, got %d", q)
+	}
+	if q := (Line{Designators: []string{"R1"}, Quantity: 2}).QuantityPerBoard(); q != 2 {
+		t.Errorf("expected explicit quantity 2, got %d", q)
+	}
+}
+
+// TestOrderQuantity tests rounding to the minimum order quantity.
+func TestOrderQuantity(t *testing.T) {
+	tests := []struct {
+		required, moq, expected int
+	}{
+		{0, 100, 0},
+		{1, 100, 100},
+		{100, 100, 100},
+		{101, 100, 200},
+		{7, 1, 7},
+		{7, 0, 7},
+	}
+
+	for _, test := range tests {
+		if got := orderQuantity(test.required, test.moq); got != test.expected {
+			t.Errorf("orderQuantity(%d, %d) = %d, expected %d", test.required, test.moq, got, test.expected)
+		}
+	}
+}
+
+// TestPriceLine tests minimum orders and status precedence.
+func TestPriceLine(t *testing.T) {
+	ladder := &lcsc.Product{
+		StockNumber:      1000,
+		MinPacketNumber:  5,
+		ProductPriceList: []lcsc.PriceBreak{{Ladder: 20, ProductPrice: 0.5}, {Ladder: 100, ProductPrice: 0.4}},
+	}
+	tests := []struct {
+		name         string
+		product      *lcsc.Product
+		required     int
+		orderQty     int
+		belowMinimum bool
+		status       LineStatus
+	}{
+		{"below first break", ladder, 3, 20, true, StatusOK},
+		{"at first break", ladder, 20, 20, false, StatusOK},
+		{"packet rounding", ladder, 21, 25, false, StatusOK},
+		{"below packet size", &lcsc.Product{StockNumber: 1000, MinPacketNumber: 100,
+			ProductPriceList: []lcsc.PriceBreak{{Ladder: 1, ProductPrice: 0.1}}}, 50, 100, true, StatusOK},
+		{"no pricing", &lcsc.Product{StockNumber: 10}, 5, 5, false, StatusNoPricing},
+		{"no pricing or stock", &lcsc.Product{}, 5, 5, false, StatusOutOfStock},
+		{"no pricing, low stock", &lcsc.Product{StockNumber: 2}, 5, 5, false, StatusInsufficientStock},
+	}
+
+	for _, test := range tests {
+		cl := priceLine(Line{}, test.product, test.required)
+		if cl.OrderQty != test.orderQty || cl.BelowMinimum != test.belowMinimum || cl.Status != test.status {
+			t.Errorf("%s: got order %d, below minimum %v, status %s; expected %d, %v, %s", test.name,
+				cl.OrderQty, cl.BelowMinimum, cl.Status, test.orderQty, test.belowMinimum, test.status)
+		}
+	}
+	if cl := priceLine(Line{}, ladder, 3); cl.UnitPrice != 0.5 || cl.ExtendedPrice != 10 {
+		t.Errorf("expected 20 units at 0.5, got %+v", cl)
+	}
+}
+
+// TestCost tests resolving and pricing a BOM.
+func TestCost(t *testing.T) {
+	client := newTestClient(t)
+
+	b := &BOM{Lines: []Line{
+		{Designators: []string{"C1", "C2", "C3", "C4"}, LCSCCode: "C1"},
+		{Designators: []string{"U1"}, MPN: "STM32F103C8T6", Manufacturer: "STMicroelectronics"},
+		{Designators: []string{"U2"}, LCSCCode: "C3"},
+		{Designators: []string{"U3"}, MPN: "DOES-NOT-EXIST"},
+		{Designators: []string{"X1"}, LCSCCode: "C999"},
+	}}
+
+	costing, err := Cost(context.Background(), client, b, CostOptions{BuildQuantity: 50})
+	if err != nil {
+		t.Fatalf("Cost failed: %v", err)
+	}
+
+	if costing.Currency != "USD" || costing.CurrencySymbol != "US$" {
+		t.Errorf("unexpected currency %s / %s", costing.Currency, costing.CurrencySymbol)
+	}
+
+	caps := costing.Lines[0]
+	if caps.RequiredQty != 200 || caps.OrderQty != 200 || caps.UnitPrice != 0.002 || caps.Status != StatusOK {
+		t.Errorf("unexpected capacitor line: %+v", caps)
+	}
+
+	mcu := costing.Lines[1]
+	if mcu.Product == nil || mcu.Product.ProductCode != "C2" {
+		t.Fatalf("expected MPN to resolve to C2, got %+v", mcu)
+	}
+	if mcu.OrderQty != 50 || mcu.Status != StatusInsufficientStock {
+		t.Errorf("expected insufficient stock for 50 MCUs, got %+v", mcu)
+	}
+
+	if costing.Lines[2].Status != StatusOutOfStock {
+		t.Errorf("expected out of stock, got %s", costing.Lines[2].Status)
+	}
+
+	if costing.Lines[3].Status != StatusUnresolved || !errors.Is(costing.Lines[3].Err, ErrNoMatch) {
+		t.Errorf("expected unresolved MPN, got %+v", costing.Lines[3])
+	}
+
+	if costing.Lines[4].Status != StatusUnresolved || !errors.Is(costing.Lines[4].Err, lcsc.ErrProductNotFound) {
+		t.Errorf("expected unresolved code, got %+v", costing.Lines[4])
+	}
+
+	expectedTotal := 200*0.002 + 50*2.5 + 50*0.3
+	if diff := costing.Total - expectedTotal; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("expected total %f, got %f", expectedTotal, costing.Total)
+	}
+	if diff := costing.PerBoard() - expectedTotal/50; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("unexpected per-board cost %f", costing.PerBoard())
+	}
+
+	if len(costing.Problems()) != 4 {
+		t.Errorf("expected 4 problem lines, got %d", len(costing.Problems()))
+	}
+}
+
+// TestCostInvalidOptions tests argument validation.
+func TestCostInvalidOptions(t *testing.T) {
+	client := lcsc.NewClient()
+
+	if _, err := Cost(context.Background(), client, &BOM{}, CostOptions{}); err == nil {
+		t.Error("expected error for zero build quantity")
+	}
+	if _, err := Cost(context.Background(), nil, &BOM{}, CostOptions{BuildQuantity: 1}); err == nil {
+		t.Error("expected error for nil client")
+	}
+	if _, err := Cost(context.Background(), client, nil, CostOptions{BuildQuantity: 1}); err == nil {
+		t.Error("expected error for nil BOM")
+	}
+}
+
+// TestCostingConvert tests converting a costing to another currency.
+func TestCostingConvert(t *testing.T) {
+	costing := &Costing{
+		Currency:       "USD",
+		CurrencySymbol: "US$",
+		Lines: []CostedLine{
+			{UnitPrice: 1, ExtendedPrice: 10},
+			{UnitPrice: 2, ExtendedPrice: 20},
+		},
+		Total: 30,
+	}
+	rates := &lcsc.RateTable{Base: "USD", Rates: map[string]float64{"USD": 1, "EUR": 0.9}}
+
+	eur, err := costing.Convert(rates, "EUR")
+	if err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+	if eur.Currency != "EUR" || eur.CurrencySymbol != "€" {
+		t.Errorf("unexpected currency %s / %s", eur.Currency, eur.CurrencySymbol)
+	}
+	if math.Abs(eur.Total-27) > 1e-9 || math.Abs(eur.Lines[1].UnitPrice-1.8) > 1e-9 {
+		t.Errorf("unexpected converted costing: %+v", eur)
+	}
+	if costing.Total != 30 {
+		t.Error("expected the original costing to be unchanged")
+	}
+	if _, err := costing.Convert(rates, "GBP"); !errors.Is(err, lcsc.ErrNoRate) {
+		t.Errorf("expected ErrNoRate, got %v", err)
+	}
+
+	// Without rates, only the costing's own currency converts.
+	if _, err := costing.Convert(nil, "EUR"); !errors.Is(err, lcsc.ErrNoRate) {
+		t.Errorf("expected ErrNoRate for a nil table, got %v", err)
+	}
+	usd, err := costing.Convert(nil, " usd ")
+	if err != nil {
+		t.Fatalf("Convert to the same currency failed: %v", err)
+	}
+	if usd.Total != 30 || usd.CurrencySymbol != "US$" || len(usd.Lines) != 2 {
+		t.Errorf("unexpected same-currency costing: %+v", usd)
+	}
+	usd.Lines[0].UnitPrice = 5
+	if costing.Lines[0].UnitPrice != 1 {
+		t.Error("expected the original lines to be unchanged")
+	}
+}
@@ -0,0 +1,340 @@
+package bom
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteJLCPCB writes a costed BOM in JLCPCB's assembly BOM format
+// (Comment, Designator, Footprint, LCSC Part #). Unresolved lines are
+// written with an empty LCSC part number so JLCPCB flags them for review.
+func WriteJLCPCB(w io.Writer, c *Costing) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Comment", "Designator", "Footprint", "LCSC Part #"}); err != nil {
+		return err
+	}
+	for _, line := range c.Lines {
+		record := []string{
+			line.comment(),
+			strings.Join(line.Line.Designators, ","),
+			line.footprint(),
+			line.productCode(),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteLCSCCart writes a costed BOM as an LCSC cart bulk-upload file: one
+// "code,quantity" line per resolved part using the order quantity, without
+// a header. Unresolved lines are omitted.
+func WriteLCSCCart(w io.Writer, c *Costing) error {
+	cw := csv.NewWriter(w)
+	for _, line := range c.Lines {
+		if line.Product == nil || line.OrderQty <= 0 {
+			continue
+		}
+		if err := cw.Write([]string{line.Product.ProductCode, strconv.Itoa(line.OrderQty)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvHeader lists the columns written by WriteCSV.
+var csvHeader = []string{
+	"Designators", "Qty Per Board", "Required Qty", "Order Qty", "LCSC Part", "MPN", "Manufacturer",
+	"Package", "Price Tier", "Unit Price", "Extended Price", "Currency", "Stock", "Status",
+}
+
+// WriteCSV writes a costed BOM as plain CSV with pricing and stock columns.
+func WriteCSV(w io.Writer, c *Costing) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, line := range c.Lines {
+		record := []string{
+			strings.Join(line.Line.Designators, ","),
+			strconv.Itoa(line.Line.QuantityPerBoard()),
+			strconv.Itoa(line.RequiredQty),
+			strconv.Itoa(line.OrderQty),
+			line.productCode(),
+			line.mpn(),
+			line.manufacturer(),
+			line.footprint(),
+			strconv.Itoa(line.PriceTier),
+			strconv.FormatFloat(line.UnitPrice, 'f', -1, 64),
+			strconv.FormatFloat(line.ExtendedPrice, 'f', 2, 64),
+			c.Currency,
+			line.stock(),
+			string(line.Status),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// jsonLine is the JSON representation of a costed line.
+type jsonLine struct {
+	Designators   []string   `json:"designators"`
+	QtyPerBoard   int        `json:"qtyPerBoard"`
+	RequiredQty   int        `json:"requiredQty"`
+	OrderQty      int        `json:"orderQty"`
+	BelowMinimum  bool       `json:"belowMinimum,omitempty"`
+	ProductCode   string     `json:"productCode,omitempty"`
+	MPN           string     `json:"mpn,omitempty"`
+	Manufacturer  string     `json:"manufacturer,omitempty"`
+	Package       string     `json:"package,omitempty"`
+	Confidence    float64    `json:"confidence"`
+	PriceTier     int        `json:"priceTier"`
+	UnitPrice     float64    `json:"unitPrice"`
+	ExtendedPrice float64    `json:"extendedPrice"`
+	Stock         int        `json:"stock"`
+	Status        LineStatus `json:"status"`
+	Error         string     `json:"error,omitempty"`
+}
+
+// jsonCosting is the JSON representation of a Costing.
+type jsonCosting struct {
+	Name           string     `json:"name,omitempty"`
+	BuildQuantity  int        `json:"buildQuantity"`
+	Currency       string     `json:"currency"`
+	CurrencySymbol string     `json:"currencySymbol,omitempty"`
+	Total          float64    `json:"total"`
+	PerBoard       float64    `json:"perBoard"`
+	Lines          []jsonLine `json:"lines"`
+}
+
+// WriteJSON writes a costed BOM as indented JSON.
+func WriteJSON(w io.Writer, c *Costing) error {
+	out := jsonCosting{
+		BuildQuantity:  c.BuildQuantity,
+		Currency:       c.Currency,
+		CurrencySymbol: c.CurrencySymbol,
+		Total:          c.Total,
+		PerBoard:       c.PerBoard(),
+		Lines:          make([]jsonLine, 0, len(c.Lines)),
+	}
+	if c.BOM != nil {
+		out.Name = c.BOM.Name
+	}
+	for _, line := range c.Lines {
+		jl := jsonLine{
+			Designators:   line.Line.Designators,
+			QtyPerBoard:   line.Line.QuantityPerBoard(),
+			RequiredQty:   line.RequiredQty,
+			OrderQty:      line.OrderQty,
+			BelowMinimum:  line.BelowMinimum,
+			ProductCode:   line.productCode(),
+			MPN:           line.mpn(),
+			Manufacturer:  line.manufacturer(),
+			Package:       line.footprint(),
+			Confidence:    line.Confidence,
+			PriceTier:     line.PriceTier,
+			UnitPrice:     line.UnitPrice,
+			ExtendedPrice: line.ExtendedPrice,
+			Status:        line.Status,
+		}
+		if line.Product != nil {
+			jl.Stock = int(line.Product.StockNumber)
+		}
+		if line.Err != nil {
+			jl.Error = line.Err.Error()
+		}
+		out.Lines = append(out.Lines, jl)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// WriteMarkdown writes a human-readable cost report as a Markdown table.
+func WriteMarkdown(w io.Writer, c *Costing) error {
+	var b strings.Builder
+
+	title := "BOM cost report"
+	if c.BOM != nil && c.BOM.Name != "" {
+		title += ": " + c.BOM.Name
+	}
+	fmt.Fprintf(&b, "# %s\n\n", markdownEscape(title))
+	fmt.Fprintf(&b, "Build quantity: %d, currency: %s\n\n", c.BuildQuantity, c.Currency)
+	b.WriteString("| Designators | LCSC Part | MPN | Manufacturer | Package | Order Qty | Price Tier | Unit Price | Extended | Status |\n")
+	b.WriteString("|---|---|---|---|---|--:|--:|--:|--:|---|\n")
+	for _, line := range c.Lines {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %d | %d | %s | %s | %s |\n",
+			markdownEscape(strings.Join(line.Line.Designators, ", ")),
+			markdownEscape(line.productCode()),
+			markdownEscape(line.mpn()),
+			markdownEscape(line.manufacturer()),
+			markdownEscape(line.footprint()),
+			line.OrderQty,
+			line.PriceTier,
+			formatPrice(c.CurrencySymbol, line.UnitPrice, 4),
+			formatPrice(c.CurrencySymbol, line.ExtendedPrice, 2),
+			line.Status,
+		)
+	}
+	fmt.Fprintf(&b, "\n**Total: %s** (%s per board)\n",
+		formatPrice(c.CurrencySymbol, c.Total, 2), formatPrice(c.CurrencySymbol, c.PerBoard(), 4))
+
+	if problems := c.Problems(); len(problems) > 0 {
+		b.WriteString("\n## Problems\n\n")
+		for _, line := range problems {
+			fmt.Fprintf(&b, "- %s: %s", markdownEscape(strings.Join(line.Line.Designators, ", ")), line.Status)
+			if line.Err != nil {
+				fmt.Fprintf(&b, " (%s)", markdownEscape(line.Err.Error()))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// htmlReport is the template used by WriteHTML.
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; }
+td.num { text-align: right; }
+tr.problem { background: #fdd; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>Build quantity: {{.BuildQuantity}}, currency: {{.Currency}}</p>
+<table>
+<tr><th>Designators</th><th>LCSC Part</th><th>MPN</th><th>Manufacturer</th><th>Package</th><th>Order Qty</th><th>Price Tier</th><th>Unit Price</th><th>Extended</th><th>Status</th></tr>
+{{- range .Lines}}
+<tr{{if .Problem}} class="problem"{{end}}><td>{{.Designators}}</td><td>{{.ProductCode}}</td><td>{{.MPN}}</td><td>{{.Manufacturer}}</td><td>{{.Package}}</td><td class="num">{{.OrderQty}}</td><td class="num">{{.PriceTier}}</td><td class="num">{{.UnitPrice}}</td><td class="num">{{.ExtendedPrice}}</td><td>{{.Status}}</td></tr>
+{{- end}}
+</table>
+<p><strong>Total: {{.Total}}</strong> ({{.PerBoard}} per board)</p>
+</body>
+</html>
+`))
+
+// WriteHTML writes a human-readable cost report as a standalone HTML page.
+func WriteHTML(w io.Writer, c *Costing) error {
+	type htmlLine struct {
+		Designators, ProductCode, MPN, Manufacturer, Package string
+		OrderQty, PriceTier                                  int
+		UnitPrice, ExtendedPrice                             string
+		Status                                               LineStatus
+		Problem                                              bool
+	}
+
+	data := struct {
+		Title, Currency, Total, PerBoard string
+		BuildQuantity                    int
+		Lines                            []htmlLine
+	}{
+		Title:         "BOM cost report",
+		Currency:      c.Currency,
+		Total:         formatPrice(c.CurrencySymbol, c.Total, 2),
+		PerBoard:      formatPrice(c.CurrencySymbol, c.PerBoard(), 4),
+		BuildQuantity: c.BuildQuantity,
+	}
+	if c.BOM != nil && c.BOM.Name != "" {
+		data.Title += ": " + c.BOM.Name
+	}
+	for _, line := range c.Lines {
+		data.Lines = append(data.Lines, htmlLine{
+			Designators:   strings.Join(line.Line.Designators, ", "),
+			ProductCode:   line.productCode(),
+			MPN:           line.mpn(),
+			Manufacturer:  line.manufacturer(),
+			Package:       line.footprint(),
+			OrderQty:      line.OrderQty,
+			PriceTier:     line.PriceTier,
+			UnitPrice:     formatPrice(c.CurrencySymbol, line.UnitPrice, 4),
+			ExtendedPrice: formatPrice(c.CurrencySymbol, line.ExtendedPrice, 2),
+			Status:        line.Status,
+			Problem:       line.Status != StatusOK,
+		})
+	}
+
+	return htmlReport.Execute(w, data)
+}
+
+// productCode returns the resolved LCSC code. It is empty for unresolved
+// lines, even if the line names a code, as that code was not found.
+func (l CostedLine) productCode() string {
+	if l.Product != nil {
+		return l.Product.ProductCode
+	}
+	return ""
+}
+
+// mpn returns the resolved manufacturer part number, or the line's own MPN.
+func (l CostedLine) mpn() string {
+	if l.Product != nil && l.Product.ProductModel != "" {
+		return l.Product.ProductModel
+	}
+	return l.Line.MPN
+}
+
+// manufacturer returns the resolved manufacturer, or the line's own.
+func (l CostedLine) manufacturer() string {
+	if l.Product != nil && l.Product.BrandNameEn != "" {
+		return l.Product.BrandNameEn
+	}
+	return l.Line.Manufacturer
+}
+
+// footprint returns the line's footprint, falling back to the product package.
+func (l CostedLine) footprint() string {
+	if l.Line.Footprint != "" {
+		return l.Line.Footprint
+	}
+	if l.Product != nil {
+		return l.Product.EncapStandard
+	}
+	return ""
+}
+
+// comment returns the line's value, falling back to the product MPN.
+func (l CostedLine) comment() string {
+	if l.Line.Value != "" {
+		return l.Line.Value
+	}
+	return l.mpn()
+}
+
+// stock returns the product stock as text, or "" when unresolved.
+func (l CostedLine) stock() string {
+	if l.Product == nil {
+		return ""
+	}
+	return strconv.Itoa(int(l.Product.StockNumber))
+}
+
+// formatPrice formats a price with a currency symbol and fixed precision.
+func formatPrice(symbol string, price float64, precision int) string {
+	return symbol + strconv.FormatFloat(price, 'f', precision, 64)
+}
+
+// markdownEscape escapes characters that would break a Markdown table cell.
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
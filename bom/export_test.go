@@ -0,0 +1,194 @@
+package bom
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	lcsc "github.com/PatrickWalther/go-lcsc"
+)
+
+// testCosting returns a small costed BOM for exporter tests.
+func testCosting() *Costing {
+	capacitor := &lcsc.Product{
+		ProductCode:   "C14663",
+		ProductModel:  "CC0603KRX7R9BB104",
+		BrandNameEn:   "YAGEO",
+		EncapStandard: "0603",
+		StockNumber:   5000000,
+	}
+	return &Costing{
+		BOM:            &BOM{Name: "board|v1"},
+		BuildQuantity:  10,
+		Currency:       "USD",
+		CurrencySymbol: "US$",
+		Lines: []CostedLine{
+			{
+				Line:          Line{Designators: []string{"C1", "C2"}, LCSCCode: "C14663", Value: "100nF"},
+				Product:       capacitor,
+				Confidence:    1,
+				RequiredQty:   20,
+				OrderQty:      100,
+				PriceTier:     100,
+				UnitPrice:     0.0016,
+				ExtendedPrice: 0.16,
+				Status:        StatusOK,
+			},
+			{
+				Line:        Line{Designators: []string{"U1"}, MPN: "XYZ123", Footprint: "SOIC-8"},
+				RequiredQty: 10,
+				Status:      StatusUnresolved,
+				Err:         errors.New("no match"),
+			},
+		},
+		Total: 0.16,
+	}
+}
+
+// TestWriteJLCPCB tests the JLCPCB assembly BOM exporter.
+func TestWriteJLCPCB(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJLCPCB(&buf, testCosting()); err != nil {
+		t.Fatalf("WriteJLCPCB failed: %v", err)
+	}
+
+	expected := "Comment,Designator,Footprint,LCSC Part #\n" +
+		"100nF,\"C1,C2\",0603,C14663\n" +
+		"XYZ123,U1,SOIC-8,\n"
+	if buf.String() != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+
+	b, err := ParseJLCPCB(&buf)
+	if err != nil {
+		t.Fatalf("ParseJLCPCB failed on exported BOM: %v", err)
+	}
+	if len(b.Lines) != 2 || b.Lines[0].LCSCCode != "C14663" || b.Lines[0].QuantityPerBoard() != 2 {
+		t.Errorf("round trip mismatch: %+v", b.Lines)
+	}
+}
+
+// TestWriteUnresolvedCode tests that unresolved lines export an empty LCSC
+// code even when the BOM names one.
+func TestWriteUnresolvedCode(t *testing.T) {
+	c := testCosting()
+	c.Lines[1].Line.LCSCCode = "C999999"
+
+	var buf bytes.Buffer
+	if err := WriteJLCPCB(&buf, c); err != nil {
+		t.Fatalf("WriteJLCPCB failed: %v", err)
+	}
+	if !strings.HasSuffix(buf.String(), "XYZ123,U1,SOIC-8,\n") {
+		t.Errorf("expected an empty code for the unresolved line:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteCSV(&buf, c); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "C999999") {
+		t.Errorf("expected no code for the unresolved line:\n%s", buf.String())
+	}
+}
+
+// TestWriteLCSCCart tests the LCSC cart bulk-upload exporter.
+func TestWriteLCSCCart(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteLCSCCart(&buf, testCosting()); err != nil {
+		t.Fatalf("WriteLCSCCart failed: %v", err)
+	}
+
+	if buf.String() != "C14663,100\n" {
+		t.Errorf("unexpected output: %q", buf.String())
+	}
+}
+
+// TestWriteCSV tests the plain CSV exporter.
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, testCosting()); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], "Designators,Qty Per Board,") {
+		t.Errorf("unexpected header: %s", lines[0])
+	}
+	expected := `"C1,C2",2,20,100,C14663,CC0603KRX7R9BB104,YAGEO,0603,100,0.0016,0.16,USD,5000000,ok`
+	if lines[1] != expected {
+		t.Errorf("unexpected row:\n%s\nexpected:\n%s", lines[1], expected)
+	}
+}
+
+// TestWriteJSON tests the JSON exporter.
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, testCosting()); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+
+	var out jsonCosting
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if out.Name != "board|v1" || out.Currency != "USD" || len(out.Lines) != 2 {
+		t.Errorf("unexpected output: %+v", out)
+	}
+	if out.Lines[0].PriceTier != 100 || out.Lines[0].Stock != 5000000 {
+		t.Errorf("unexpected first line: %+v", out.Lines[0])
+	}
+	if out.Lines[1].Error != "no match" || out.Lines[1].Status != StatusUnresolved {
+		t.Errorf("unexpected second line: %+v", out.Lines[1])
+	}
+}
+
+// TestWriteMarkdown tests the Markdown report.
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, testCosting()); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"# BOM cost report: board\\|v1",
+		"| C1, C2 | C14663 | CC0603KRX7R9BB104 | YAGEO | 0603 | 100 | 100 | US$0.0016 | US$0.16 | ok |",
+		"**Total: US$0.16** (US$0.0160 per board)",
+		"## Problems",
+		"- U1: unresolved (no match)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q:\n%s", want, out)
+		}
+	}
+}
+
+// TestWriteHTML tests the HTML report.
+func TestWriteHTML(t *testing.T) {
+	c := testCosting()
+	c.Lines[1].Line.MPN = "<script>"
+
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, c); err != nil {
+		t.Fatalf("WriteHTML failed: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"<title>BOM cost report: board|v1</title>",
+		"<td>C14663</td>",
+		`<tr class="problem">`,
+		"&lt;script&gt;",
+		"<strong>Total: US$0.16</strong>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package bom
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	lcsc "github.com/PatrickWalther/go-lcsc"
+)
+
+// ErrNoHeader is returned when a CSV file has no recognizable header row.
+var ErrNoHeader = errors.New("bom: no header row with a designator or quantity column")
+
+// ColumnMapping maps CSV header names to Line fields. Each field lists the
+// accepted header names; headers are matched case-insensitively, ignoring
+// spaces and punctuation, so "LCSC Part #" matches "lcsc part".
+type ColumnMapping struct {
+	Designator   []string
+	Quantity     []string
+	LCSCCode     []string
+	MPN          []string
+	Manufacturer []string
+	Value        []string
+	Footprint    []string
+	Description  []string
+	DNP          []string // Rows with a non-empty, non-false value are skipped
+	Comma        rune     // Field delimiter (default ',')
+}
+
+// DefaultColumnMapping returns a mapping that recognizes the column names
+// commonly produced by EDA tools and distributors.
+func DefaultColumnMapping() ColumnMapping {
+	return ColumnMapping{
+		Designator:   []string{"Designator", "Designators", "Reference", "References", "Ref", "Refs", "RefDes"},
+		Quantity:     []string{"Quantity", "Qty", "Qnty", "Count"},
+		LCSCCode:     []string{"LCSC", "LCSC Part", "LCSC Part #", "LCSC Part Number", "JLCPCB Part #", "JLCPCB Part"},
+		MPN:          []string{"MPN", "Manufacturer Part Number", "Manufacturer Part", "Mfr Part #", "Mfr. Part #", "Part Number", "PN"},
+		Manufacturer: []string{"Manufacturer", "Mfr", "Mfr.", "Manufacturer Name", "Brand"},
+		Value:        []string{"Value", "Comment", "Val"},
+		Footprint:    []string{"Footprint", "Package", "Case"},
+		Description:  []string{"Description", "Desc", "Cmp name"},
+		DNP:          []string{"DNP", "Do Not Populate", "Exclude from BOM"},
+	}
+}
+
+// KiCadColumnMapping returns the mapping for KiCad's BOM CSV exports, both
+// the Symbol Fields Table export and the legacy grouped-by-value script.
+func KiCadColumnMapping() ColumnMapping {
+	m := DefaultColumnMapping()
+	m.Designator = []string{"Reference", "References", "Ref"}
+	m.Quantity = []string{"Qty", "Quantity", "Qnty", "Quantity Per PCB"}
+	return m
+}
+
+// JLCPCBColumnMapping returns the mapping for JLCPCB's assembly BOM format
+// (Comment, Designator, Footprint, LCSC Part #).
+func JLCPCBColumnMapping() ColumnMapping {
+	m := DefaultColumnMapping()
+	m.Designator = []string{"Designator"}
+	m.Value = []string{"Comment"}
+	m.LCSCCode = []string{"LCSC Part #", "LCSC Part Number", "LCSC", "JLCPCB Part #"}
+	return m
+}
+
+// ParseCSV reads a CSV BOM using mapping. Rows before the first header row
+// containing a designator or quantity column are skipped, which tolerates
+// the title lines some tools emit. Lines for the same part are grouped.
+func ParseCSV(r io.Reader, mapping ColumnMapping) (*BOM, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+	if mapping.Comma != 0 {
+		reader.Comma = mapping.Comma
+	}
+
+	var columns map[string]int
+	b := &BOM{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		if columns == nil {
+			columns = mapColumns(record, mapping)
+			continue
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+
+		get := func(field string) string {
+			idx, ok := columns[field]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+
+		if isDNP(get("dnp")) {
+			continue
+		}
+
+		line := Line{
+			Designators:  splitDesignators(get("designator")),
+			LCSCCode:     get("lcsc"),
+			MPN:          get("mpn"),
+			Manufacturer: get("manufacturer"),
+			Value:        get("value"),
+			Footprint:    get("footprint"),
+			Description:  get("description"),
+		}
+		if qty := get("quantity"); qty != "" {
+			n, err := strconv.Atoi(qty)
+			if err != nil {
+				return nil, fmt.Errorf("invalid quantity %q on line %v: %w", qty, line.Designators, err)
+			}
+			line.Quantity = n
+		}
+		if line.QuantityPerBoard() == 0 {
+			continue
+		}
+		b.Lines = append(b.Lines, line)
+	}
+
+	if columns == nil {
+		return nil, ErrNoHeader
+	}
+
+	b.Lines = GroupLines(b.Lines)
+	return b, nil
+}
+
+// ParseKiCadCSV reads a BOM exported from KiCad as CSV.
+func ParseKiCadCSV(r io.Reader) (*BOM, error) {
+	return ParseCSV(r, KiCadColumnMapping())
+}
+
+// ParseJLCPCB reads a BOM in JLCPCB's assembly format.
+func ParseJLCPCB(r io.Reader) (*BOM, error) {
+	return ParseCSV(r, JLCPCBColumnMapping())
+}
+
+// kicadExport matches the parts of KiCad's XML netlist/BOM export used here.
+type kicadExport struct {
+	Design struct {
+		Source string `xml:"source"`
+	} `xml:"design"`
+	Components []struct {
+		Ref       string `xml:"ref,attr"`
+		Value     string `xml:"value"`
+		Footprint string `xml:"footprint"`
+		Fields    []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"fields>field"`
+		Properties []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:"value,attr"`
+		} `xml:"property"`
+		LibSource struct {
+			Description string `xml:"description,attr"`
+		} `xml:"libsource"`
+	} `xml:"components>comp"`
+}
+
+// ParseKiCadXML reads KiCad's intermediate XML export (the input to KiCad's
+// BOM scripts). Custom symbol fields are matched with KiCadColumnMapping;
+// components marked DNP or excluded from the BOM are skipped.
+func ParseKiCadXML(r io.Reader) (*BOM, error) {
+	var export kicadExport
+	if err := xml.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("failed to parse KiCad XML: %w", err)
+	}
+
+	mapping := KiCadColumnMapping()
+	b := &BOM{Name: export.Design.Source}
+	for _, comp := range export.Components {
+		fields := make(map[string]string)
+		for _, f := range comp.Fields {
+			fields[headerKey(f.Name)] = strings.TrimSpace(f.Value)
+		}
+
+		skip := false
+		for _, p := range comp.Properties {
+			switch headerKey(p.Name) {
+			case "dnp", "excludefrombom":
+				skip = true
+			}
+		}
+		if skip || isDNP(lookupField(fields, mapping.DNP)) {
+			continue
+		}
+
+		line := Line{
+			Designators:  []string{comp.Ref},
+			LCSCCode:     lookupField(fields, mapping.LCSCCode),
+			MPN:          lookupField(fields, mapping.MPN),
+			Manufacturer: lookupField(fields, mapping.Manufacturer),
+			Value:        strings.TrimSpace(comp.Value),
+			Footprint:    strings.TrimSpace(comp.Footprint),
+			Description:  lookupField(fields, mapping.Description),
+		}
+		if line.Description == "" {
+			line.Description = comp.LibSource.Description
+		}
+		b.Lines = append(b.Lines, line)
+	}
+
+	b.Lines = GroupLines(b.Lines)
+	return b, nil
+}
+
+// GroupLines merges lines that describe the same part, combining their
+// designators and quantities. Lines are matched by LCSC code, then by
+// normalized MPN and manufacturer, and finally by value and footprint.
+// Lines with none of these are never merged. The order of first appearance
+// is preserved.
+func GroupLines(lines []Line) []Line {
+	index := make(map[string]int)
+	var grouped []Line
+	for i, line := range lines {
+		key := groupKey(line, i)
+		gi, ok := index[key]
+		if !ok {
+			index[key] = len(grouped)
+			grouped = append(grouped, line)
+			continue
+		}
+		g := &grouped[gi]
+		g.Quantity = g.QuantityPerBoard() + line.QuantityPerBoard()
+		g.Designators = append(append([]string(nil), g.Designators...), line.Designators...)
+	}
+	return grouped
+}
+
+// groupKey returns the identity of the part on the line at index i. Lines
+// that do not identify a part are keyed by their index.
+func groupKey(l Line, i int) string {
+	if code := strings.TrimSpace(l.LCSCCode); code != "" {
+		return "lcsc:" + strings.ToUpper(code)
+	}
+	if mpn := strings.TrimSpace(l.MPN); mpn != "" {
+		return "mpn:" + lcsc.NormalizeMPN(mpn) + ":" + headerKey(lcsc.CanonicalManufacturer(l.Manufacturer))
+	}
+	value, footprint := strings.TrimSpace(l.Value), strings.TrimSpace(l.Footprint)
+	if value == "" && footprint == "" {
+		return "line:" + strconv.Itoa(i)
+	}
+	return "value:" + value + ":" + footprint
+}
+
+// mapColumns returns the column index of each mapped field present in
+// header, preferring earlier names in the mapping, or nil if header has neither a designator nor a quantity column.
+func mapColumns(header []string, mapping ColumnMapping) map[string]int {
+	fields := map[string][]string{
+		"designator":   mapping.Designator,
+		"quantity":     mapping.Quantity,
+		"lcsc":         mapping.LCSCCode,
+		"mpn":          mapping.MPN,
+		"manufacturer": mapping.Manufacturer,
+		"value":        mapping.Value,
+		"footprint":    mapping.Footprint,
+		"description":  mapping.Description,
+		"dnp":          mapping.DNP,
+	}
+
+	columns := make(map[string]int)
+	for field, names := range fields {
+		if i := findColumn(header, names); i >= 0 {
+			columns[field] = i
+		}
+	}
+
+	_, hasDesignator := columns["designator"]
+	_, hasQuantity := columns["quantity"]
+	if !hasDesignator && !hasQuantity {
+		return nil
+	}
+	return columns
+}
+
+// findColumn returns the index of the header matching the earliest of names,
+// or -1 if none match.
+func findColumn(header []string, names []string) int {
+	for _, name := range names {
+		key := headerKey(name)
+		for i, h := range header {
+			if key != "" && headerKey(h) == key {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// lookupField returns the first field present under one of names.
+func lookupField(fields map[string]string, names []string) string {
+	for _, name := range names {
+		if v, ok := fields[headerKey(name)]; ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// headerKey lowercases s and strips everything except letters and digits.
+func headerKey(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
+
+// splitDesignators splits a designator list such as "R1, R2 R3;R4".
+func splitDesignators(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ';' || unicode.IsSpace(r)
+	})
+}
+
+// isDNP reports whether a DNP column value marks the part as not populated.
+func isDNP(v string) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "", "0", "false", "no", "n", "-":
+		return false
+	}
+	return true
+}
+
+// isBlankRecord reports whether every field of a CSV record is empty.
+func isBlankRecord(record []string) bool {
+	for _, f := range record {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
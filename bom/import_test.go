@@ -0,0 +1,231 @@
+package bom
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestParseCSVCustomMapping tests a generic CSV with a custom column mapping.
+func TestParseCSVCustomMapping(t *testing.T) {
+	data := `Part;Refs;Count;Maker
+LM358DR;U1 U2;2;TI
+10k;R1;1;
+`
+	mapping := ColumnMapping{
+		Designator:   []string{"Refs"},
+		Quantity:     []string{"Count"},
+		MPN:          []string{"Part"},
+		Manufacturer: []string{"Maker"},
+		Comma:        ';',
+	}
+
+	b, err := ParseCSV(strings.NewReader(data), mapping)
+	if err != nil {
+		t.Fatalf("ParseCSV failed: %v", err)
+	}
+
+	if len(b.Lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(b.Lines))
+	}
+
+	line := b.Lines[0]
+	if line.MPN != "LM358DR" || line.Manufacturer != "TI" || line.QuantityPerBoard() != 2 {
+		t.Errorf("unexpected line: %+v", line)
+	}
+	if len(line.Designators) != 2 || line.Designators[1] != "U2" {
+		t.Errorf("unexpected designators: %v", line.Designators)
+	}
+}
+
+// TestParseCSVNoHeader tests that a file without a recognizable header fails.
+func TestParseCSVNoHeader(t *testing.T) {
+	_, err := ParseCSV(strings.NewReader("a,b,c\n1,2,3\n"), DefaultColumnMapping())
+	if !errors.Is(err, ErrNoHeader) {
+		t.Errorf("expected ErrNoHeader, got %v", err)
+	}
+}
+
+// TestParseCSVInvalidQuantity tests that a non-numeric quantity is rejected.
+func TestParseCSVInvalidQuantity(t *testing.T) {
+	_, err := ParseCSV(strings.NewReader("Designator,Qty\nR1,many\n"), DefaultColumnMapping())
+	if err == nil {
+		t.Fatal("expected error for invalid quantity")
+	}
+}
+
+// TestParseKiCadCSV tests KiCad's Symbol Fields Table CSV export.
+func TestParseKiCadCSV(t *testing.T) {
+	data := `"Reference","Value","Footprint","Qty","LCSC","DNP"
+"C1,C2","100nF","Capacitor_SMD:C_0603_1608Metric","2","C14663",""
+"C5","100nF","Capacitor_SMD:C_0603_1608Metric","1","C14663",""
+"R1","10k","Resistor_SMD:R_0603_1608Metric","1","C25804",""
+"R2","10k","Resistor_SMD:R_0603_1608Metric","1","C25804","DNP"
+`
+
+	b, err := ParseKiCadCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseKiCadCSV failed: %v", err)
+	}
+
+	if len(b.Lines) != 2 {
+		t.Fatalf("expected 2 grouped lines, got %d: %+v", len(b.Lines), b.Lines)
+	}
+
+	caps := b.Lines[0]
+	if caps.LCSCCode != "C14663" || caps.QuantityPerBoard() != 3 || len(caps.Designators) != 3 {
+		t.Errorf("unexpected capacitor line: %+v", caps)
+	}
+
+	if b.Lines[1].QuantityPerBoard() != 1 {
+		t.Errorf("expected DNP resistor to be skipped, got %+v", b.Lines[1])
+	}
+}
+
+// TestParseKiCadCSVLegacy tests KiCad's legacy grouped CSV with a title preamble.
+func TestParseKiCadCSVLegacy(t *testing.T) {
+	data := `"Source:","/home/user/board.sch"
+"Date:","2024-01-01"
+"Component Count:","3"
+
+"Ref","Qnty","Value","Cmp name","Footprint","Description","Vendor"
+"R1 R2 ","2","10k","R","Resistor_SMD:R_0603_1608Metric","Resistor",""
+"U1 ","1","NE555","NE555","Package_SO:SOIC-8","Timer",""
+`
+
+	b, err := ParseKiCadCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseKiCadCSV failed: %v", err)
+	}
+
+	if len(b.Lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(b.Lines))
+	}
+	if b.Lines[0].Value != "10k" || len(b.Lines[0].Designators) != 2 {
+		t.Errorf("unexpected line: %+v", b.Lines[0])
+	}
+	if b.Lines[1].Description != "Timer" {
+		t.Errorf("expected description Timer, got %q", b.Lines[1].Description)
+	}
+}
+
+// TestParseKiCadXML tests KiCad's intermediate XML export.
+func TestParseKiCadXML(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<export version="E">
+  <design>
+    <source>/home/user/board.kicad_sch</source>
+  </design>
+  <components>
+    <comp ref="C1">
+      <value>100nF</value>
+      <footprint>Capacitor_SMD:C_0603_1608Metric</footprint>
+      <fields>
+        <field name="LCSC Part #">C14663</field>
+      </fields>
+      <libsource lib="Device" part="C" description="Unpolarized capacitor"/>
+    </comp>
+    <comp ref="C2">
+      <value>100nF</value>
+      <footprint>Capacitor_SMD:C_0603_1608Metric</footprint>
+      <fields>
+        <field name="LCSC Part #">C14663</field>
+      </fields>
+    </comp>
+    <comp ref="U1">
+      <value>STM32F103C8Tx</value>
+      <footprint>Package_QFP:LQFP-48_7x7mm_P0.5mm</footprint>
+      <fields>
+        <field name="MPN">STM32F103C8T6</field>
+        <field name="Manufacturer">ST</field>
+      </fields>
+    </comp>
+    <comp ref="R9">
+      <value>0R</value>
+      <property name="dnp"/>
+    </comp>
+  </components>
+</export>`
+
+	b, err := ParseKiCadXML(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseKiCadXML failed: %v", err)
+	}
+
+	if b.Name != "/home/user/board.kicad_sch" {
+		t.Errorf("unexpected BOM name %q", b.Name)
+	}
+	if len(b.Lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %+v", len(b.Lines), b.Lines)
+	}
+
+	caps := b.Lines[0]
+	if caps.LCSCCode != "C14663" || caps.QuantityPerBoard() != 2 || caps.Description != "Unpolarized capacitor" {
+		t.Errorf("unexpected capacitor line: %+v", caps)
+	}
+
+	mcu := b.Lines[1]
+	if mcu.MPN != "STM32F103C8T6" || mcu.Manufacturer != "ST" {
+		t.Errorf("unexpected MCU line: %+v", mcu)
+	}
+}
+
+// TestParseKiCadXMLInvalid tests error handling for malformed XML.
+func TestParseKiCadXMLInvalid(t *testing.T) {
+	if _, err := ParseKiCadXML(strings.NewReader("<export><components>")); err == nil {
+		t.Fatal("expected error for malformed XML")
+	}
+}
+
+// TestParseJLCPCB tests JLCPCB's assembly BOM format.
+func TestParseJLCPCB(t *testing.T) {
+	data := "\ufeffComment,Designator,Footprint,LCSC Part #\n" +
+		"100nF,\"C1,C2,C3\",0603,C14663\n" +
+		"10k,R1,0603,C25804\n"
+
+	b, err := ParseJLCPCB(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseJLCPCB failed: %v", err)
+	}
+
+	if len(b.Lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(b.Lines))
+	}
+
+	line := b.Lines[0]
+	if line.Value != "100nF" || line.LCSCCode != "C14663" || line.Footprint != "0603" || line.QuantityPerBoard() != 3 {
+		t.Errorf("unexpected line: %+v", line)
+	}
+}
+
+// TestGroupLines tests merging of lines for the same part.
+func TestGroupLines(t *testing.T) {
+	lines := []Line{
+		{Designators: []string{"U1"}, MPN: "LM358DR", Manufacturer: "TI"},
+		{Designators: []string{"R1"}, Value: "10k", Footprint: "0603"},
+		{Designators: []string{"U2"}, MPN: "lm358dr-tr", Manufacturer: "Texas Instruments"},
+		{Designators: []string{"R2"}, Value: "10k", Footprint: "0805"},
+		{Designators: []string{"C1"}, LCSCCode: "c14663", Quantity: 2},
+		{Designators: []string{"C3"}, LCSCCode: "C14663"},
+		{Designators: []string{"TP1"}},
+		{Designators: []string{"TP2"}},
+	}
+
+	grouped := GroupLines(lines)
+	if len(grouped) != 6 {
+		t.Fatalf("expected 6 groups, got %d: %+v", len(grouped), grouped)
+	}
+
+	if grouped[0].QuantityPerBoard() != 2 || len(grouped[0].Designators) != 2 {
+		t.Errorf("expected op-amps to be grouped, got %+v", grouped[0])
+	}
+	if grouped[3].QuantityPerBoard() != 3 {
+		t.Errorf("expected capacitor quantity 3, got %d", grouped[3].QuantityPerBoard())
+	}
+	if len(grouped[4].Designators) != 1 || len(grouped[5].Designators) != 1 {
+		t.Errorf("expected unidentified lines to stay apart, got %+v and %+v", grouped[4], grouped[5])
+	}
+	if len(lines[0].Designators) != 1 {
+		t.Error("expected input lines to be left unmodified")
+	}
+}
@@ -0,0 +1,228 @@
+package bom
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	lcsc "github.com/PatrickWalther/go-lcsc"
+)
+
+// Build is a BOM together with the number of boards to build.
+type Build struct {
+	BOM      *BOM
+	Quantity int
+}
+
+// OptimizeOptions configures Optimize.
+type OptimizeOptions struct {
+	// Attrition is the spare fraction to order per package type, keyed by
+	// EncapStandard (case-insensitive), e.g. {"0402": 0.05} orders 5% extra
+	// of every 0402 part.
+	Attrition map[string]float64
+	// DefaultAttrition is used for packages not listed in Attrition.
+	DefaultAttrition float64
+	// MinConfidence is the minimum MPN match confidence (default: DefaultMinConfidence).
+	MinConfidence float64
+}
+
+// LineSource identifies a BOM line contributing to an order line.
+type LineSource struct {
+	BOMName     string
+	Designators []string
+	Quantity    int // Parts needed for this build, before attrition
+}
+
+// OrderLine is the consolidated order for one LCSC product across builds.
+type OrderLine struct {
+	Product       *lcsc.Product
+	Sources       []LineSource
+	NeededQty     int     // Sum of parts needed across builds, before attrition
+	RequiredQty   int     // NeededQty plus attrition spares
+	OrderQty      int     // Cheapest quantity covering RequiredQty
+	PriceTier     int     // Ladder of the price break applied at OrderQty
+	UnitPrice     float64 // Unit price at OrderQty
+	ExtendedPrice float64 // UnitPrice times OrderQty
+	NaiveCost     float64 // Cost of ordering each build's share separately
+	Status        LineStatus
+}
+
+// OrderPlan is the result of Optimize.
+type OrderPlan struct {
+	Currency       string
+	CurrencySymbol string
+	Lines          []OrderLine
+	Unresolved     []CostedLine // Lines that could not be mapped to a product
+	Total          float64      // Cost of the consolidated order
+	NaiveTotal     float64      // Cost of ordering every BOM separately
+}
+
+// Savings returns how much the consolidated order saves over naive ordering.
+func (p *OrderPlan) Savings() float64 {
+	return p.NaiveTotal - p.Total
+}
+
+// Optimize merges several builds into one LCSC order. Quantities are
+// consolidated per ProductCode, spares are added per package type, and
+// each product's order quantity is chosen to minimize its cost given price
+// breaks and the minimum order quantity. Buying up to a higher price break
+// is preferred whenever it is cheaper overall and in stock.
+//
+// The naive total prices each build separately, with the same attrition,
+// at the price break its own quantity reaches.
+//
+// Builds and options are validated before any lookup. Each distinct part
+// is looked up once per run, including parts that fail to resolve.
+func Optimize(ctx context.Context, client *lcsc.Client, builds []Build, opts OptimizeOptions) (*OrderPlan, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+	for _, build := range builds {
+		if build.BOM == nil || build.Quantity <= 0 {
+			return nil, fmt.Errorf("each build needs a BOM and a positive quantity")
+		}
+	}
+	if opts.DefaultAttrition < 0 {
+		return nil, fmt.Errorf("attrition must not be negative")
+	}
+	for pkg, fraction := range opts.Attrition {
+		if fraction < 0 {
+			return nil, fmt.Errorf("attrition for %s must not be negative", pkg)
+		}
+	}
+	if opts.MinConfidence > 1 {
+		return nil, fmt.Errorf("minimum confidence must be at most 1")
+	}
+	if opts.MinConfidence <= 0 {
+		opts.MinConfidence = DefaultMinConfidence
+	}
+
+	plan := &OrderPlan{Currency: client.Currency()}
+	index := make(map[string]int)
+	resolved := make(map[string]*lcsc.Product)
+	failed := make(map[string]error) // Lines that did not resolve, tried once per run
+
+	for b, build := range builds {
+		for j, line := range build.BOM.Lines {
+			needed := line.QuantityPerBoard() * build.Quantity
+			if needed <= 0 {
+				continue
+			}
+
+			key := groupKey(line, j)
+			if strings.HasPrefix(key, "line:") {
+				// Unidentified lines are keyed by position, which is only
+				// unique within one BOM.
+				key = fmt.Sprintf("%d:%s", b, key)
+			}
+			product, ok := resolved[key]
+			err := failed[key]
+			if !ok && err == nil {
+				product, _, err = resolveLine(ctx, client, line, opts.MinConfidence)
+				if err != nil {
+					if ctx.Err() != nil {
+						return nil, ctx.Err()
+					}
+					failed[key] = err
+				} else {
+					resolved[key] = product
+				}
+			}
+			if err != nil {
+				plan.Unresolved = append(plan.Unresolved, CostedLine{
+					Line:        line,
+					RequiredQty: needed,
+					Status:      StatusUnresolved,
+					Err:         err,
+				})
+				continue
+			}
+
+			if plan.CurrencySymbol == "" && len(product.ProductPriceList) > 0 {
+				plan.CurrencySymbol = product.ProductPriceList[0].CurrencySymbol
+			}
+
+			attrition := opts.attritionFor(product.EncapStandard)
+			naiveQty := productOrderQuantity(product, withAttrition(needed, attrition))
+
+			i, ok := index[product.ProductCode]
+			if !ok {
+				i = len(plan.Lines)
+				index[product.ProductCode] = i
+				plan.Lines = append(plan.Lines, OrderLine{Product: product})
+			}
+			ol := &plan.Lines[i]
+			ol.NeededQty += needed
+			ol.NaiveCost += product.UnitPrice(naiveQty) * float64(naiveQty)
+			ol.Sources = append(ol.Sources, LineSource{
+				BOMName:     build.BOM.Name,
+				Designators: line.Designators,
+				Quantity:    needed,
+			})
+		}
+	}
+
+	for i := range plan.Lines {
+		ol := &plan.Lines[i]
+		ol.RequiredQty = withAttrition(ol.NeededQty, opts.attritionFor(ol.Product.EncapStandard))
+		ol.OrderQty = cheapestOrderQuantity(ol.Product, ol.RequiredQty)
+		ol.Status = stockStatus(ol.Product, ol.OrderQty)
+		if pb, ok := ol.Product.PriceAt(ol.OrderQty); ok {
+			ol.PriceTier = int(pb.Ladder)
+			ol.UnitPrice = float64(pb.ProductPrice)
+			ol.ExtendedPrice = ol.UnitPrice * float64(ol.OrderQty)
+		} else if ol.Status == StatusOK {
+			ol.Status = StatusNoPricing
+		}
+		plan.Total += ol.ExtendedPrice
+		plan.NaiveTotal += ol.NaiveCost
+	}
+
+	return plan, nil
+}
+
+// attritionFor returns the spare fraction for a package.
+func (o OptimizeOptions) attritionFor(pkg string) float64 {
+	pkg = strings.TrimSpace(pkg)
+	for name, fraction := range o.Attrition {
+		if strings.EqualFold(strings.TrimSpace(name), pkg) {
+			return fraction
+		}
+	}
+	return o.DefaultAttrition
+}
+
+// withAttrition adds a spare fraction to qty, rounding up.
+func withAttrition(qty int, fraction float64) int {
+	if fraction <= 0 {
+		return qty
+	}
+	return int(math.Ceil(float64(qty)*(1+fraction) - 1e-9))
+}
+
+// cheapestOrderQuantity returns the order quantity covering required with
+// the lowest total cost. Candidates are the minimum valid quantity and every
+// higher price break, each rounded to the minimum order quantity. Candidates
+// exceeding available stock are only considered if the minimum does too.
+// Ties go to the smaller quantity.
+func cheapestOrderQuantity(p *lcsc.Product, required int) int {
+	moq := int(p.MinPacketNumber)
+	minQty := productOrderQuantity(p, required)
+	if minQty <= 0 {
+		return minQty
+	}
+	stock := int(p.StockNumber)
+
+	best, bestCost := minQty, p.UnitPrice(minQty)*float64(minQty)
+	for _, pb := range p.ProductPriceList {
+		qty := orderQuantity(int(pb.Ladder), moq)
+		if qty <= minQty || (qty > stock && minQty <= stock) {
+			continue
+		}
+		if cost := p.UnitPrice(qty) * float64(qty); cost < bestCost || (cost == bestCost && qty < best) {
+			best, bestCost = qty, cost
+		}
+	}
+	return best
+}
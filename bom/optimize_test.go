@@ -0,0 +1,205 @@
+package bom
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	lcsc "github.com/PatrickWalther/go-lcsc"
+)
+
+// TestCheapestOrderQuantity tests buying up to a cheaper price break.
+func TestCheapestOrderQuantity(t *testing.T) {
+	product := &lcsc.Product{
+		MinPacketNumber: 10,
+		StockNumber:     100000,
+		ProductPriceList: []lcsc.PriceBreak{
+			{Ladder: 1000, ProductPrice: 0.01},
+			{Ladder: 10, ProductPrice: 0.1},
+			{Ladder: 100, ProductPrice: 0.05},
+		},
+	}
+
+	tests := []struct {
+		required, expected int
+	}{
+		{5, 10},     // 10 x 0.1 = 1.00 beats 100 x 0.05 = 5.00
+		{60, 100},   // 70 x 0.1 = 7.00 loses to 100 x 0.05 = 5.00
+		{150, 150},  // 150 x 0.05 = 7.50 beats 1000 x 0.01 = 10.00
+		{500, 1000}, // 500 x 0.05 = 25.00 loses to 1000 x 0.01 = 10.00
+		{0, 0},
+	}
+
+	for _, test := range tests {
+		if got := cheapestOrderQuantity(product, test.required); got != test.expected {
+			t.Errorf("cheapestOrderQuantity(%d) = %d, expected %d", test.required, got, test.expected)
+		}
+	}
+
+	product.StockNumber = 500
+	if got := cheapestOrderQuantity(product, 500); got != 500 {
+		t.Errorf("expected break beyond stock to be ignored, got %d", got)
+	}
+}
+
+// TestWithAttrition tests spare rounding.
+func TestWithAttrition(t *testing.T) {
+	tests := []struct {
+		qty      int
+		fraction float64
+		expected int
+	}{
+		{100, 0, 100},
+		{100, 0.05, 105},
+		{101, 0.05, 107},
+		{10, 0.1, 11},
+	}
+
+	for _, test := range tests {
+		if got := withAttrition(test.qty, test.fraction); got != test.expected {
+			t.Errorf("withAttrition(%d, %f) = %d, expected %d", test.qty, test.fraction, got, test.expected)
+		}
+	}
+}
+
+// TestOptimize tests consolidation across builds and savings reporting.
+func TestOptimize(t *testing.T) {
+	client := newTestClient(t)
+
+	boardA := &BOM{Name: "A", Lines: []Line{
+		{Designators: []string{"C1", "C2"}, LCSCCode: "C1"},
+		{Designators: []string{"U1"}, LCSCCode: "C2"},
+	}}
+	boardB := &BOM{Name: "B", Lines: []Line{
+		{Designators: []string{"C10"}, LCSCCode: "c1"},
+		{Designators: []string{"U5"}, MPN: "NOPE"},
+	}}
+
+	plan, err := Optimize(context.Background(), client, []Build{
+		{BOM: boardA, Quantity: 200},
+		{BOM: boardB, Quantity: 150},
+	}, OptimizeOptions{Attrition: map[string]float64{"0603": 0.1}})
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+
+	if len(plan.Lines) != 2 {
+		t.Fatalf("expected 2 order lines, got %d", len(plan.Lines))
+	}
+	if len(plan.Unresolved) != 1 {
+		t.Errorf("expected 1 unresolved line, got %d", len(plan.Unresolved))
+	}
+
+	caps := plan.Lines[0]
+	if caps.Product.ProductCode != "C1" || len(caps.Sources) != 2 {
+		t.Fatalf("unexpected capacitor line: %+v", caps)
+	}
+	// 400 + 150 needed, +10% = 605, rounded to 700; 1000 at 0.001 is cheaper.
+	if caps.NeededQty != 550 || caps.RequiredQty != 605 || caps.OrderQty != 1000 || caps.PriceTier != 1000 {
+		t.Errorf("unexpected capacitor quantities: %+v", caps)
+	}
+	// Naive: A needs 440 -> 500 at 0.002, B needs 165 -> 200 at 0.002.
+	if math.Abs(caps.NaiveCost-1.4) > 1e-9 || math.Abs(caps.ExtendedPrice-1.0) > 1e-9 {
+		t.Errorf("unexpected capacitor costs: naive %f, optimized %f", caps.NaiveCost, caps.ExtendedPrice)
+	}
+
+	mcu := plan.Lines[1]
+	if mcu.OrderQty != 200 || mcu.Status != StatusInsufficientStock {
+		t.Errorf("unexpected MCU line: %+v", mcu)
+	}
+
+	if math.Abs(plan.Savings()-0.4) > 1e-9 {
+		t.Errorf("expected savings of 0.4, got %f", plan.Savings())
+	}
+	if plan.CurrencySymbol != "US$" {
+		t.Errorf("unexpected currency symbol %q", plan.CurrencySymbol)
+	}
+}
+
+// TestOptimizeInvalidBuild tests argument validation.
+func TestOptimizeInvalidBuild(t *testing.T) {
+	client := lcsc.NewClient()
+
+	if _, err := Optimize(context.Background(), client, []Build{{BOM: &BOM{}, Quantity: 0}}, OptimizeOptions{}); err == nil {
+		t.Error("expected error for zero build quantity")
+	}
+	if _, err := Optimize(context.Background(), nil, nil, OptimizeOptions{}); err == nil {
+		t.Error("expected error for nil client")
+	}
+
+	// Invalid arguments fail before any lookup.
+	var requests int32
+	client = newTestClient(t, lcsc.WithObserver(lcsc.ObserverFunc(func(_ context.Context, e lcsc.RequestEvent) {
+		if e.Type == lcsc.EventRequestStart {
+			atomic.AddInt32(&requests, 1)
+		}
+	})))
+	board := &BOM{Lines: []Line{{Designators: []string{"C1"}, LCSCCode: "C1"}}}
+	invalid := []struct {
+		builds []Build
+		opts   OptimizeOptions
+	}{
+		{[]Build{{BOM: board, Quantity: 1}, {BOM: board, Quantity: -1}}, OptimizeOptions{}},
+		{[]Build{{BOM: board, Quantity: 1}, {Quantity: 1}}, OptimizeOptions{}},
+		{[]Build{{BOM: board, Quantity: 1}}, OptimizeOptions{DefaultAttrition: -0.1}},
+		{[]Build{{BOM: board, Quantity: 1}}, OptimizeOptions{Attrition: map[string]float64{"0603": -1}}},
+		{[]Build{{BOM: board, Quantity: 1}}, OptimizeOptions{MinConfidence: 2}},
+	}
+	for i, test := range invalid {
+		if _, err := Optimize(context.Background(), client, test.builds, test.opts); err == nil {
+			t.Errorf("expected error for case %d", i)
+		}
+	}
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Errorf("expected no requests, got %d", n)
+	}
+}
+
+// TestOptimizeUnresolvedOnce tests that a line failing to resolve is looked
+// up once per run.
+func TestOptimizeUnresolvedOnce(t *testing.T) {
+	var requests int32
+	client := newTestClient(t, lcsc.WithObserver(lcsc.ObserverFunc(func(_ context.Context, e lcsc.RequestEvent) {
+		if e.Type == lcsc.EventRequestStart {
+			atomic.AddInt32(&requests, 1)
+		}
+	})))
+
+	board := &BOM{Name: "A", Lines: []Line{{Designators: []string{"U1"}, LCSCCode: "C404"}}}
+	plan, err := Optimize(context.Background(), client, []Build{
+		{BOM: board, Quantity: 1},
+		{BOM: board, Quantity: 2},
+	}, OptimizeOptions{})
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+	if len(plan.Unresolved) != 2 {
+		t.Errorf("expected 2 unresolved lines, got %d", len(plan.Unresolved))
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expected 1 lookup, got %d", n)
+	}
+}
+
+// TestOptimizeUnidentifiedLines tests that lines without any identifying
+// field are not shared between builds.
+func TestOptimizeUnidentifiedLines(t *testing.T) {
+	client := newTestClient(t)
+	plan, err := Optimize(context.Background(), client, []Build{
+		{BOM: &BOM{Name: "A", Lines: []Line{{Designators: []string{"U1"}, Quantity: 1}}}, Quantity: 1},
+		{BOM: &BOM{Name: "B", Lines: []Line{{Designators: []string{"U7"}, Quantity: 1}}}, Quantity: 1},
+	}, OptimizeOptions{})
+	if err != nil {
+		t.Fatalf("Optimize failed: %v", err)
+	}
+	if len(plan.Unresolved) != 2 {
+		t.Fatalf("expected 2 unresolved lines, got %d", len(plan.Unresolved))
+	}
+	for i, want := range []string{"U1", "U7"} {
+		if err := plan.Unresolved[i].Err; err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected line %d error to name %s, got %v", i, want, err)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package lcsc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBrandPageSize is the page size for brand product listings.
+	DefaultBrandPageSize = 30
+	// MaxBrandPageSize is the largest page size LCSC accepts.
+	MaxBrandPageSize = 100
+
+	// brandCacheTTL is how long the brand directory is cached. It changes
+	// rarely and is large, so it is kept longer than product data.
+	brandCacheTTL = time.Hour
+)
+
+// Manufacturer is an entry of LCSC's brand directory.
+type Manufacturer struct {
+	ID           FlexInt `json:"brandId"`
+	Name         string  `json:"brandNameEn"`            // Name as LCSC lists it, like "TI(Texas Instruments)"
+	NameCn       string  `json:"brandNameCn,omitempty"`  // Chinese name
+	ProductCount FlexInt `json:"productCount,omitempty"` // Number of listed products
+}
+
+// Canonical returns the canonical manufacturer name. See CanonicalManufacturer.
+func (m *Manufacturer) Canonical() string {
+	return CanonicalManufacturer(m.Name)
+}
+
+// ListManufacturers returns LCSC's brand directory.
+// Uses GET /brand/list.
+func (c *Client) ListManufacturers(ctx context.Context) ([]Manufacturer, error) {
+	cacheKey := c.getCacheKeyBrands()
+	if c.cache != nil {
+		if cached, ok := c.cacheGet(ctx, "GET", "/brand/list", cacheKey); ok {
+			var brands []Manufacturer
+			if err := json.Unmarshal(cached, &brands); err == nil {
+				return brands, nil
+			}
+		}
+	}
+
+	body, err := c.doRequest(ctx, "GET", "/brand/list", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var brands []Manufacturer
+	if err := c.parseResponse(body, &brands); err != nil {
+		return nil, err
+	}
+
+	if c.cache != nil {
+		if cacheData, err := json.Marshal(brands); err == nil {
+			c.cache.Set(cacheKey, cacheData, brandCacheTTL)
+		}
+	}
+
+	return brands, nil
+}
+
+// FindManufacturer returns the directory entry for a manufacturer name,
+// matching across aliases with ManufacturersMatch, so "TI" finds
+// "TI(Texas Instruments)". It returns ErrManufacturerNotFound if no entry
+// matches.
+func (c *Client) FindManufacturer(ctx context.Context, name string) (*Manufacturer, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	brands, err := c.ListManufacturers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range brands {
+		if ManufacturersMatch(brands[i].Name, name) {
+			return &brands[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrManufacturerNotFound, name)
+}
+
+// BrandProductsRequest contains parameters for listing a brand's products.
+type BrandProductsRequest struct {
+	BrandID     int
+	Keyword     string // Optional search within the brand
+	CurrentPage int    // 1-based page number (default: 1)
+	PageSize    int    // Products per page (default: DefaultBrandPageSize, max: MaxBrandPageSize)
+
+	// Filters and Sort are applied to the returned page client-side.
+	Filters []Filter
+	Sort    []Order
+}
+
+// BrandProductsResponse is one page of a brand's products.
+type BrandProductsResponse struct {
+	Products   []Product `json:"productList"`
+	TotalCount int       `json:"total"`
+	PageSize   int       `json:"pageSize"`
+	PageNumber int       `json:"currentPage"`
+}
+
+// TotalPages returns the number of pages in the listing.
+func (r *BrandProductsResponse) TotalPages() int {
+	if r.PageSize <= 0 {
+		return 0
+	}
+	return (r.TotalCount + r.PageSize - 1) / r.PageSize
+}
+
+// brandProductsRequestBody is the JSON body for the brand products endpoint.
+type brandProductsRequestBody struct {
+	BrandID     int    `json:"brandId"`
+	Keyword     string `json:"keyword,omitempty"`
+	CurrentPage int    `json:"currentPage"`
+	PageSize    int    `json:"pageSize"`
+}
+
+// ListBrandProducts lists the products of a brand one page at a time,
+// optionally narrowed by a keyword.
+// Uses POST /brand/product/list with JSON body.
+func (c *Client) ListBrandProducts(ctx context.Context, req BrandProductsRequest) (*BrandProductsResponse, error) {
+	if req.BrandID <= 0 {
+		return nil, fmt.Errorf("brandID is required")
+	}
+	reqBody := brandProductsRequestBody{
+		BrandID:     req.BrandID,
+		Keyword:     strings.TrimSpace(req.Keyword),
+		CurrentPage: req.CurrentPage,
+		PageSize:    req.PageSize,
+	}
+	if reqBody.CurrentPage <= 0 {
+		reqBody.CurrentPage = 1
+	}
+	if reqBody.PageSize <= 0 {
+		reqBody.PageSize = DefaultBrandPageSize
+	}
+	if reqBody.PageSize > MaxBrandPageSize {
+		reqBody.PageSize = MaxBrandPageSize
+	}
+
+	cacheKey := c.getCacheKeyBrandProducts(reqBody)
+	var resp *BrandProductsResponse
+	if c.cache != nil {
+		if cached, ok := c.cacheGet(ctx, "POST", "/brand/product/list", cacheKey); ok {
+			var r BrandProductsResponse
+			if err := json.Unmarshal(cached, &r); err == nil {
+				resp = &r
+			}
+		}
+	}
+
+	if resp == nil {
+		body, err := c.doRequest(ctx, "POST", "/brand/product/list", nil, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		resp = &BrandProductsResponse{}
+		if err := c.parseResponse(body, resp); err != nil {
+			return nil, err
+		}
+		if c.cache != nil {
+			if cacheData, err := json.Marshal(resp); err == nil {
+				c.cache.Set(cacheKey, cacheData, c.cacheTTL)
+			}
+		}
+	}
+
+	resp.Products = applyListOptions(resp.Products, req.Filters, req.Sort)
+	return resp, nil
+}
+
+// getCacheKeyBrands generates a cache key for the brand directory.
+func (c *Client) getCacheKeyBrands() string {
+	return fmt.Sprintf("brands:%s", c.cacheScope())
+}
+
+// getCacheKeyBrandProducts generates a cache key for brand product listings.
+func (c *Client) getCacheKeyBrandProducts(body brandProductsRequestBody) string {
+	return fmt.Sprintf("brand:%s:%d:%d:%d:%s", c.cacheScope(), body.BrandID, body.CurrentPage, body.PageSize, body.Keyword)
+}
@@ -0,0 +1,109 @@
+package lcsc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// brandHandler serves a brand directory and one brand's products.
+func brandHandler(t *testing.T, requests *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		switch r.URL.Path {
+		case "/brand/list":
+			writeAPIResult(t, w, []map[string]interface{}{
+				{"brandId": 1, "brandNameEn": "TI(Texas Instruments)", "productCount": "5230"},
+				{"brandId": 2, "brandNameEn": "Samsung Electro-Mechanics"},
+			})
+		case "/brand/product/list":
+			var body brandProductsRequestBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("invalid body: %v", err)
+			}
+			if body.BrandID != 1 {
+				writeAPIResult(t, w, map[string]interface{}{"productList": []Product{}, "total": 0})
+				return
+			}
+			writeAPIResult(t, w, map[string]interface{}{
+				"productList": []Product{
+					{ProductCode: "C7593", BrandNameEn: "TI(Texas Instruments)", StockNumber: 0},
+					{ProductCode: "C7594", BrandNameEn: "TI(Texas Instruments)", StockNumber: 900},
+				},
+				"total":       61,
+				"pageSize":    body.PageSize,
+				"currentPage": body.CurrentPage,
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}
+}
+
+// TestFindManufacturer tests the brand directory and alias lookup.
+func TestFindManufacturer(t *testing.T) {
+	var requests int32
+	client := newTestClient(t, brandHandler(t, &requests), WithCache(NewMemoryCache(time.Minute)))
+	ctx := context.Background()
+
+	brands, err := client.ListManufacturers(ctx)
+	if err != nil {
+		t.Fatalf("ListManufacturers failed: %v", err)
+	}
+	if len(brands) != 2 || brands[0].ID != 1 || brands[0].ProductCount != 5230 {
+		t.Fatalf("unexpected brands: %+v", brands)
+	}
+	if brands[0].Canonical() != "Texas Instruments" {
+		t.Errorf("unexpected canonical name %q", brands[0].Canonical())
+	}
+
+	for _, name := range []string{"TI", "texas instruments inc."} {
+		m, err := client.FindManufacturer(ctx, name)
+		if err != nil || m.ID != 1 {
+			t.Errorf("FindManufacturer(%q) = %+v, %v", name, m, err)
+		}
+	}
+	if m, err := client.FindManufacturer(ctx, "SEMCO"); err != nil || m.ID != 2 {
+		t.Errorf("expected SEMCO to find Samsung, got %+v, %v", m, err)
+	}
+	if _, err := client.FindManufacturer(ctx, "Acme"); !errors.Is(err, ErrManufacturerNotFound) {
+		t.Errorf("expected ErrManufacturerNotFound, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected the directory to be fetched once, got %d requests", requests)
+	}
+}
+
+// TestListBrandProducts tests paged brand listings with filters.
+func TestListBrandProducts(t *testing.T) {
+	var requests int32
+	client := newTestClient(t, brandHandler(t, &requests))
+	ctx := context.Background()
+
+	resp, err := client.ListBrandProducts(ctx, BrandProductsRequest{BrandID: 1, PageSize: 500})
+	if err != nil {
+		t.Fatalf("ListBrandProducts failed: %v", err)
+	}
+	if len(resp.Products) != 2 || resp.PageSize != MaxBrandPageSize || resp.PageNumber != 1 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	resp, err = client.ListBrandProducts(ctx, BrandProductsRequest{BrandID: 1, CurrentPage: 2, Filters: []Filter{InStock(1)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Products) != 1 || resp.Products[0].ProductCode != "C7594" || resp.PageNumber != 2 {
+		t.Errorf("unexpected filtered response: %+v", resp)
+	}
+	if resp.TotalPages() != 3 {
+		t.Errorf("expected 3 pages, got %d", resp.TotalPages())
+	}
+
+	if _, err := client.ListBrandProducts(ctx, BrandProductsRequest{}); err == nil {
+		t.Error("expected error for missing brand ID")
+	}
+}
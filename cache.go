@@ -2,7 +2,11 @@ package lcsc
 
 import (
 	"crypto/sha256"
+	"encoding/binary"
 	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 )
@@ -123,6 +127,86 @@ func (c *MemoryCache) Close() {
 	close(c.done)
 }
 
+// FileCache is a Cache that stores entries as files in a directory, so
+// cached responses survive process restarts. Each entry is a file named
+// after the SHA-256 of its key holding the expiry time and the value.
+type FileCache struct {
+	mu  sync.Mutex
+	dir string
+	ttl time.Duration
+}
+
+// NewFileCache creates a file cache in dir with the specified default TTL.
+// The directory is created if it does not exist.
+func NewFileCache(dir string, defaultTTL time.Duration) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &FileCache{dir: dir, ttl: defaultTTL}, nil
+}
+
+// Get retrieves a value from the cache.
+func (c *FileCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := os.ReadFile(c.path(key))
+	if err != nil || len(data) < 8 {
+		return nil, false
+	}
+
+	expiresAt := time.Unix(0, int64(binary.BigEndian.Uint64(data[:8])))
+	if !time.Now().Before(expiresAt) {
+		return nil, false
+	}
+	return data[8:], true
+}
+
+// Set stores a value in the cache with the specified TTL.
+// If ttl is 0, the default TTL is used. Write errors are ignored, as a
+// failed cache write only costs a later cache miss.
+func (c *FileCache) Set(key string, value []byte, ttl time.Duration) {
+	if ttl == 0 {
+		ttl = c.ttl
+	}
+
+	data := make([]byte, 8+len(value))
+	binary.BigEndian.PutUint64(data[:8], uint64(time.Now().Add(ttl).UnixNano()))
+	copy(data[8:], value)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+// Delete removes a value from the cache.
+func (c *FileCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_ = os.Remove(c.path(key))
+}
+
+// path returns the file path for a cache key.
+func (c *FileCache) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(hash[:])+".cache")
+}
+
 // CacheConfig configures caching behavior.
 type CacheConfig struct {
 	Enabled    bool
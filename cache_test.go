@@ -294,3 +294,66 @@ func TestDefaultCacheConfig(t *testing.T) {
 		t.Errorf("expected details TTL 10m, got %v", config.DetailsTTL)
 	}
 }
+
+// TestFileCacheSetGet tests storing and retrieving values from a file cache.
+func TestFileCacheSetGet(t *testing.T) {
+	cache, err := NewFileCache(t.TempDir(), 5*time.Minute)
+	if err != nil {
+		t.Fatalf("NewFileCache failed: %v", err)
+	}
+
+	cache.Set("test:key", []byte("test value"), 0)
+
+	value, ok := cache.Get("test:key")
+	if !ok {
+		t.Fatal("expected to find value in cache")
+	}
+	if string(value) != "test value" {
+		t.Errorf("expected %q, got %q", "test value", value)
+	}
+
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("expected cache miss for missing key")
+	}
+}
+
+// TestFileCachePersistence tests that entries are visible to a new cache on the same directory.
+func TestFileCachePersistence(t *testing.T) {
+	dir := t.TempDir()
+
+	first, err := NewFileCache(dir, time.Minute)
+	if err != nil {
+		t.Fatalf("NewFileCache failed: %v", err)
+	}
+	first.Set("key", []byte("value"), 0)
+
+	second, err := NewFileCache(dir, time.Minute)
+	if err != nil {
+		t.Fatalf("NewFileCache failed: %v", err)
+	}
+	if value, ok := second.Get("key"); !ok || string(value) != "value" {
+		t.Errorf("expected persisted value, got %q (%v)", value, ok)
+	}
+}
+
+// TestFileCacheTTLAndDelete tests expiry and deletion in a file cache.
+func TestFileCacheTTLAndDelete(t *testing.T) {
+	cache, err := NewFileCache(t.TempDir(), time.Minute)
+	if err != nil {
+		t.Fatalf("NewFileCache failed: %v", err)
+	}
+
+	cache.Set("short", []byte("value"), 50*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	if _, ok := cache.Get("short"); ok {
+		t.Error("expected expired entry to be missing")
+	}
+
+	cache.Set("key", []byte("value"), 0)
+	cache.Delete("key")
+	if _, ok := cache.Get("key"); ok {
+		t.Error("expected deleted entry to be missing")
+	}
+
+	var _ Cache = (*FileCache)(nil)
+}
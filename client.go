@@ -8,25 +8,36 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
 const (
-	defaultBaseURL   = "https://wmsc.lcsc.com/ftps/wm"
-	defaultTimeout   = 30 * time.Second
-	defaultRateLimit = 5.0 // requests per second
-	defaultCurrency  = "USD"
-	userAgent        = "go-lcsc/1.0"
+	defaultBaseURL    = "https://wmsc.lcsc.com/ftps/wm"
+	defaultEasyEDAURL = "https://easyeda.com"
+	defaultTimeout    = 30 * time.Second
+	defaultRateLimit  = 5.0 // requests per second
+	defaultCurrency   = "USD"
+	defaultCacheTTL   = 5 * time.Minute
+	userAgent         = "go-lcsc/1.0"
 )
 
 // Client is an LCSC API client.
 type Client struct {
 	httpClient  *http.Client
 	baseURL     string
+	easyedaURL  string
 	currency    string
+	language    Language
 	rateLimiter *RateLimiter
 	cache       Cache
+	cacheTTL    time.Duration
 	retryConfig RetryConfig
+	observers   []Observer
+	middleware  []Middleware
+	session     *session
+	suggestions *suggestCache
+	err         error // Configuration error returned by every request
 }
 
 // ClientOption is a function that configures a Client.
@@ -46,10 +57,26 @@ func WithBaseURL(baseURL string) ClientOption {
 	}
 }
 
-// WithCurrency sets the currency for price responses.
+// WithEasyEDABaseURL sets a custom base URL for EasyEDA CAD data requests.
+func WithEasyEDABaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.easyedaURL = baseURL
+	}
+}
+
+// WithCurrency sets the currency for price responses; see Currencies. An
+// unsupported code makes every request fail with ErrUnsupportedCurrency
+// rather than return prices in another currency.
 func WithCurrency(currency string) ClientOption {
 	return func(c *Client) {
-		c.currency = currency
+		cur, ok := LookupCurrency(currency)
+		if !ok {
+			c.currency = currency
+			c.err = fmt.Errorf("%w: %q", ErrUnsupportedCurrency, currency)
+			return
+		}
+		c.currency = cur.Code
+		c.err = nil
 	}
 }
 
@@ -67,6 +94,14 @@ func WithCache(cache Cache) ClientOption {
 	}
 }
 
+// WithCacheTTL sets how long product, search and brand product responses
+// are cached (default: 5 minutes). Zero leaves the TTL to the cache.
+func WithCacheTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.cacheTTL = ttl
+	}
+}
+
 // WithRetryConfig sets the retry configuration.
 func WithRetryConfig(config RetryConfig) ClientOption {
 	return func(c *Client) {
@@ -82,116 +117,229 @@ func NewClient(opts ...ClientOption) *Client {
 			Timeout: defaultTimeout,
 		},
 		baseURL:     defaultBaseURL,
+		easyedaURL:  defaultEasyEDAURL,
 		currency:    defaultCurrency,
+		cacheTTL:    defaultCacheTTL,
 		rateLimiter: NewRateLimiter(defaultRateLimit),
 		retryConfig: DefaultRetryConfig(),
+		suggestions: newSuggestCache(),
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.applyMiddleware()
 
 	return c
 }
 
-// doRequest performs an HTTP request to the LCSC API.
-func (c *Client) doRequest(ctx context.Context, method, path string, params url.Values, body interface{}) ([]byte, error) {
-	cacheKey := ""
-	if method == http.MethodGet && c.cache != nil {
-		cacheKey = c.buildCacheKey(method, path, params)
-		if cached, ok := c.cache.Get(cacheKey); ok {
-			return cached, nil
-		}
+// Currency returns the currency code used for price responses.
+func (c *Client) Currency() string {
+	return c.currency
+}
+
+// request describes one HTTP request made through the client.
+type request struct {
+	method string
+	path   string // Relative to the base URL, or an absolute URL as for EasyEDA
+	params url.Values
+	body   interface{} // Sent as JSON when not nil
+	header http.Header // Extra headers, overriding the defaults
+
+	// accept reports whether a status is a success (default: 200 only).
+	accept func(status int) bool
+	// allowHTML accepts HTML content, which API requests treat as a block
+	// page.
+	allowHTML bool
+	// stream leaves the response body unread for the caller; otherwise it
+	// is read before the response is returned.
+	stream bool
+}
+
+// doRequest performs an HTTP request to the LCSC API. Responses are not
+// cached here: each endpoint caches its decoded result under its own key,
+// so every call reports a single cache hit or miss.
+func (c *Client) doRequest(ctx context.Context, method, path string, params url.Values, body interface{}) (respBody []byte, err error) {
+	if c.err != nil {
+		return nil, c.err
 	}
 
+	start := time.Now()
+	done := RequestEvent{Type: EventRequestDone, Method: method, Endpoint: endpointName(path)}
+	c.observe(ctx, RequestEvent{Type: EventRequestStart, Method: method, Endpoint: done.Endpoint})
+	defer func() {
+		done.Duration = time.Since(start)
+		done.Bytes = int64(len(respBody))
+		done.Err = err
+		c.observe(ctx, done)
+	}()
+
+	resp, err := c.roundTrip(ctx, &request{method: method, path: path, params: params, body: body}, &done)
+	if err != nil {
+		return nil, err
+	}
+	respBody, _ = io.ReadAll(resp.Body) // Already buffered
+	return respBody, nil
+}
+
+// roundTrip sends req with the client's retries and rate limiting, and
+// reports each attempt to observers. done is updated with the final
+// attempt and status; emitting the start and done events is left to the
+// caller. The caller must close the response body.
+func (c *Client) roundTrip(ctx context.Context, req *request, done *RequestEvent) (*http.Response, error) {
 	var lastErr error
 	for attempt := 0; attempt <= c.retryConfig.MaxRetries; attempt++ {
 		if attempt > 0 {
 			waitTime := c.retryConfig.calculateBackoff(attempt - 1)
+			c.observe(ctx, RequestEvent{Type: EventRetry, Method: req.method, Endpoint: done.Endpoint, Attempt: attempt + 1, Status: done.Status, Duration: waitTime, Err: lastErr})
 			if err := sleep(ctx, waitTime); err != nil {
 				return nil, err
 			}
 		}
 
+		waitStart := time.Now()
 		if err := c.rateLimiter.Wait(ctx); err != nil {
 			return nil, fmt.Errorf("rate limiter: %w", err)
 		}
-
-		respBody, statusCode, err := c.executeRequest(ctx, method, path, params, body)
+		c.observe(ctx, RequestEvent{Type: EventRateLimitWait, Method: req.method, Endpoint: done.Endpoint, Attempt: attempt + 1, Duration: time.Since(waitStart)})
+
+		attemptStart := time.Now()
+		resp, statusCode, err := c.executeRequest(ctx, req)
+		done.Attempt, done.Status = attempt+1, statusCode
+		ev := RequestEvent{
+			Type:     EventAttempt,
+			Method:   req.method,
+			Endpoint: done.Endpoint,
+			Attempt:  attempt + 1,
+			Status:   statusCode,
+			Duration: time.Since(attemptStart),
+			Err:      err,
+		}
+		if resp != nil && resp.ContentLength > 0 {
+			ev.Bytes = resp.ContentLength
+		}
+		c.observe(ctx, ev)
 		if err != nil {
 			lastErr = err
-			if shouldRetry(err, statusCode) {
+			// Once the caller gave up, retrying cannot succeed.
+			if ctx.Err() == nil && shouldRetry(err, statusCode) {
 				continue
 			}
 			return nil, err
 		}
 
-		if cacheKey != "" && c.cache != nil {
-			c.cache.Set(cacheKey, respBody, 5*time.Minute)
-		}
-
-		return respBody, nil
+		return resp, nil
 	}
 
 	return nil, fmt.Errorf("max retries exceeded: %w", lastErr)
 }
 
-// executeRequest performs a single HTTP request.
-func (c *Client) executeRequest(ctx context.Context, method, path string, params url.Values, body interface{}) ([]byte, int, error) {
-	reqURL := c.baseURL + path
-	if len(params) > 0 {
-		reqURL = fmt.Sprintf("%s?%s", reqURL, params.Encode())
+// executeRequest performs a single HTTP request, refreshing the session
+// and repeating the request once if it is blocked.
+func (c *Client) executeRequest(ctx context.Context, req *request) (*http.Response, int, error) {
+	send := func() (*http.Response, int, error) {
+		return c.sendRequest(ctx, req)
+	}
+	if c.session != nil {
+		return c.sessionRequest(ctx, send)
+	}
+	return send()
+}
+
+// sendRequest sends one HTTP request and checks the response status. On
+// error the response body is closed and a nil response returned.
+func (c *Client) sendRequest(ctx context.Context, r *request) (*http.Response, int, error) {
+	reqURL := r.path
+	if !strings.Contains(r.path, "://") {
+		reqURL = c.baseURL + r.path
+	}
+	if len(r.params) > 0 {
+		reqURL = fmt.Sprintf("%s?%s", reqURL, r.params.Encode())
 	}
 
 	var bodyReader io.Reader
-	if body != nil {
-		jsonBody, err := json.Marshal(body)
+	if r.body != nil {
+		jsonBody, err := json.Marshal(r.body)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to marshal request body: %w", err)
 		}
 		bodyReader = bytes.NewReader(jsonBody)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, reqURL, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, r.method, reqURL, bodyReader)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", userAgent)
-	req.Header.Set("Cookie", fmt.Sprintf("currencyCode=%s", c.currency))
+	if c.language != "" && c.isBaseHost(req.URL) {
+		req.Header.Set("Accept-Language", string(c.language))
+	}
+	c.setCookies(req)
 
-	if body != nil {
+	if r.body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, 0, fmt.Errorf("request failed: %w", err)
 	}
+	c.storeCookies(resp)
+
+	if err := c.checkResponse(resp, r); err != nil {
+		_ = resp.Body.Close()
+		return nil, resp.StatusCode, err
+	}
+	if r.stream {
+		return resp, resp.StatusCode, nil
+	}
+
 	defer func() {
 		_ = resp.Body.Close()
 	}()
-
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, resp.StatusCode, fmt.Errorf("failed to read response: %w", err)
 	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	resp.ContentLength = int64(len(respBody))
+	return resp, resp.StatusCode, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, resp.StatusCode, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+// checkResponse returns an error for responses with a status not accepted
+// by r and for blocked requests.
+func (c *Client) checkResponse(resp *http.Response, r *request) error {
+	if resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("%w: unexpected status code: %d", ErrBlocked, resp.StatusCode)
 	}
-
-	return respBody, resp.StatusCode, nil
+	ok := resp.StatusCode == http.StatusOK
+	if r.accept != nil {
+		ok = r.accept(resp.StatusCode)
+	}
+	if !ok {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	if c.session != nil && !r.allowHTML && mediaType(resp.Header.Get("Content-Type")) == "text/html" {
+		// Anti-bot challenges are served as pages with status 200. Only
+		// session clients treat them as blocks, since only they can
+		// refresh the session to pass them.
+		return fmt.Errorf("%w: HTML page instead of JSON", ErrBlocked)
+	}
+	return nil
 }
 
-// buildCacheKey creates a cache key from request parameters.
-func (c *Client) buildCacheKey(method, path string, params url.Values) string {
-	key := method + ":" + c.currency + ":" + path
-	if params != nil {
-		key += "?" + params.Encode()
+// cacheScope returns the part of cache keys identifying the currency and
+// language responses are in.
+func (c *Client) cacheScope() string {
+	if c.language == "" || c.language == LanguageEnglish {
+		return c.currency
 	}
-	return key
+	return c.currency + "/" + string(c.language)
 }
 
 // parseResponse parses the API response and checks for errors.
@@ -2,7 +2,10 @@ package lcsc
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -116,74 +119,34 @@ func TestNewClientWithMultipleOptions(t *testing.T) {
 	}
 }
 
-// TestBuildCacheKey tests cache key generation.
-func TestBuildCacheKey(t *testing.T) {
-	client := NewClient(WithCurrency("USD"))
-
-	params := make(map[string][]string)
-	params["productCode"] = []string{"C8734"}
-
-	key := client.buildCacheKey("GET", "/product/detail", params)
-
-	if key == "" {
-		t.Fatal("expected non-empty cache key")
-	}
-
-	if !contains(key, "GET") {
-		t.Error("expected cache key to contain method")
-	}
-
-	if !contains(key, "USD") {
-		t.Error("expected cache key to contain currency")
-	}
-
-	if !contains(key, "/product/detail") {
-		t.Error("expected cache key to contain path")
-	}
-}
-
-// TestBuildCacheKeyDifferentMethods produces different keys for different methods.
-func TestBuildCacheKeyDifferentMethods(t *testing.T) {
-	client := NewClient()
-
-	key1 := client.buildCacheKey("GET", "/path", nil)
-	key2 := client.buildCacheKey("POST", "/path", nil)
-
-	if key1 == key2 {
-		t.Error("expected different cache keys for different methods")
-	}
-}
-
-// TestBuildCacheKeyWithParams produces different keys for different parameters.
-func TestBuildCacheKeyWithParams(t *testing.T) {
-	client := NewClient()
-
-	params1 := make(map[string][]string)
-	params1["productCode"] = []string{"C8734"}
-
-	params2 := make(map[string][]string)
-	params2["productCode"] = []string{"C5555"}
-
-	key1 := client.buildCacheKey("GET", "/product/detail", params1)
-	key2 := client.buildCacheKey("GET", "/product/detail", params2)
-
-	if key1 == key2 {
-		t.Error("expected different cache keys for different parameters")
+// TestWithCacheTTL tests the lifetime of cached product data.
+func TestWithCacheTTL(t *testing.T) {
+	var hits int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		writeAPIResult(t, w, Product{ProductCode: "C1"})
+	}
+	lookupTwice := func(client *Client) {
+		t.Helper()
+		for i := 0; i < 2; i++ {
+			if i > 0 {
+				time.Sleep(100 * time.Millisecond)
+			}
+			if _, err := client.GetProductDetails(context.Background(), "C1"); err != nil {
+				t.Fatalf("GetProductDetails failed: %v", err)
+			}
+		}
 	}
-}
 
-// TestBuildCacheKeyNilParams produces a valid key with nil params.
-func TestBuildCacheKeyNilParams(t *testing.T) {
-	client := NewClient()
-
-	key := client.buildCacheKey("GET", "/path", nil)
-
-	if key == "" {
-		t.Fatal("expected non-empty cache key")
+	// By default products are kept for five minutes, whatever the cache's TTL.
+	lookupTwice(newTestClient(t, handler, WithCache(NewMemoryCache(50*time.Millisecond))))
+	if n := atomic.LoadInt32(&hits); n != 1 {
+		t.Fatalf("expected 1 request while cached, got %d", n)
 	}
 
-	if !contains(key, "GET") || !contains(key, "/path") {
-		t.Error("cache key missing expected components")
+	lookupTwice(newTestClient(t, handler, WithCache(NewMemoryCache(time.Hour)), WithCacheTTL(50*time.Millisecond)))
+	if n := atomic.LoadInt32(&hits); n != 3 {
+		t.Errorf("expected a new request after the TTL, got %d requests", n-1)
 	}
 }
 
@@ -330,3 +293,45 @@ func contains(s, substring string) bool {
 	}
 	return false
 }
+
+// newTestClient starts an httptest server with handler and returns a client
+// pointed at it. Retries are disabled and the rate limit is raised so tests
+// run quickly.
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...ClientOption) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	opts = append([]ClientOption{
+		WithBaseURL(server.URL),
+		WithRetryConfig(NoRetry()),
+		WithRateLimit(1000),
+	}, opts...)
+	return NewClient(opts...)
+}
+
+// writeAPIResult writes result wrapped in the standard LCSC response envelope.
+func writeAPIResult(t *testing.T, w http.ResponseWriter, result interface{}) {
+	t.Helper()
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("failed to marshal result: %v", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(apiResponse{Code: 200, Message: "success", Result: data})
+}
+
+// decodeJSONBody decodes a JSON request body into v.
+func decodeJSONBody(r *http.Request, v interface{}) error {
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+// TestClientCurrency tests the Currency accessor.
+func TestClientCurrency(t *testing.T) {
+	if got := NewClient().Currency(); got != defaultCurrency {
+		t.Errorf("expected currency %s, got %s", defaultCurrency, got)
+	}
+	if got := NewClient(WithCurrency("EUR")).Currency(); got != "EUR" {
+		t.Errorf("expected currency EUR, got %s", got)
+	}
+}
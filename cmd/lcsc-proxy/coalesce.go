@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// call is an in-flight upstream lookup shared by concurrent requests.
+type call struct {
+	done chan struct{}
+	val  interface{}
+	err  error
+}
+
+// group coalesces concurrent lookups with the same key into one upstream
+// request.
+type group struct {
+	timeout time.Duration
+
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// newGroup creates a group whose shared lookups run for at most timeout.
+func newGroup(timeout time.Duration) *group {
+	return &group{timeout: timeout, calls: make(map[string]*call)}
+}
+
+// do runs fn once for all concurrent callers with the same key. The lookup
+// is detached from the caller that started it, so one client disconnecting
+// does not fail the others; each caller still stops waiting when its own
+// context is done.
+func (g *group) do(ctx context.Context, key string, fn func(context.Context) (interface{}, error)) (interface{}, error) {
+	g.mu.Lock()
+	c, ok := g.calls[key]
+	if !ok {
+		c = &call{done: make(chan struct{})}
+		g.calls[key] = c
+		go g.run(ctx, key, c, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.val, c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// run performs a lookup and publishes its result. A panic in fn is
+// reported to the callers as an error rather than crashing the server.
+func (g *group) run(ctx context.Context, key string, c *call, fn func(context.Context) (interface{}, error)) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), g.timeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			c.val, c.err = nil, fmt.Errorf("lookup panicked: %v", r)
+		}
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+
+	c.val, c.err = fn(ctx)
+}
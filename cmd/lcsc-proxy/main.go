@@ -0,0 +1,164 @@
+// Command lcsc-proxy serves LCSC catalog lookups as a local JSON API, so
+// tools in any language can use one shared client.
+//
+// Usage:
+//
+//	lcsc-proxy [flags]
+//
+// All callers share one cache and rate limiter, and concurrent identical
+// lookups are sent to LCSC once. Endpoints:
+//
+//	GET  /v1/search?q=<keyword>     search, with currency, qty, in_stock, package, brand and sort
+//	GET  /v1/products/<code>        product details, with currency
+//	POST /v1/products/batch         details for {"codes": [...]}
+//	POST /v1/bom                    BOM pricing from JSON or a BOM file, with qty and input
+//	GET  /healthz                   liveness
+//	GET  /readyz                    readiness, probing LCSC
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	lcsc "github.com/PatrickWalther/go-lcsc"
+)
+
+// options are the parsed command-line flags.
+type options struct {
+	addr         string
+	currency     string
+	rate         float64
+	cacheDir     string
+	cacheTTL     time.Duration
+	retries      int
+	timeout      time.Duration
+	baseURL      string
+	maxBatch     int
+	readyProduct string
+}
+
+// parseFlags parses the command-line flags.
+func parseFlags(args []string, stderr io.Writer) (*options, error) {
+	o := &options{}
+	fs := flag.NewFlagSet("lcsc-proxy", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&o.addr, "addr", "127.0.0.1:8080", "address to listen on")
+	fs.StringVar(&o.currency, "currency", "USD", "default currency code for prices")
+	fs.Float64Var(&o.rate, "rate", 5, "rate limit in requests per second to LCSC")
+	fs.StringVar(&o.cacheDir, "cache-dir", "", "directory for a persistent response cache (in memory if empty)")
+	fs.DurationVar(&o.cacheTTL, "cache-ttl", 10*time.Minute, "time to live for cached responses")
+	fs.IntVar(&o.retries, "retries", lcsc.DefaultRetryConfig().MaxRetries, "maximum retries for transient failures")
+	fs.DurationVar(&o.timeout, "timeout", 30*time.Second, "timeout for one upstream lookup")
+	fs.StringVar(&o.baseURL, "base-url", "", "override the LCSC API base URL")
+	fs.IntVar(&o.maxBatch, "max-batch", 50, "maximum product codes per batch request")
+	fs.StringVar(&o.readyProduct, "ready-product", "C8734", "product looked up by /readyz (no probe if empty)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: lcsc-proxy [flags]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Serve LCSC lookups as a local JSON API.")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Flags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return nil, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	if o.rate <= 0 {
+		return nil, fmt.Errorf("-rate must be positive")
+	}
+	if _, ok := lcsc.LookupCurrency(o.currency); !ok {
+		return nil, fmt.Errorf("%w: %q", lcsc.ErrUnsupportedCurrency, o.currency)
+	}
+	return o, nil
+}
+
+// client builds the shared lcsc.Client from the flags.
+func (o *options) client() (*lcsc.Client, error) {
+	retry := lcsc.NoRetry()
+	if o.retries > 0 {
+		retry = lcsc.DefaultRetryConfig()
+		retry.MaxRetries = o.retries
+	}
+
+	var cache lcsc.Cache = lcsc.NewMemoryCache(o.cacheTTL)
+	if o.cacheDir != "" {
+		fc, err := lcsc.NewFileCache(o.cacheDir, o.cacheTTL)
+		if err != nil {
+			return nil, err
+		}
+		cache = fc
+	}
+
+	opts := []lcsc.ClientOption{
+		lcsc.WithCurrency(o.currency),
+		lcsc.WithRateLimit(o.rate),
+		lcsc.WithRetryConfig(retry),
+		lcsc.WithHTTPClient(&http.Client{Timeout: o.timeout}),
+		lcsc.WithCache(cache),
+		lcsc.WithCacheTTL(o.cacheTTL),
+	}
+	if o.baseURL != "" {
+		opts = append(opts, lcsc.WithBaseURL(o.baseURL))
+	}
+	return lcsc.NewClient(opts...), nil
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx, os.Args[1:], os.Stderr); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "lcsc-proxy: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run serves until ctx is done, then drains in-flight requests.
+func run(ctx context.Context, args []string, stderr io.Writer) error {
+	o, err := parseFlags(args, stderr)
+	if err != nil {
+		return err
+	}
+	client, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	s := newServer(client, config{Timeout: o.timeout, MaxBatch: o.maxBatch, ReadyProduct: o.readyProduct})
+	srv := &http.Server{
+		Addr:              o.addr,
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.ListenAndServe()
+	}()
+	fmt.Fprintf(stderr, "lcsc-proxy: listening on %s\n", o.addr)
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.draining.Store(true)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), o.timeout)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,502 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	lcsc "github.com/PatrickWalther/go-lcsc"
+	"github.com/PatrickWalther/go-lcsc/bom"
+)
+
+const (
+	// maxBodySize limits request bodies for batch and BOM requests.
+	maxBodySize = 4 << 20
+	// batchWorkers is the number of concurrent lookups per batch request.
+	batchWorkers = 4
+	// readyTTL is how long a readiness probe result is reused.
+	readyTTL = 30 * time.Second
+)
+
+// config configures a server.
+type config struct {
+	Timeout      time.Duration // Limit for one upstream lookup
+	MaxBatch     int           // Maximum codes per batch request
+	ReadyProduct string        // Product looked up by readiness probes; empty disables the probe
+}
+
+// server exposes an lcsc.Client over HTTP. All callers share the client's
+// cache and rate limiter, and concurrent identical lookups are coalesced.
+type server struct {
+	client *lcsc.Client
+	cfg    config
+	group  *group
+
+	draining atomic.Bool
+
+	readyMu  sync.Mutex
+	readyAt  time.Time
+	readyErr error
+}
+
+// newServer creates a server for client.
+func newServer(client *lcsc.Client, cfg config) *server {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	if cfg.MaxBatch <= 0 {
+		cfg.MaxBatch = 50
+	}
+	return &server{client: client, cfg: cfg, group: newGroup(cfg.Timeout)}
+}
+
+// handler returns the HTTP routes of the server.
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleHealth)
+	mux.HandleFunc("/readyz", s.handleReady)
+	mux.HandleFunc("/v1/search", s.handleSearch)
+	mux.HandleFunc("/v1/products/", s.handleProduct)
+	mux.HandleFunc("/v1/products/batch", s.handleBatch)
+	mux.HandleFunc("/v1/bom", s.handleBOM)
+	return mux
+}
+
+// handleHealth implements GET /healthz: the process is up.
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReady implements GET /readyz: the server accepts traffic and LCSC
+// is reachable. Probe results are reused for readyTTL.
+func (s *server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	if s.draining.Load() {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "draining"})
+		return
+	}
+	if err := s.probe(r.Context()); err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "unavailable", "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+// probe looks up the readiness product, reusing a recent result.
+// Concurrent probes share one lookup through the group, so the lock is
+// only held to read and store the result.
+func (s *server) probe(ctx context.Context) error {
+	if s.cfg.ReadyProduct == "" {
+		return nil
+	}
+	s.readyMu.Lock()
+	if !s.readyAt.IsZero() && time.Since(s.readyAt) < readyTTL {
+		err := s.readyErr
+		s.readyMu.Unlock()
+		return err
+	}
+	s.readyMu.Unlock()
+
+	_, err := s.product(ctx, s.client, s.cfg.ReadyProduct)
+	if ctx.Err() != nil {
+		return err
+	}
+	s.readyMu.Lock()
+	s.readyAt, s.readyErr = time.Now(), err
+	s.readyMu.Unlock()
+	return err
+}
+
+// searchResponse is the JSON body of search results.
+type searchResponse struct {
+	Products        []lcsc.Product `json:"products"`
+	TotalCount      int            `json:"totalCount"`
+	DirectMatchCode string         `json:"directMatchCode,omitempty"`
+}
+
+// handleSearch implements GET /v1/search?q=<keyword>, with optional
+// currency, qty, in_stock, package, brand and sort parameters.
+func (s *server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	query := r.URL.Query()
+	keyword := strings.TrimSpace(query.Get("q"))
+	if keyword == "" {
+		writeError(w, http.StatusBadRequest, errors.New("query parameter q is required"))
+		return
+	}
+	client, ok := s.clientFor(w, r)
+	if !ok {
+		return
+	}
+	req, err := searchRequest(keyword, query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	key := "search\x00" + client.Currency() + "\x00" + keyword
+	val, err := s.group.do(r.Context(), key, func(ctx context.Context) (interface{}, error) {
+		return client.KeywordSearch(ctx, lcsc.SearchRequest{Keyword: keyword})
+	})
+	if err != nil {
+		writeLookupError(w, err)
+		return
+	}
+
+	// Filters run per caller on a copy, since coalesced callers share the
+	// unfiltered response.
+	resp := *val.(*lcsc.SearchResponse)
+	resp.Products = lcsc.FilterProducts(resp.Products, req.Filters...)
+	lcsc.SortProducts(resp.Products, req.Sort...)
+	writeJSON(w, http.StatusOK, searchResponse{
+		Products:        resp.Products,
+		TotalCount:      resp.TotalCount,
+		DirectMatchCode: resp.DirectMatchCode,
+	})
+}
+
+// searchRequest builds filter and sort options from query parameters.
+func searchRequest(keyword string, query url.Values) (lcsc.SearchRequest, error) {
+	get := func(name string) string {
+		return strings.TrimSpace(query.Get(name))
+	}
+
+	req := lcsc.SearchRequest{Keyword: keyword}
+	qty := 1
+	if v := get("qty"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return req, fmt.Errorf("qty must be a positive integer, got %q", v)
+		}
+		qty = n
+	}
+	if v := get("in_stock"); v != "" {
+		inStock, err := strconv.ParseBool(v)
+		if err != nil {
+			return req, fmt.Errorf("in_stock must be a boolean, got %q", v)
+		}
+		if inStock {
+			req.Filters = append(req.Filters, lcsc.InStock(qty))
+		}
+	}
+	if v := get("package"); v != "" {
+		req.Filters = append(req.Filters, lcsc.Package(v))
+	}
+	if v := get("brand"); v != "" {
+		req.Filters = append(req.Filters, lcsc.Brand(v))
+	}
+	switch v := get("sort"); v {
+	case "", "relevance":
+	case "price":
+		req.Sort = []lcsc.Order{lcsc.ByUnitPrice(qty)}
+	case "stock":
+		req.Sort = []lcsc.Order{lcsc.ByStock()}
+	default:
+		return req, fmt.Errorf("unknown sort order %q", v)
+	}
+	return req, nil
+}
+
+// handleProduct implements GET /v1/products/<code>.
+func (s *server) handleProduct(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	code := strings.TrimSpace(strings.TrimPrefix(r.URL.Path, "/v1/products/"))
+	if code == "" || strings.Contains(code, "/") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	client, ok := s.clientFor(w, r)
+	if !ok {
+		return
+	}
+
+	product, err := s.product(r.Context(), client, code)
+	if err != nil {
+		writeLookupError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, product)
+}
+
+// product looks up one product, coalescing concurrent lookups.
+func (s *server) product(ctx context.Context, client *lcsc.Client, code string) (*lcsc.Product, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	key := "product\x00" + client.Currency() + "\x00" + code
+	val, err := s.group.do(ctx, key, func(ctx context.Context) (interface{}, error) {
+		return client.GetProductDetails(ctx, code)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return val.(*lcsc.Product), nil
+}
+
+// batchRequest is the JSON body of a batch lookup.
+type batchRequest struct {
+	Codes []string `json:"codes"`
+}
+
+// batchResponse is the JSON body of batch results. Codes that failed are
+// listed in Errors instead of Products.
+type batchResponse struct {
+	Products map[string]*lcsc.Product `json:"products"`
+	Errors   map[string]string        `json:"errors,omitempty"`
+}
+
+// handleBatch implements POST /v1/products/batch.
+func (s *server) handleBatch(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	client, ok := s.clientFor(w, r)
+	if !ok {
+		return
+	}
+
+	var req batchRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	codes := uniqueCodes(req.Codes)
+	if len(codes) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("codes is required"))
+		return
+	}
+	if len(codes) > s.cfg.MaxBatch {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("at most %d codes per batch, got %d", s.cfg.MaxBatch, len(codes)))
+		return
+	}
+
+	resp := batchResponse{Products: make(map[string]*lcsc.Product, len(codes)), Errors: map[string]string{}}
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		jobs = make(chan string)
+	)
+	for i := 0; i < batchWorkers && i < len(codes); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for code := range jobs {
+				product, err := s.product(r.Context(), client, code)
+				mu.Lock()
+				if err != nil {
+					resp.Errors[code] = err.Error()
+				} else {
+					resp.Products[code] = product
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, code := range codes {
+		jobs <- code
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := r.Context().Err(); err != nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// uniqueCodes normalizes product codes and drops blanks and duplicates.
+func uniqueCodes(codes []string) []string {
+	seen := make(map[string]bool, len(codes))
+	var result []string
+	for _, code := range codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		result = append(result, code)
+	}
+	return result
+}
+
+// bomRequest is the JSON body of a BOM pricing request.
+type bomRequest struct {
+	Name          string    `json:"name"`
+	BuildQuantity int       `json:"buildQuantity"`
+	Lines         []bomLine `json:"lines"`
+}
+
+// bomLine is one line of a BOM pricing request.
+type bomLine struct {
+	Designators  []string `json:"designators"`
+	Quantity     int      `json:"quantity"`
+	LCSCCode     string   `json:"lcscCode"`
+	MPN          string   `json:"mpn"`
+	Manufacturer string   `json:"manufacturer"`
+	Value        string   `json:"value"`
+	Footprint    string   `json:"footprint"`
+	Description  string   `json:"description"`
+}
+
+// handleBOM implements POST /v1/bom. The body is a JSON bomRequest, or a
+// BOM file with the format given by the input parameter (csv, kicad,
+// kicad-xml or jlcpcb) and the build quantity by qty. The response is the
+// costing as written by bom.WriteJSON.
+func (s *server) handleBOM(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	client, ok := s.clientFor(w, r)
+	if !ok {
+		return
+	}
+
+	b, qty, err := readBOMRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	costing, err := bom.Cost(r.Context(), client, b, bom.CostOptions{BuildQuantity: qty})
+	if err != nil {
+		writeLookupError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = bom.WriteJSON(w, costing)
+}
+
+// readBOMRequest parses the BOM and build quantity of a BOM request.
+func readBOMRequest(r *http.Request) (*bom.BOM, int, error) {
+	body := io.LimitReader(r.Body, maxBodySize)
+	query := r.URL.Query()
+
+	input := query.Get("input")
+	if input == "" {
+		input = "csv"
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+			input = "json"
+		}
+	}
+
+	qty := 1
+	if v := query.Get("qty"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return nil, 0, fmt.Errorf("qty must be a positive integer, got %q", v)
+		}
+		qty = n
+	}
+
+	var (
+		b   *bom.BOM
+		err error
+	)
+	switch input {
+	case "json":
+		var req bomRequest
+		if err := json.NewDecoder(body).Decode(&req); err != nil {
+			return nil, 0, fmt.Errorf("invalid request body: %w", err)
+		}
+		b = &bom.BOM{Name: req.Name}
+		for _, l := range req.Lines {
+			b.Lines = append(b.Lines, bom.Line(l))
+		}
+		if req.BuildQuantity > 0 {
+			qty = req.BuildQuantity
+		}
+	case "csv":
+		b, err = bom.ParseCSV(body, bom.DefaultColumnMapping())
+	case "kicad":
+		b, err = bom.ParseKiCadCSV(body)
+	case "kicad-xml":
+		b, err = bom.ParseKiCadXML(body)
+	case "jlcpcb":
+		b, err = bom.ParseJLCPCB(body)
+	default:
+		return nil, 0, fmt.Errorf("unknown input format %q", input)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(b.Lines) == 0 {
+		return nil, 0, errors.New("BOM has no lines")
+	}
+	return b, qty, nil
+}
+
+// clientFor returns the client for the request's currency parameter,
+// writing an error response if it is not supported.
+func (s *server) clientFor(w http.ResponseWriter, r *http.Request) (*lcsc.Client, bool) {
+	currency := r.URL.Query().Get("currency")
+	if currency == "" {
+		return s.client, true
+	}
+	client, err := s.client.InCurrency(currency)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return nil, false
+	}
+	return client, true
+}
+
+// allowMethod writes a 405 response unless the request uses method.
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method || (method == http.MethodGet && r.Method == http.MethodHead) {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	return false
+}
+
+// writeLookupError writes the response for a failed upstream lookup.
+func writeLookupError(w http.ResponseWriter, err error) {
+	writeError(w, statusFor(err), err)
+}
+
+// statusFor maps client errors to HTTP status codes.
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, lcsc.ErrProductNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, lcsc.ErrUnsupportedCurrency):
+		return http.StatusBadRequest
+	case errors.Is(err, lcsc.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, lcsc.ErrServiceUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// writeError writes a JSON error body.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	lcsc "github.com/PatrickWalther/go-lcsc"
+)
+
+// upstreamProducts are served by the stand-in LCSC API.
+var upstreamProducts = map[string]lcsc.Product{
+	"C1": {ProductCode: "C1", ProductModel: "CL10B104KB8NNNC", BrandNameEn: "Samsung", EncapStandard: "0603",
+		StockNumber: 100000, MinPacketNumber: 100,
+		ProductPriceList: []lcsc.PriceBreak{{Ladder: 100, ProductPrice: 0.002, CurrencySymbol: "US$"}}},
+	"C2": {ProductCode: "C2", ProductModel: "GRM188R71H104KA93D", BrandNameEn: "Murata", EncapStandard: "0603",
+		StockNumber: 0, MinPacketNumber: 100,
+		ProductPriceList: []lcsc.PriceBreak{{Ladder: 100, ProductPrice: 0.001, CurrencySymbol: "US$"}}},
+}
+
+// newTestProxy starts a stand-in LCSC API and a proxy in front of it. The
+// returned counter is the number of upstream requests.
+func newTestProxy(t *testing.T, delay time.Duration) (*server, string, *int32) {
+	t.Helper()
+	var requests int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		time.Sleep(delay)
+		var result interface{}
+		switch r.URL.Path {
+		case "/search/v2/global":
+			result = map[string]interface{}{
+				"productSearchResultVO": map[string]interface{}{
+					"productList": []lcsc.Product{upstreamProducts["C1"], upstreamProducts["C2"]},
+					"totalCount":  2,
+				},
+			}
+		case "/product/detail":
+			p, ok := upstreamProducts[r.URL.Query().Get("productCode")]
+			if !ok {
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 404, "msg": "not found"})
+				return
+			}
+			result = p
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 200, "result": result})
+	}))
+	t.Cleanup(upstream.Close)
+
+	cache := lcsc.NewMemoryCache(time.Minute)
+	t.Cleanup(cache.Close)
+	client := lcsc.NewClient(
+		lcsc.WithBaseURL(upstream.URL),
+		lcsc.WithRateLimit(1000),
+		lcsc.WithRetryConfig(lcsc.NoRetry()),
+		lcsc.WithCache(cache),
+	)
+	s := newServer(client, config{Timeout: 5 * time.Second, MaxBatch: 3, ReadyProduct: "C1"})
+	proxy := httptest.NewServer(s.handler())
+	t.Cleanup(proxy.Close)
+	return s, proxy.URL, &requests
+}
+
+// getJSON performs a request and decodes the JSON response into v.
+func getJSON(t *testing.T, method, url, body string, v interface{}) int {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("invalid JSON from %s: %v", url, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// TestProduct tests product lookups and error mapping.
+func TestProduct(t *testing.T) {
+	_, url, _ := newTestProxy(t, 0)
+
+	var product lcsc.Product
+	if status := getJSON(t, "GET", url+"/v1/products/c1", "", &product); status != http.StatusOK || product.ProductModel != "CL10B104KB8NNNC" {
+		t.Errorf("unexpected response %d: %+v", status, product)
+	}
+
+	var errBody map[string]string
+	if status := getJSON(t, "GET", url+"/v1/products/C999", "", &errBody); status != http.StatusNotFound || errBody["error"] == "" {
+		t.Errorf("expected 404 with error, got %d: %v", status, errBody)
+	}
+	if status := getJSON(t, "GET", url+"/v1/products/C1?currency=XYZ", "", &errBody); status != http.StatusBadRequest {
+		t.Errorf("expected 400 for unsupported currency, got %d", status)
+	}
+	if status := getJSON(t, "POST", url+"/v1/products/C1", "{}", nil); status != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", status)
+	}
+}
+
+// TestCoalescing tests that concurrent identical lookups reach LCSC once.
+func TestCoalescing(t *testing.T) {
+	_, url, requests := newTestProxy(t, 100*time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var product lcsc.Product
+			if status := getJSON(t, "GET", url+"/v1/products/C1", "", &product); status != http.StatusOK {
+				t.Errorf("unexpected status %d", status)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("expected 1 upstream request, got %d", n)
+	}
+}
+
+// TestSearch tests search with filter and sort parameters.
+func TestSearch(t *testing.T) {
+	_, url, requests := newTestProxy(t, 0)
+
+	var resp searchResponse
+	if status := getJSON(t, "GET", url+"/v1/search?q=100nF&sort=price", "", &resp); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+	if len(resp.Products) != 2 || resp.Products[0].ProductCode != "C2" || resp.TotalCount != 2 {
+		t.Errorf("expected price order, got %+v", resp)
+	}
+
+	if getJSON(t, "GET", url+"/v1/search?q=100nF&in_stock=true&qty=1000", "", &resp); len(resp.Products) != 1 || resp.Products[0].ProductCode != "C1" {
+		t.Errorf("expected in-stock filter, got %+v", resp.Products)
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("expected filtered searches to share the cached response, got %d requests", n)
+	}
+
+	if status := getJSON(t, "GET", url+"/v1/search?q=x&sort=size", "", nil); status != http.StatusBadRequest {
+		t.Errorf("expected 400 for unknown sort, got %d", status)
+	}
+	if status := getJSON(t, "GET", url+"/v1/search", "", nil); status != http.StatusBadRequest {
+		t.Errorf("expected 400 without q, got %d", status)
+	}
+}
+
+// TestBatch tests batch lookups with partial failures and limits.
+func TestBatch(t *testing.T) {
+	_, url, _ := newTestProxy(t, 0)
+
+	var resp batchResponse
+	status := getJSON(t, "POST", url+"/v1/products/batch", `{"codes": ["C1", "c2", "C1", "C999"]}`, &resp)
+	if status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+	if len(resp.Products) != 2 || resp.Products["C2"] == nil || resp.Errors["C999"] == "" {
+		t.Errorf("unexpected batch response: %+v", resp)
+	}
+
+	if status := getJSON(t, "POST", url+"/v1/products/batch", `{"codes": ["C1", "C2", "C3", "C4"]}`, nil); status != http.StatusBadRequest {
+		t.Errorf("expected 400 for an oversized batch, got %d", status)
+	}
+	if status := getJSON(t, "POST", url+"/v1/products/batch", `{"codes": []}`, nil); status != http.StatusBadRequest {
+		t.Errorf("expected 400 for an empty batch, got %d", status)
+	}
+}
+
+// TestBOM tests BOM pricing from JSON and CSV bodies.
+func TestBOM(t *testing.T) {
+	_, url, _ := newTestProxy(t, 0)
+
+	var costing struct {
+		BuildQuantity int     `json:"buildQuantity"`
+		Total         float64 `json:"total"`
+		Lines         []struct {
+			ProductCode string `json:"productCode"`
+			OrderQty    int    `json:"orderQty"`
+		} `json:"lines"`
+	}
+	body := `{"buildQuantity": 10, "lines": [{"designators": ["C1", "C2"], "lcscCode": "C1"}]}`
+	if status := getJSON(t, "POST", url+"/v1/bom", body, &costing); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+	if costing.BuildQuantity != 10 || len(costing.Lines) != 1 || costing.Lines[0].OrderQty != 100 {
+		t.Errorf("unexpected costing: %+v", costing)
+	}
+
+	resp, err := http.Post(url+"/v1/bom?qty=2&input=jlcpcb", "text/csv",
+		bytes.NewBufferString("Comment,Designator,Footprint,LCSC Part #\n100nF,C1,0603,C1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("unexpected status %d for a JLCPCB BOM", resp.StatusCode)
+	}
+
+	if status := getJSON(t, "POST", url+"/v1/bom?qty=0", body, nil); status != http.StatusBadRequest {
+		t.Errorf("expected 400 for qty 0, got %d", status)
+	}
+}
+
+// TestHealthAndReady tests liveness and readiness endpoints.
+func TestHealthAndReady(t *testing.T) {
+	s, url, requests := newTestProxy(t, 0)
+
+	var body map[string]string
+	if status := getJSON(t, "GET", url+"/healthz", "", &body); status != http.StatusOK || body["status"] != "ok" {
+		t.Errorf("unexpected health %d: %v", status, body)
+	}
+	for i := 0; i < 2; i++ {
+		if status := getJSON(t, "GET", url+"/readyz", "", &body); status != http.StatusOK || body["status"] != "ready" {
+			t.Errorf("unexpected readiness %d: %v", status, body)
+		}
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("expected one probe request, got %d", n)
+	}
+
+	s.draining.Store(true)
+	if status := getJSON(t, "GET", url+"/readyz", "", &body); status != http.StatusServiceUnavailable || body["status"] != "draining" {
+		t.Errorf("expected draining, got %d: %v", status, body)
+	}
+}
+
+// TestCacheTTLFlag tests that -cache-ttl sets the lifetime of cached lookups.
+func TestCacheTTLFlag(t *testing.T) {
+	var requests int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 200, "result": upstreamProducts["C1"]})
+	}))
+	t.Cleanup(upstream.Close)
+
+	o, err := parseFlags([]string{"-base-url", upstream.URL, "-cache-ttl", "50ms", "-rate", "1000"}, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := o.client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookup := func() {
+		t.Helper()
+		if _, err := client.GetProductDetails(context.Background(), "C1"); err != nil {
+			t.Fatalf("GetProductDetails failed: %v", err)
+		}
+	}
+
+	lookup()
+	lookup()
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Fatalf("expected 1 upstream request while cached, got %d", n)
+	}
+	time.Sleep(100 * time.Millisecond)
+	lookup()
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("expected a new upstream request after the TTL, got %d requests", n)
+	}
+}
+
+// TestParseFlagsCurrency tests validating the default currency.
+func TestParseFlagsCurrency(t *testing.T) {
+	if _, err := parseFlags([]string{"-currency", "XYZ"}, io.Discard); !errors.Is(err, lcsc.ErrUnsupportedCurrency) {
+		t.Errorf("expected ErrUnsupportedCurrency, got %v", err)
+	}
+	if _, err := parseFlags([]string{"-currency", "eur"}, io.Discard); err != nil {
+		t.Errorf("unexpected error for eur: %v", err)
+	}
+}
+
+// TestGroupPanic tests that a panicking lookup fails its callers and does
+// not leave the key in flight.
+func TestGroupPanic(t *testing.T) {
+	g := newGroup(time.Second)
+	_, err := g.do(context.Background(), "k", func(context.Context) (interface{}, error) {
+		panic("boom")
+	})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected panic error, got %v", err)
+	}
+
+	val, err := g.do(context.Background(), "k", func(context.Context) (interface{}, error) {
+		return 1, nil
+	})
+	if err != nil || val != 1 {
+		t.Errorf("expected a fresh lookup, got %v, %v", val, err)
+	}
+}
+
+// TestReadyConcurrent tests that concurrent probes share one upstream
+// lookup.
+func TestReadyConcurrent(t *testing.T) {
+	_, url, requests := newTestProxy(t, 50*time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if status := getJSON(t, "GET", url+"/readyz", "", nil); status != http.StatusOK {
+				t.Errorf("unexpected readiness status %d", status)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("expected one probe request, got %d", n)
+	}
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	lcsc "github.com/PatrickWalther/go-lcsc"
+	"github.com/PatrickWalther/go-lcsc/bom"
+)
+
+// parseArgs parses flags and checks the number of positional arguments.
+// A negative max means no upper limit.
+func parseArgs(fs *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		return nil, errUsage
+	}
+	rest := fs.Args()
+	if len(rest) < min || (max >= 0 && len(rest) > max) {
+		fs.Usage()
+		return nil, errUsage
+	}
+	return rest, nil
+}
+
+// runSearch implements "lcsc search".
+func runSearch(ctx context.Context, e *env, args []string) error {
+	fs := e.flagSet("search")
+	qty := fs.Int("qty", 1, "quantity for stock checks and price sorting")
+	inStock := fs.Bool("in-stock", false, "only show products with stock for -qty units")
+	pkg := fs.String("package", "", "only show products in this package, like 0603")
+	brand := fs.String("brand", "", "only show products from this manufacturer")
+	sortBy := fs.String("sort", "relevance", "sort order: relevance, price or stock")
+	rest, err := parseArgs(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	req := lcsc.SearchRequest{Keyword: strings.Join(rest, " ")}
+	if *inStock {
+		req.Filters = append(req.Filters, lcsc.InStock(*qty))
+	}
+	if *pkg != "" {
+		req.Filters = append(req.Filters, lcsc.Package(*pkg))
+	}
+	if *brand != "" {
+		req.Filters = append(req.Filters, lcsc.Brand(*brand))
+	}
+	switch *sortBy {
+	case "relevance":
+	case "price":
+		req.Sort = []lcsc.Order{lcsc.ByUnitPrice(*qty)}
+	case "stock":
+		req.Sort = []lcsc.Order{lcsc.ByStock()}
+	default:
+		return fmt.Errorf("unknown sort order %q", *sortBy)
+	}
+
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.KeywordSearch(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	header := []string{"Code", "MPN", "Manufacturer", "Package", "Stock", "Unit Price", "Description"}
+	rows := make([][]string, 0, len(resp.Products))
+	for _, p := range resp.Products {
+		description := p.ProductIntroEn
+		if e.format == "table" {
+			description = truncate(description, 60)
+		}
+		rows = append(rows, []string{
+			p.ProductCode,
+			p.ProductModel,
+			p.BrandNameEn,
+			p.EncapStandard,
+			strconv.Itoa(int(p.StockNumber)),
+			formatUnitPrice(&p, int(p.MinPacketNumber)),
+			description,
+		})
+	}
+	return writeOutput(e.stdout, e.format, header, rows, resp)
+}
+
+// runShow implements "lcsc show".
+func runShow(ctx context.Context, e *env, args []string) error {
+	fs := e.flagSet("show")
+	rest, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	p, err := client.GetProductDetails(ctx, rest[0])
+	if err != nil {
+		return err
+	}
+
+	header := []string{"Field", "Value"}
+	rows := [][]string{
+		{"Code", p.ProductCode},
+		{"MPN", p.ProductModel},
+		{"Manufacturer", p.BrandNameEn},
+		{"Description", p.ProductIntroEn},
+		{"Category", strings.Trim(p.ParentCatalogName+" / "+p.CatalogName, " /")},
+		{"Package", p.EncapStandard},
+		{"Stock", strconv.Itoa(int(p.StockNumber))},
+		{"Min Order", strconv.Itoa(int(p.MinPacketNumber))},
+		{"Datasheet", p.PdfUrl},
+		{"URL", p.GetProductURL()},
+	}
+	for _, pb := range p.ProductPriceList {
+		rows = append(rows, []string{
+			fmt.Sprintf("Price %d+", pb.Ladder),
+			pb.CurrencySymbol + strconv.FormatFloat(float64(pb.ProductPrice), 'f', -1, 64),
+		})
+	}
+	for _, param := range p.ParamVOList {
+		rows = append(rows, []string{param.ParamNameEn, param.ParamValueEn})
+	}
+	return writeOutput(e.stdout, e.format, header, rows, p)
+}
+
+// runPrice implements "lcsc price".
+func runPrice(ctx context.Context, e *env, args []string) error {
+	fs := e.flagSet("price")
+	rest, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	qty, err := strconv.Atoi(rest[1])
+	if err != nil || qty <= 0 {
+		return fmt.Errorf("quantity must be a positive integer, got %q", rest[1])
+	}
+
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	b := &bom.BOM{Lines: []bom.Line{{LCSCCode: rest[0], Quantity: qty}}}
+	costing, err := bom.Cost(ctx, client, b, bom.CostOptions{BuildQuantity: 1})
+	if err != nil {
+		return err
+	}
+	line := costing.Lines[0]
+	if line.Err != nil {
+		return line.Err
+	}
+
+	header := []string{"Code", "Requested", "Order Qty", "Price Tier", "Unit Price", "Extended", "Currency", "Stock", "Status"}
+	rows := [][]string{{
+		line.Product.ProductCode,
+		strconv.Itoa(line.RequiredQty),
+		strconv.Itoa(line.OrderQty),
+		strconv.Itoa(line.PriceTier),
+		strconv.FormatFloat(line.UnitPrice, 'f', -1, 64),
+		strconv.FormatFloat(line.ExtendedPrice, 'f', 2, 64),
+		costing.Currency,
+		strconv.Itoa(int(line.Product.StockNumber)),
+		string(line.Status),
+	}}
+	value := map[string]interface{}{
+		"productCode":   line.Product.ProductCode,
+		"requestedQty":  line.RequiredQty,
+		"orderQty":      line.OrderQty,
+		"priceTier":     line.PriceTier,
+		"unitPrice":     line.UnitPrice,
+		"extendedPrice": line.ExtendedPrice,
+		"currency":      costing.Currency,
+		"stock":         int(line.Product.StockNumber),
+		"status":        line.Status,
+	}
+	return writeOutput(e.stdout, e.format, header, rows, value)
+}
+
+// runBOM implements "lcsc bom".
+func runBOM(ctx context.Context, e *env, args []string) error {
+	fs := e.flagSet("bom")
+	qty := fs.Int("qty", 1, "number of boards to build")
+	input := fs.String("input", "auto", "input format: auto, csv, kicad, kicad-xml or jlcpcb")
+	fs.Lookup("format").Usage = "output format: table, json, csv, jlcpcb, cart, markdown or html"
+	rest, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if *qty <= 0 {
+		return fmt.Errorf("-qty must be positive")
+	}
+
+	b, err := readBOM(rest[0], *input)
+	if err != nil {
+		return err
+	}
+
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	costing, err := bom.Cost(ctx, client, b, bom.CostOptions{BuildQuantity: *qty})
+	if err != nil {
+		return err
+	}
+
+	switch e.format {
+	case "table":
+		return writeBOMTable(e, costing)
+	case "json":
+		return bom.WriteJSON(e.stdout, costing)
+	case "csv":
+		return bom.WriteCSV(e.stdout, costing)
+	case "jlcpcb":
+		return bom.WriteJLCPCB(e.stdout, costing)
+	case "cart":
+		return bom.WriteLCSCCart(e.stdout, costing)
+	case "markdown":
+		return bom.WriteMarkdown(e.stdout, costing)
+	case "html":
+		return bom.WriteHTML(e.stdout, costing)
+	default:
+		return fmt.Errorf("unknown format %q", e.format)
+	}
+}
+
+// readBOM parses a BOM file. In auto mode, .xml files are read as KiCad XML
+// and everything else as CSV with the default column mapping.
+func readBOM(path, format string) (*bom.BOM, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if format == "auto" {
+		format = "csv"
+		if strings.EqualFold(filepath.Ext(path), ".xml") {
+			format = "kicad-xml"
+		}
+	}
+
+	var b *bom.BOM
+	switch format {
+	case "csv":
+		b, err = bom.ParseCSV(f, bom.DefaultColumnMapping())
+	case "kicad":
+		b, err = bom.ParseKiCadCSV(f)
+	case "kicad-xml":
+		b, err = bom.ParseKiCadXML(f)
+	case "jlcpcb":
+		b, err = bom.ParseJLCPCB(f)
+	default:
+		return nil, fmt.Errorf("unknown input format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if b.Name == "" {
+		b.Name = filepath.Base(path)
+	}
+	return b, nil
+}
+
+// writeBOMTable writes a costed BOM as an aligned table with a total line.
+func writeBOMTable(e *env, c *bom.Costing) error {
+	header := []string{"Designators", "Code", "MPN", "Order Qty", "Unit Price", "Extended", "Status"}
+	rows := make([][]string, 0, len(c.Lines)+1)
+	for _, line := range c.Lines {
+		code, mpn := line.Line.LCSCCode, line.Line.MPN
+		if line.Product != nil {
+			code, mpn = line.Product.ProductCode, line.Product.ProductModel
+		}
+		rows = append(rows, []string{
+			truncate(strings.Join(line.Line.Designators, ","), 30),
+			code,
+			mpn,
+			strconv.Itoa(line.OrderQty),
+			strconv.FormatFloat(line.UnitPrice, 'f', 4, 64),
+			strconv.FormatFloat(line.ExtendedPrice, 'f', 2, 64),
+			string(line.Status),
+		})
+	}
+	rows = append(rows, []string{
+		"TOTAL", "", "", "", "",
+		c.CurrencySymbol + strconv.FormatFloat(c.Total, 'f', 2, 64),
+		fmt.Sprintf("%s%.4f per board", c.CurrencySymbol, c.PerBoard()),
+	})
+	return writeTable(e.stdout, header, rows)
+}
+
+// formatUnitPrice formats the unit price at qty with its currency symbol.
+func formatUnitPrice(p *lcsc.Product, qty int) string {
+	pb, ok := p.PriceAt(qty)
+	if !ok {
+		return ""
+	}
+	return pb.CurrencySymbol + strconv.FormatFloat(float64(pb.ProductPrice), 'f', -1, 64)
+}
@@ -0,0 +1,174 @@
+// Command lcsc is a command-line client for the LCSC electronics catalog.
+//
+// Usage:
+//
+//	lcsc search [flags] <keyword>...
+//	lcsc show [flags] <code>
+//	lcsc price [flags] <code> <quantity>
+//	lcsc bom [flags] <file>
+//
+// Every command accepts -currency, -rate, -cache-dir, -cache-ttl, -retries,
+// -timeout and -format. Run "lcsc <command> -h" for command-specific flags.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	lcsc "github.com/PatrickWalther/go-lcsc"
+)
+
+// errUsage signals a usage error; the flag package has already printed help.
+var errUsage = errors.New("usage error")
+
+// command is a CLI subcommand.
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(ctx context.Context, env *env, args []string) error
+}
+
+// commands lists the subcommands. It is populated in init because the
+// command functions refer back to it for their usage text.
+var commands []command
+
+func init() {
+	commands = []command{
+		{"search", "<keyword>...", "Search products by keyword", runSearch},
+		{"show", "<code>", "Show product details", runShow},
+		{"price", "<code> <quantity>", "Price a quantity of one product", runPrice},
+		{"bom", "<file>", "Resolve and price a BOM file", runBOM},
+	}
+}
+
+// env carries the parsed common flags and output streams of a command.
+type env struct {
+	stdout io.Writer
+	stderr io.Writer
+
+	currency string
+	rate     float64
+	cacheDir string
+	cacheTTL time.Duration
+	retries  int
+	timeout  time.Duration
+	baseURL  string
+	format   string
+}
+
+// flagSet returns a FlagSet for the named command with the common client
+// flags registered.
+func (e *env) flagSet(name string) *flag.FlagSet {
+	var cmd command
+	for _, c := range commands {
+		if c.name == name {
+			cmd = c
+		}
+	}
+
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.StringVar(&e.currency, "currency", "USD", "currency code for prices")
+	fs.Float64Var(&e.rate, "rate", 5, "rate limit in requests per second")
+	fs.StringVar(&e.cacheDir, "cache-dir", "", "directory for a persistent response cache (disabled if empty)")
+	fs.DurationVar(&e.cacheTTL, "cache-ttl", 10*time.Minute, "time to live for cached responses")
+	fs.IntVar(&e.retries, "retries", lcsc.DefaultRetryConfig().MaxRetries, "maximum retries for transient failures")
+	fs.DurationVar(&e.timeout, "timeout", 30*time.Second, "HTTP request timeout")
+	fs.StringVar(&e.baseURL, "base-url", "", "override the LCSC API base URL")
+	fs.StringVar(&e.format, "format", "table", "output format: table, json or csv")
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: lcsc %s [flags] %s\n\n%s.\n\nFlags:\n", cmd.name, cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// client builds an lcsc.Client from the common flags.
+func (e *env) client() (*lcsc.Client, error) {
+	if e.rate <= 0 {
+		return nil, fmt.Errorf("-rate must be positive")
+	}
+
+	retry := lcsc.NoRetry()
+	if e.retries > 0 {
+		retry = lcsc.DefaultRetryConfig()
+		retry.MaxRetries = e.retries
+	}
+
+	opts := []lcsc.ClientOption{
+		lcsc.WithCurrency(e.currency),
+		lcsc.WithRateLimit(e.rate),
+		lcsc.WithRetryConfig(retry),
+		lcsc.WithHTTPClient(&http.Client{Timeout: e.timeout}),
+	}
+	if e.baseURL != "" {
+		opts = append(opts, lcsc.WithBaseURL(e.baseURL))
+	}
+	if e.cacheDir != "" {
+		cache, err := lcsc.NewFileCache(e.cacheDir, e.cacheTTL)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, lcsc.WithCache(cache), lcsc.WithCacheTTL(e.cacheTTL))
+	}
+	return lcsc.NewClient(opts...), nil
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the CLI and returns the process exit code.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(ctx, &env{stdout: stdout, stderr: stderr}, args[1:])
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errUsage):
+			return 2
+		default:
+			fmt.Fprintf(stderr, "lcsc %s: %v\n", cmd.name, err)
+			return 1
+		}
+	}
+
+	fmt.Fprintf(stderr, "lcsc: unknown command %q\n\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+// usage prints the top-level help.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: lcsc <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "lcsc <command> -h" for command flags.`)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	lcsc "github.com/PatrickWalther/go-lcsc"
+)
+
+// testProduct is served by newTestServer.
+var testProduct = lcsc.Product{
+	ProductCode:     "C8734",
+	ProductModel:    "STM32F103C8T6",
+	BrandNameEn:     "STMicroelectronics",
+	ProductIntroEn:  "ARM Cortex-M3 MCU",
+	EncapStandard:   "LQFP-48",
+	StockNumber:     1000,
+	MinPacketNumber: 1,
+	ProductPriceList: []lcsc.PriceBreak{
+		{Ladder: 1, ProductPrice: 3.5, CurrencySymbol: "US$"},
+		{Ladder: 10, ProductPrice: 3.0, CurrencySymbol: "US$"},
+	},
+	ParamVOList: []lcsc.Parameter{{ParamNameEn: "Core", ParamValueEn: "ARM Cortex-M3"}},
+}
+
+// newTestServer starts a stand-in LCSC API and returns its URL.
+func newTestServer(t *testing.T) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var result interface{}
+		switch r.URL.Path {
+		case "/search/v2/global":
+			result = map[string]interface{}{
+				"productSearchResultVO": map[string]interface{}{
+					"productList": []lcsc.Product{testProduct},
+					"totalCount":  1,
+				},
+			}
+		case "/product/detail":
+			if r.URL.Query().Get("productCode") != testProduct.ProductCode {
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 404, "msg": "not found"})
+				return
+			}
+			result = testProduct
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 200, "msg": "", "result": result})
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// runCLI runs the CLI against the test server and returns exit code and output.
+func runCLI(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+	if len(args) > 0 {
+		args = append([]string{args[0], "-base-url", newTestServer(t), "-retries", "0"}, args[1:]...)
+	}
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// TestRunSearchTable tests the search command with table output.
+func TestRunSearchTable(t *testing.T) {
+	code, out, errOut := runCLI(t, "search", "STM32F103")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, errOut)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected header and one row, got:\n%s", out)
+	}
+	if !strings.HasPrefix(lines[0], "Code") || !strings.Contains(lines[1], "C8734") || !strings.Contains(lines[1], "US$3.5") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+// TestRunSearchFilters tests search filter and sort flags.
+func TestRunSearchFilters(t *testing.T) {
+	code, out, errOut := runCLI(t, "search", "-in-stock", "-qty", "5000", "STM32F103")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, errOut)
+	}
+	if strings.Contains(out, "C8734") {
+		t.Errorf("expected the product to be filtered out:\n%s", out)
+	}
+
+	code, out, errOut = runCLI(t, "search", "-package", "lqfp-48", "-sort", "price", "STM32F103")
+	if code != 0 || !strings.Contains(out, "C8734") {
+		t.Errorf("expected the product to match, got %d: %s%s", code, out, errOut)
+	}
+
+	if code, _, _ := runCLI(t, "search", "-sort", "size", "STM32F103"); code == 0 {
+		t.Error("expected an error for an unknown sort order")
+	}
+}
+
+// TestRunShowJSON tests the show command with JSON output.
+func TestRunShowJSON(t *testing.T) {
+	code, out, errOut := runCLI(t, "show", "-format", "json", "C8734")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, errOut)
+	}
+
+	var product lcsc.Product
+	if err := json.Unmarshal([]byte(out), &product); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if product.ProductModel != "STM32F103C8T6" {
+		t.Errorf("unexpected product: %+v", product)
+	}
+}
+
+// TestRunShowNotFound tests error reporting for an unknown product.
+func TestRunShowNotFound(t *testing.T) {
+	code, _, errOut := runCLI(t, "show", "C1")
+	if code != 1 || !strings.Contains(errOut, "not found") {
+		t.Errorf("expected not found error, got %d: %s", code, errOut)
+	}
+}
+
+// TestRunPriceCSV tests the price command with CSV output.
+func TestRunPriceCSV(t *testing.T) {
+	code, out, errOut := runCLI(t, "price", "-format", "csv", "C8734", "25")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, errOut)
+	}
+
+	expected := "Code,Requested,Order Qty,Price Tier,Unit Price,Extended,Currency,Stock,Status\n" +
+		"C8734,25,25,10,3,75.00,USD,1000,ok\n"
+	if out != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", out, expected)
+	}
+}
+
+// TestRunBOM tests the bom command with a JLCPCB BOM file.
+func TestRunBOM(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "board.csv")
+	data := "Comment,Designator,Footprint,LCSC Part #\nSTM32,U1,LQFP-48,C8734\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, out, errOut := runCLI(t, "bom", "-input", "jlcpcb", "-qty", "10", "-format", "cart", path)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, errOut)
+	}
+	if out != "C8734,10\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+
+	code, out, _ = runCLI(t, "bom", "-qty", "10", path)
+	if code != 0 || !strings.Contains(out, "TOTAL") || !strings.Contains(out, "US$30.00") {
+		t.Errorf("unexpected table output (%d):\n%s", code, out)
+	}
+}
+
+// TestRunUsageErrors tests exit codes for usage errors.
+func TestRunUsageErrors(t *testing.T) {
+	tests := [][]string{
+		{},
+		{"frobnicate"},
+		{"show"},
+		{"price", "C8734"},
+		{"search", "-no-such-flag", "x"},
+	}
+
+	for _, args := range tests {
+		var stdout, stderr bytes.Buffer
+		if code := run(context.Background(), args, &stdout, &stderr); code != 2 {
+			t.Errorf("expected exit code 2 for %v, got %d", args, code)
+		}
+	}
+}
+
+// TestRunCacheDir tests that -cache-dir enables a persistent cache.
+func TestRunCacheDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+
+	code, _, errOut := runCLI(t, "show", "-cache-dir", dir, "C8734")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, errOut)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) == 0 {
+		t.Errorf("expected cache entries in %s, got %v (%v)", dir, entries, err)
+	}
+}
@@ -0,0 +1,120 @@
+package lcsc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrUnsupportedCurrency is returned for currency codes LCSC does not price in.
+var ErrUnsupportedCurrency = errors.New("lcsc: unsupported currency")
+
+// Currency describes a currency LCSC prices products in.
+type Currency struct {
+	Code   string // ISO 4217 code, like "USD"
+	Symbol string // Symbol as in PriceBreak.CurrencySymbol, like "US$"
+	Name   string
+}
+
+// currencies lists the supported currencies, default first.
+var currencies = []Currency{
+	{Code: "USD", Symbol: "US$", Name: "US Dollar"},
+	{Code: "EUR", Symbol: "€", Name: "Euro"},
+	{Code: "GBP", Symbol: "£", Name: "British Pound"},
+	{Code: "CNY", Symbol: "¥", Name: "Chinese Yuan"},
+	{Code: "JPY", Symbol: "JP¥", Name: "Japanese Yen"},
+	{Code: "AUD", Symbol: "A$", Name: "Australian Dollar"},
+	{Code: "CAD", Symbol: "C$", Name: "Canadian Dollar"},
+	{Code: "HKD", Symbol: "HK$", Name: "Hong Kong Dollar"},
+	{Code: "SGD", Symbol: "S$", Name: "Singapore Dollar"},
+	{Code: "CHF", Symbol: "CHF", Name: "Swiss Franc"},
+	{Code: "KRW", Symbol: "₩", Name: "South Korean Won"},
+	{Code: "INR", Symbol: "₹", Name: "Indian Rupee"},
+}
+
+// Currencies returns the supported currencies.
+func Currencies() []Currency {
+	return append([]Currency(nil), currencies...)
+}
+
+// LookupCurrency returns the supported currency with the given code,
+// ignoring case and surrounding space.
+func LookupCurrency(code string) (Currency, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	for _, c := range currencies {
+		if c.Code == code {
+			return c, true
+		}
+	}
+	return Currency{}, false
+}
+
+// CurrencyBySymbol returns the supported currency with the given symbol.
+func CurrencyBySymbol(symbol string) (Currency, bool) {
+	symbol = strings.TrimSpace(symbol)
+	for _, c := range currencies {
+		if c.Symbol == symbol {
+			return c, true
+		}
+	}
+	return Currency{}, false
+}
+
+// InCurrency returns a client for prices in another currency. It shares the
+// receiver's HTTP client, rate limiter, cache and session, with cache
+// entries kept apart per currency. An unsupported currency set on the
+// receiver does not carry over to the returned client.
+func (c *Client) InCurrency(code string) (*Client, error) {
+	cur, ok := LookupCurrency(code)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedCurrency, code)
+	}
+	clone := *c
+	clone.currency = cur.Code
+	if errors.Is(clone.err, ErrUnsupportedCurrency) {
+		clone.err = nil
+	}
+	return &clone, nil
+}
+
+// GetProductInCurrencies fetches a product's details in each of the given
+// currencies concurrently, keyed by currency code. Currencies that fail are
+// missing from the result and reported in the joined error.
+func (c *Client) GetProductInCurrencies(ctx context.Context, productCode string, codes ...string) (map[string]*Product, error) {
+	var errs []error
+	clients := make(map[string]*Client, len(codes))
+	for _, code := range codes {
+		cc, err := c.InCurrency(code)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		clients[cc.currency] = cc
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		products = make(map[string]*Product, len(clients))
+	)
+	for code, cc := range clients {
+		wg.Add(1)
+		go func(code string, cc *Client) {
+			defer wg.Done()
+			product, err := cc.GetProductDetails(ctx, productCode)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", code, err))
+				return
+			}
+			products[code] = product
+		}(code, cc)
+	}
+	wg.Wait()
+
+	return products, errors.Join(errs...)
+}
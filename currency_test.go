@@ -0,0 +1,166 @@
+package lcsc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// currencyHandler serves product details and searches priced in the
+// currency of the currencyCode cookie.
+func currencyHandler(t *testing.T, requests *int32) http.HandlerFunc {
+	symbols := map[string]string{"USD": "US$", "EUR": "€", "CNY": "¥"}
+	prices := map[string]float64{"USD": 1, "EUR": 0.9, "CNY": 7}
+	return func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		cookie, err := r.Cookie("currencyCode")
+		if err != nil {
+			t.Errorf("missing currency cookie")
+			return
+		}
+		cur := cookie.Value
+		product := Product{
+			ProductCode:      "C1",
+			ProductPriceList: []PriceBreak{{Ladder: 1, ProductPrice: FlexFloat64(prices[cur]), CurrencySymbol: symbols[cur]}},
+		}
+		if r.Method == http.MethodPost {
+			writeAPIResult(t, w, map[string]interface{}{
+				"productSearchResultVO": map[string]interface{}{"productList": []Product{product}, "totalCount": 1},
+			})
+			return
+		}
+		writeAPIResult(t, w, product)
+	}
+}
+
+// TestLookupCurrency tests the currency catalog.
+func TestLookupCurrency(t *testing.T) {
+	cur, ok := LookupCurrency(" eur ")
+	if !ok || cur.Code != "EUR" || cur.Symbol != "€" {
+		t.Errorf("unexpected currency: %+v, %v", cur, ok)
+	}
+	if _, ok := LookupCurrency("XYZ"); ok {
+		t.Error("expected XYZ to be unsupported")
+	}
+	if cur, ok := CurrencyBySymbol("US$"); !ok || cur.Code != "USD" {
+		t.Errorf("unexpected currency for US$: %+v", cur)
+	}
+	if list := Currencies(); len(list) == 0 || list[0].Code != defaultCurrency {
+		t.Errorf("expected the default currency first, got %+v", list)
+	}
+}
+
+// TestWithCurrencyValidation tests that unsupported currencies fail requests.
+func TestWithCurrencyValidation(t *testing.T) {
+	var requests int32
+	client := newTestClient(t, currencyHandler(t, &requests), WithCurrency("XYZ"))
+
+	_, err := client.GetProductDetails(context.Background(), "C1")
+	if !errors.Is(err, ErrUnsupportedCurrency) {
+		t.Errorf("expected ErrUnsupportedCurrency, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected no requests, got %d", requests)
+	}
+
+	client = newTestClient(t, currencyHandler(t, &requests), WithCurrency("eur"))
+	if client.Currency() != "EUR" {
+		t.Errorf("expected normalized currency EUR, got %s", client.Currency())
+	}
+}
+
+// TestPerCallCurrency tests currency overrides and cache separation.
+func TestPerCallCurrency(t *testing.T) {
+	var requests int32
+	cache := NewMemoryCache(time.Minute)
+	defer cache.Close()
+	client := newTestClient(t, currencyHandler(t, &requests), WithCache(cache))
+	ctx := context.Background()
+
+	usd, err := client.GetProductDetails(ctx, "C1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	eurClient, err := client.InCurrency("EUR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	eur, err := eurClient.GetProductDetails(ctx, "C1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usd.ProductPriceList[0].CurrencySymbol != "US$" || eur.ProductPriceList[0].CurrencySymbol != "€" {
+		t.Errorf("expected prices in USD and EUR, got %s and %s",
+			usd.ProductPriceList[0].CurrencySymbol, eur.ProductPriceList[0].CurrencySymbol)
+	}
+	if client.Currency() != "USD" {
+		t.Error("expected the original client to keep its currency")
+	}
+
+	// Both currencies are now cached.
+	if _, err := client.GetProductDetails(ctx, "C1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := eurClient.GetProductDetails(ctx, "C1"); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+
+	cny, err := client.GetProductDetailsIn(ctx, "C1", "cny")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cny.ProductPriceList[0].CurrencySymbol != "¥" {
+		t.Errorf("expected details in CNY, got %+v", cny.ProductPriceList)
+	}
+	if _, err := client.GetProductDetailsIn(ctx, "C1", "CNY"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetProductDetailsIn(ctx, "C1", ""); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests with CNY cached separately, got %d", requests)
+	}
+	if _, err := client.GetProductDetailsIn(ctx, "C1", "XYZ"); !errors.Is(err, ErrUnsupportedCurrency) {
+		t.Errorf("expected ErrUnsupportedCurrency for details, got %v", err)
+	}
+
+	resp, err := client.KeywordSearch(ctx, SearchRequest{Keyword: "x", Currency: "CNY"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Products[0].ProductPriceList[0].CurrencySymbol != "¥" {
+		t.Errorf("expected search prices in CNY, got %+v", resp.Products[0].ProductPriceList)
+	}
+
+	if _, err := client.InCurrency("XYZ"); !errors.Is(err, ErrUnsupportedCurrency) {
+		t.Errorf("expected ErrUnsupportedCurrency, got %v", err)
+	}
+	if _, err := client.KeywordSearch(ctx, SearchRequest{Keyword: "x", Currency: "XYZ"}); !errors.Is(err, ErrUnsupportedCurrency) {
+		t.Errorf("expected ErrUnsupportedCurrency for search, got %v", err)
+	}
+}
+
+// TestGetProductInCurrencies tests fetching a product in several currencies.
+func TestGetProductInCurrencies(t *testing.T) {
+	var requests int32
+	client := newTestClient(t, currencyHandler(t, &requests))
+
+	products, err := client.GetProductInCurrencies(context.Background(), "C1", "USD", "eur", "EUR", "CNY", "XYZ")
+	if !errors.Is(err, ErrUnsupportedCurrency) || !strings.Contains(err.Error(), "XYZ") {
+		t.Errorf("expected error for XYZ, got %v", err)
+	}
+	if len(products) != 3 || requests != 3 {
+		t.Fatalf("expected 3 products from 3 requests, got %d from %d", len(products), requests)
+	}
+	if products["CNY"].UnitPrice(1) != 7 || products["EUR"].UnitPrice(1) != 0.9 {
+		t.Errorf("unexpected prices: %v and %v", products["CNY"].UnitPrice(1), products["EUR"].UnitPrice(1))
+	}
+}
@@ -0,0 +1,202 @@
+package lcsc
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChangeKind describes how a value differs between two products.
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"    // Present only in the new product
+	ChangeRemoved  ChangeKind = "removed"  // Present only in the old product
+	ChangeModified ChangeKind = "modified" // Present in both with different values
+)
+
+// FieldChange is a change to a scalar Product field.
+type FieldChange struct {
+	Field string // Go field name, like "StockNumber"
+	Old   string
+	New   string
+}
+
+// ParamChange is a change to a product parameter.
+type ParamChange struct {
+	Name  string // English name, or the Chinese name if there is none
+	Kind  ChangeKind
+	Old   string // Empty for ChangeAdded
+	New   string // Empty for ChangeRemoved
+	OldCn string // Chinese values, with LanguageChinese
+	NewCn string
+}
+
+// PriceBreakChange is a change to the price break at one ladder.
+type PriceBreakChange struct {
+	Ladder   int
+	Kind     ChangeKind
+	OldPrice float64 // Zero for ChangeAdded
+	NewPrice float64 // Zero for ChangeRemoved
+}
+
+// ProductDiff is the structured difference between two versions of a product.
+type ProductDiff struct {
+	ProductCode string
+	Fields      []FieldChange
+	Params      []ParamChange
+	PriceBreaks []PriceBreakChange
+}
+
+// Empty reports whether the diff contains no changes.
+func (d *ProductDiff) Empty() bool {
+	return len(d.Fields) == 0 && len(d.Params) == 0 && len(d.PriceBreaks) == 0
+}
+
+// DiffProducts compares two versions of a product. Scalar fields are
+// compared by value, parameters by name (case-insensitive) and price breaks
+// by ladder, so reordering alone is not a change. Chinese text fields and
+// parameter values are compared along with the English ones. Currency
+// symbols are not compared. A nil product is treated as empty.
+func DiffProducts(before, after *Product) *ProductDiff {
+	if before == nil {
+		before = &Product{}
+	}
+	if after == nil {
+		after = &Product{}
+	}
+
+	d := &ProductDiff{ProductCode: after.ProductCode}
+	if d.ProductCode == "" {
+		d.ProductCode = before.ProductCode
+	}
+
+	beforeFields, afterFields := productFields(before), productFields(after)
+	for i, f := range beforeFields {
+		if f.value != afterFields[i].value {
+			d.Fields = append(d.Fields, FieldChange{Field: f.name, Old: f.value, New: afterFields[i].value})
+		}
+	}
+
+	d.Params = diffParams(before.ParamVOList, after.ParamVOList)
+	d.PriceBreaks = diffPriceBreaks(before.ProductPriceList, after.ProductPriceList)
+	return d
+}
+
+// namedValue is a product field formatted for comparison.
+type namedValue struct {
+	name  string
+	value string
+}
+
+// productFields returns the scalar fields of p in declaration order.
+func productFields(p *Product) []namedValue {
+	return []namedValue{
+		{"ProductCode", p.ProductCode},
+		{"ProductModel", p.ProductModel},
+		{"BrandNameEn", p.BrandNameEn},
+		{"ProductIntroEn", p.ProductIntroEn},
+		{"PdfUrl", p.PdfUrl},
+		{"ProductImages", strings.Join(p.ProductImages, ", ")},
+		{"ProductImageUrl", p.ProductImageUrl},
+		{"StockNumber", strconv.Itoa(int(p.StockNumber))},
+		{"MinPacketNumber", strconv.Itoa(int(p.MinPacketNumber))},
+		{"EncapStandard", p.EncapStandard},
+		{"ParentCatalogName", p.ParentCatalogName},
+		{"CatalogName", p.CatalogName},
+		{"Weight", strconv.FormatFloat(float64(p.Weight), 'g', -1, 64)},
+		{"ProductNameEn", p.ProductNameEn},
+		{"ProductNameCn", p.ProductNameCn},
+		{"BrandNameCn", p.BrandNameCn},
+		{"ProductIntroCn", p.ProductIntroCn},
+		{"CatalogNameCn", p.CatalogNameCn},
+	}
+}
+
+// diffParams compares parameter lists by name, in the order of the after
+// list followed by removed parameters.
+func diffParams(before, after []Parameter) []ParamChange {
+	beforeValues := make(map[string]Parameter, len(before))
+	for _, p := range before {
+		beforeValues[paramKey(p)] = p
+	}
+
+	var changes []ParamChange
+	seen := make(map[string]bool, len(after))
+	for _, p := range after {
+		key := paramKey(p)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		prev, ok := beforeValues[key]
+		switch {
+		case !ok:
+			changes = append(changes, ParamChange{Name: paramName(p), Kind: ChangeAdded, New: p.ParamValueEn, NewCn: p.ParamValueCn})
+		case prev.ParamValueEn != p.ParamValueEn || prev.ParamValueCn != p.ParamValueCn:
+			changes = append(changes, ParamChange{
+				Name:  paramName(p),
+				Kind:  ChangeModified,
+				Old:   prev.ParamValueEn,
+				New:   p.ParamValueEn,
+				OldCn: prev.ParamValueCn,
+				NewCn: p.ParamValueCn,
+			})
+		}
+	}
+
+	for _, p := range before {
+		key := paramKey(p)
+		if !seen[key] {
+			seen[key] = true
+			changes = append(changes, ParamChange{Name: paramName(p), Kind: ChangeRemoved, Old: p.ParamValueEn, OldCn: p.ParamValueCn})
+		}
+	}
+	return changes
+}
+
+// paramName returns the English name of p, or its Chinese name if it has
+// no English one.
+func paramName(p Parameter) string {
+	if name := strings.TrimSpace(p.ParamNameEn); name != "" {
+		return p.ParamNameEn
+	}
+	return p.ParamNameCn
+}
+
+// paramKey returns the key parameters are matched by.
+func paramKey(p Parameter) string {
+	return strings.ToLower(strings.TrimSpace(paramName(p)))
+}
+
+// diffPriceBreaks compares price lists by ladder, sorted by ladder.
+func diffPriceBreaks(before, after []PriceBreak) []PriceBreakChange {
+	beforePrices := make(map[int]float64, len(before))
+	for _, pb := range before {
+		beforePrices[int(pb.Ladder)] = float64(pb.ProductPrice)
+	}
+	afterPrices := make(map[int]float64, len(after))
+	for _, pb := range after {
+		afterPrices[int(pb.Ladder)] = float64(pb.ProductPrice)
+	}
+
+	var changes []PriceBreakChange
+	for ladder, price := range afterPrices {
+		prev, ok := beforePrices[ladder]
+		switch {
+		case !ok:
+			changes = append(changes, PriceBreakChange{Ladder: ladder, Kind: ChangeAdded, NewPrice: price})
+		case prev != price:
+			changes = append(changes, PriceBreakChange{Ladder: ladder, Kind: ChangeModified, OldPrice: prev, NewPrice: price})
+		}
+	}
+	for ladder, price := range beforePrices {
+		if _, ok := afterPrices[ladder]; !ok {
+			changes = append(changes, PriceBreakChange{Ladder: ladder, Kind: ChangeRemoved, OldPrice: price})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Ladder < changes[j].Ladder })
+	return changes
+}
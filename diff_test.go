@@ -0,0 +1,159 @@
+package lcsc
+
+import "testing"
+
+// diffTestProduct returns a product used as the base of diff tests.
+func diffTestProduct() *Product {
+	return &Product{
+		ProductCode:     "C1525",
+		ProductModel:    "CL05B104KO5NNNC",
+		BrandNameEn:     "Samsung Electro-Mechanics",
+		StockNumber:     1000,
+		MinPacketNumber: 10,
+		Weight:          0.001,
+		ProductPriceList: []PriceBreak{
+			{Ladder: 10, ProductPrice: 0.0016},
+			{Ladder: 100, ProductPrice: 0.0012},
+		},
+		ParamVOList: []Parameter{
+			{ParamNameEn: "Capacitance", ParamValueEn: "100nF"},
+			{ParamNameEn: "Tolerance", ParamValueEn: "±10%"},
+		},
+	}
+}
+
+// TestDiffProductsIdentical tests that identical products produce an empty diff.
+func TestDiffProductsIdentical(t *testing.T) {
+	a, b := diffTestProduct(), diffTestProduct()
+	b.ProductPriceList[0], b.ProductPriceList[1] = b.ProductPriceList[1], b.ProductPriceList[0]
+	b.ParamVOList[0].ParamNameEn = "CAPACITANCE"
+
+	d := DiffProducts(a, b)
+	if !d.Empty() {
+		t.Errorf("expected empty diff, got %+v", d)
+	}
+	if d.ProductCode != "C1525" {
+		t.Errorf("expected ProductCode C1525, got %s", d.ProductCode)
+	}
+}
+
+// TestDiffProductsFields tests scalar field changes.
+func TestDiffProductsFields(t *testing.T) {
+	a, b := diffTestProduct(), diffTestProduct()
+	b.StockNumber = 250
+	b.Weight = 0.002
+
+	d := DiffProducts(a, b)
+	expected := []FieldChange{
+		{Field: "StockNumber", Old: "1000", New: "250"},
+		{Field: "Weight", Old: "0.001", New: "0.002"},
+	}
+	if len(d.Fields) != len(expected) {
+		t.Fatalf("expected %d field changes, got %+v", len(expected), d.Fields)
+	}
+	for i, want := range expected {
+		if d.Fields[i] != want {
+			t.Errorf("change %d: expected %+v, got %+v", i, want, d.Fields[i])
+		}
+	}
+	if len(d.Params) != 0 || len(d.PriceBreaks) != 0 {
+		t.Errorf("unexpected parameter or price changes: %+v", d)
+	}
+}
+
+// TestDiffProductsParams tests parameter additions, removals and modifications.
+func TestDiffProductsParams(t *testing.T) {
+	a, b := diffTestProduct(), diffTestProduct()
+	b.ParamVOList = []Parameter{
+		{ParamNameEn: "Capacitance", ParamValueEn: "100nF"},
+		{ParamNameEn: "Voltage Rated", ParamValueEn: "16V"},
+		{ParamNameEn: "Tolerance", ParamValueEn: "±5%"},
+	}
+
+	d := DiffProducts(a, b)
+	expected := []ParamChange{
+		{Name: "Voltage Rated", Kind: ChangeAdded, New: "16V"},
+		{Name: "Tolerance", Kind: ChangeModified, Old: "±10%", New: "±5%"},
+	}
+	if len(d.Params) != len(expected) {
+		t.Fatalf("expected %d parameter changes, got %+v", len(expected), d.Params)
+	}
+	for i, want := range expected {
+		if d.Params[i] != want {
+			t.Errorf("change %d: expected %+v, got %+v", i, want, d.Params[i])
+		}
+	}
+
+	d = DiffProducts(b, a)
+	if len(d.Params) != 2 || d.Params[1].Kind != ChangeRemoved || d.Params[1].Name != "Voltage Rated" {
+		t.Errorf("expected removed Voltage Rated, got %+v", d.Params)
+	}
+}
+
+// TestDiffProductsChinese tests comparing Chinese fields and parameter
+// values.
+func TestDiffProductsChinese(t *testing.T) {
+	a, b := diffTestProduct(), diffTestProduct()
+	a.ProductNameCn, b.ProductNameCn = "贴片电容", "多层陶瓷电容"
+	a.ParamVOList = append(a.ParamVOList, Parameter{ParamNameCn: "温度系数", ParamValueCn: "X7R"})
+	b.ParamVOList = append([]Parameter{}, a.ParamVOList...)
+	b.ParamVOList[0].ParamValueCn = "100纳法"
+	b.ParamVOList[len(b.ParamVOList)-1].ParamValueCn = "X5R"
+
+	d := DiffProducts(a, b)
+	if len(d.Fields) != 1 || d.Fields[0] != (FieldChange{Field: "ProductNameCn", Old: "贴片电容", New: "多层陶瓷电容"}) {
+		t.Errorf("unexpected field changes: %+v", d.Fields)
+	}
+	expected := []ParamChange{
+		{Name: "Capacitance", Kind: ChangeModified, Old: "100nF", New: "100nF", NewCn: "100纳法"},
+		{Name: "温度系数", Kind: ChangeModified, OldCn: "X7R", NewCn: "X5R"},
+	}
+	if len(d.Params) != len(expected) {
+		t.Fatalf("expected %d parameter changes, got %+v", len(expected), d.Params)
+	}
+	for i, want := range expected {
+		if d.Params[i] != want {
+			t.Errorf("change %d: expected %+v, got %+v", i, want, d.Params[i])
+		}
+	}
+}
+
+// TestDiffProductsPriceBreaks tests price break changes, sorted by ladder.
+func TestDiffProductsPriceBreaks(t *testing.T) {
+	a, b := diffTestProduct(), diffTestProduct()
+	b.ProductPriceList = []PriceBreak{
+		{Ladder: 1000, ProductPrice: 0.0008},
+		{Ladder: 100, ProductPrice: 0.0011},
+	}
+
+	d := DiffProducts(a, b)
+	expected := []PriceBreakChange{
+		{Ladder: 10, Kind: ChangeRemoved, OldPrice: 0.0016},
+		{Ladder: 100, Kind: ChangeModified, OldPrice: 0.0012, NewPrice: 0.0011},
+		{Ladder: 1000, Kind: ChangeAdded, NewPrice: 0.0008},
+	}
+	if len(d.PriceBreaks) != len(expected) {
+		t.Fatalf("expected %d price changes, got %+v", len(expected), d.PriceBreaks)
+	}
+	for i, want := range expected {
+		if d.PriceBreaks[i] != want {
+			t.Errorf("change %d: expected %+v, got %+v", i, want, d.PriceBreaks[i])
+		}
+	}
+}
+
+// TestDiffProductsNil tests diffing against a nil product.
+func TestDiffProductsNil(t *testing.T) {
+	d := DiffProducts(nil, diffTestProduct())
+	if d.ProductCode != "C1525" {
+		t.Errorf("expected ProductCode C1525, got %s", d.ProductCode)
+	}
+	if len(d.Params) != 2 || len(d.PriceBreaks) != 2 {
+		t.Errorf("expected everything added, got %+v", d)
+	}
+	for _, c := range d.PriceBreaks {
+		if c.Kind != ChangeAdded {
+			t.Errorf("expected added price break, got %+v", c)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package lcsc
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxDownloadSize is the size limit used when DownloadOptions.MaxSize is zero.
+const DefaultMaxDownloadSize = 50 << 20 // 50 MiB
+
+var (
+	ErrContentType = errors.New("lcsc: unexpected content type")
+	ErrTooLarge    = errors.New("lcsc: download exceeds size limit")
+)
+
+var (
+	// DatasheetTypes are the content types accepted for datasheets.
+	DatasheetTypes = []string{"application/pdf"}
+	// ImageTypes are the content types accepted for product images.
+	ImageTypes = []string{"image/"}
+)
+
+// DownloadOptions configures a download.
+type DownloadOptions struct {
+	// MaxSize is the maximum size in bytes (default: DefaultMaxDownloadSize).
+	MaxSize int64
+	// ContentTypes lists the accepted media types. Entries ending in "/"
+	// match a whole type, like "image/". Empty accepts any type. Missing
+	// and generic types are sniffed from the content.
+	ContentTypes []string
+}
+
+// Download describes downloaded content.
+type Download struct {
+	URL         string `json:"url"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"` // Hex-encoded content hash
+	Path        string `json:"-"`      // Local file, for file and store downloads
+}
+
+// Download fetches rawURL and writes its content to w. Requests use the
+// client's rate limiter, retries and User-Agent. Nothing is written to w
+// if the content type is rejected or the size is known to exceed the limit
+// up front; content exceeding the limit while streaming is truncated and
+// ErrTooLarge returned.
+func (c *Client) Download(ctx context.Context, rawURL string, w io.Writer, opts DownloadOptions) (*Download, error) {
+	resp, err := c.openDownload(ctx, rawURL, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	h := sha256.New()
+	d := &Download{URL: rawURL}
+	if err := copyDownload(io.MultiWriter(w, h), resp, opts, 0, d); err != nil {
+		return nil, err
+	}
+	d.SHA256 = hex.EncodeToString(h.Sum(nil))
+	return d, nil
+}
+
+// DownloadFile fetches rawURL into the file at path. Content is written to
+// path + ".part" first and renamed when complete. A partial file left by an
+// interrupted transfer is resumed with a range request on the next call,
+// provided the server identified the content with an ETag or Last-Modified
+// header; the range is conditional on that validator, so content that changed
+// in between is downloaded again from the start. Partial files are discarded
+// when the content type or size is rejected.
+func (c *Client) DownloadFile(ctx context.Context, rawURL, path string, opts DownloadOptions) (*Download, error) {
+	part := path + ".part"
+	f, err := os.OpenFile(part, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", part, err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	// Hash the partial content so the final hash covers the whole file.
+	h := sha256.New()
+	offset, err := io.Copy(h, f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", part, err)
+	}
+
+	maxSize := opts.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxDownloadSize
+	}
+	meta := readPartialMeta(part)
+	if offset > 0 && (offset > maxSize || meta.URL != rawURL || meta.Validator == "" ||
+		!acceptsContentType(opts.ContentTypes, meta.ContentType)) {
+		// Not a resumable partial copy of an acceptable download.
+		if err := resetFile(f); err != nil {
+			return nil, err
+		}
+		h.Reset()
+		offset = 0
+	}
+
+	resp, err := c.openDownload(ctx, rawURL, offset, meta.Validator)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	d := &Download{URL: rawURL, Path: path}
+	switch {
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// The partial file holds the whole content only if the server
+		// reports exactly its size; otherwise it is stale, so start over.
+		if size, ok := unsatisfiedRangeSize(resp.Header.Get("Content-Range")); !ok || size != offset {
+			_ = resp.Body.Close()
+			_ = f.Close()
+			removePartial(part)
+			return c.DownloadFile(ctx, rawURL, path, opts)
+		}
+		d.ContentType = meta.ContentType
+		d.Size = offset
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		d.ContentType = meta.ContentType
+		d.Size = offset
+		if err := copyDownload(io.MultiWriter(f, h), resp, opts, offset, d); err != nil {
+			_ = f.Close()
+			return nil, keepPartial(part, meta, err)
+		}
+	default:
+		if err := resetFile(f); err != nil {
+			return nil, err
+		}
+		h.Reset()
+		meta = partialMeta{URL: rawURL, Validator: rangeValidator(resp.Header)}
+		if err := copyDownload(io.MultiWriter(f, h), resp, opts, 0, d); err != nil {
+			_ = f.Close()
+			meta.ContentType = d.ContentType
+			return nil, keepPartial(part, meta, err)
+		}
+	}
+
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", part, err)
+	}
+	if err := os.Rename(part, path); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	_ = os.Remove(part + ".meta")
+	d.SHA256 = hex.EncodeToString(h.Sum(nil))
+	return d, nil
+}
+
+// partialMeta describes the content of a partial download file, stored
+// next to it in a ".meta" file.
+type partialMeta struct {
+	URL         string `json:"url"`
+	Validator   string `json:"validator"` // ETag or Last-Modified, sent as If-Range
+	ContentType string `json:"contentType"`
+}
+
+// readPartialMeta reads the metadata of the partial file part. A missing
+// or unreadable file yields empty metadata, which is never resumed.
+func readPartialMeta(part string) partialMeta {
+	var meta partialMeta
+	if data, err := os.ReadFile(part + ".meta"); err == nil {
+		_ = json.Unmarshal(data, &meta)
+	}
+	return meta
+}
+
+// keepPartial handles a failed transfer into the partial file part. After
+// transport errors the file is kept for resuming, along with meta; it is
+// removed if the content was rejected or cannot be resumed safely. err is
+// returned.
+func keepPartial(part string, meta partialMeta, err error) error {
+	if errors.Is(err, ErrContentType) || errors.Is(err, ErrTooLarge) || meta.Validator == "" {
+		removePartial(part)
+		return err
+	}
+	data, jsonErr := json.Marshal(meta)
+	if jsonErr == nil {
+		jsonErr = os.WriteFile(part+".meta", data, 0o644)
+	}
+	if jsonErr != nil {
+		removePartial(part)
+	}
+	return err
+}
+
+// removePartial removes the partial file part and its metadata.
+func removePartial(part string) {
+	_ = os.Remove(part)
+	_ = os.Remove(part + ".meta")
+}
+
+// rangeValidator returns the validator to send as If-Range when resuming
+// the content of a response: a strong ETag, or else Last-Modified.
+func rangeValidator(header http.Header) string {
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return header.Get("Last-Modified")
+}
+
+// DownloadDatasheet writes a product's datasheet PDF to w.
+func (c *Client) DownloadDatasheet(ctx context.Context, product *Product, w io.Writer) (*Download, error) {
+	if product == nil || strings.TrimSpace(product.PdfUrl) == "" {
+		return nil, fmt.Errorf("product has no datasheet")
+	}
+	return c.Download(ctx, product.PdfUrl, w, DownloadOptions{ContentTypes: DatasheetTypes})
+}
+
+// DownloadToDir downloads rawURL into dir, naming the file after the last
+// element of the URL path. Interrupted downloads resume on the next call.
+func (c *Client) DownloadToDir(ctx context.Context, rawURL, dir string, opts DownloadOptions) (*Download, error) {
+	name := downloadName(rawURL)
+	if name == "" {
+		return nil, fmt.Errorf("cannot derive a file name from %q", rawURL)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	return c.DownloadFile(ctx, rawURL, filepath.Join(dir, name), opts)
+}
+
+// openDownload issues a GET for rawURL, starting at offset, through the
+// client's shared request path. A resumed request is conditional on
+// validator, so the server sends the whole content if it changed. It
+// returns responses with status 200, 206, or 416 for a resumed request. Observers see the request as endpoint
+// "download", with the announced content length as its size.
+func (c *Client) openDownload(ctx context.Context, rawURL string, offset int64, validator string) (resp *http.Response, err error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if strings.TrimSpace(rawURL) == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+
+	start := time.Now()
+	done := RequestEvent{Type: EventRequestDone, Method: http.MethodGet, Endpoint: "download"}
+	c.observe(ctx, RequestEvent{Type: EventRequestStart, Method: http.MethodGet, Endpoint: done.Endpoint})
+	defer func() {
+		done.Duration = time.Since(start)
+		if resp != nil && resp.ContentLength > 0 {
+			done.Bytes = resp.ContentLength
+		}
+		done.Err = err
+		c.observe(ctx, done)
+	}()
+
+	req := &request{
+		method: http.MethodGet,
+		path:   rawURL,
+		header: http.Header{"Accept": {"*/*"}},
+		accept: func(status int) bool {
+			return status == http.StatusOK || status == http.StatusPartialContent ||
+				status == http.StatusRequestedRangeNotSatisfiable && offset > 0
+		},
+		allowHTML: true,
+		stream:    true,
+	}
+	if offset > 0 {
+		req.header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		req.header.Set("If-Range", validator)
+	}
+	return c.roundTrip(ctx, req, &done)
+}
+
+// copyDownload checks the response against opts and copies its body to w,
+// updating d with the content type and total size. offset is the number of
+// bytes already downloaded; the content type is only sniffed for fresh
+// downloads.
+func copyDownload(w io.Writer, resp *http.Response, opts DownloadOptions, offset int64, d *Download) error {
+	maxSize := opts.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxDownloadSize
+	}
+	if resp.ContentLength > 0 && offset+resp.ContentLength > maxSize {
+		return fmt.Errorf("%w: %d bytes", ErrTooLarge, offset+resp.ContentLength)
+	}
+
+	body := bufio.NewReader(resp.Body)
+	contentType := mediaType(resp.Header.Get("Content-Type"))
+	if offset == 0 && (contentType == "" || contentType == "application/octet-stream") {
+		head, _ := body.Peek(512)
+		contentType = mediaType(http.DetectContentType(head))
+	}
+	if !acceptsContentType(opts.ContentTypes, contentType) && !(offset > 0 && contentType == "") {
+		return fmt.Errorf("%w: %s", ErrContentType, contentType)
+	}
+	if contentType != "" {
+		d.ContentType = contentType
+	}
+
+	n, err := io.Copy(w, io.LimitReader(body, maxSize-offset+1))
+	d.Size = offset + n
+	if err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+	if d.Size > maxSize {
+		return fmt.Errorf("%w: more than %d bytes", ErrTooLarge, maxSize)
+	}
+	return nil
+}
+
+// acceptsContentType reports whether contentType matches one of accepted.
+func acceptsContentType(accepted []string, contentType string) bool {
+	if len(accepted) == 0 {
+		return true
+	}
+	for _, a := range accepted {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if a == contentType || (strings.HasSuffix(a, "/") && strings.HasPrefix(contentType, a)) {
+			return true
+		}
+	}
+	return false
+}
+
+// mediaType returns the lowercase media type of a Content-Type header.
+func mediaType(header string) string {
+	mt, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(strings.Split(header, ";")[0]))
+	}
+	return mt
+}
+
+// downloadName returns the last path element of rawURL, safe for use as a file name.
+func downloadName(rawURL string) string {
+	u := rawURL
+	if i := strings.IndexAny(u, "?#"); i >= 0 {
+		u = u[:i]
+	}
+	name := u[strings.LastIndexByte(u, '/')+1:]
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `\:`) {
+		return ""
+	}
+	return name
+}
+
+// unsatisfiedRangeSize returns the complete length from the Content-Range
+// header of a 416 response, of the form "bytes */N".
+func unsatisfiedRangeSize(header string) (int64, bool) {
+	size, ok := strings.CutPrefix(strings.TrimSpace(header), "bytes */")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(size, 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// resetFile truncates f and rewinds it.
+func resetFile(f *os.File) error {
+	if err := f.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate %s: %w", f.Name(), err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek %s: %w", f.Name(), err)
+	}
+	return nil
+}
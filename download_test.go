@@ -0,0 +1,497 @@
+package lcsc
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testPDF = []byte("%PDF-1.4\n" + strings.Repeat("datasheet content\n", 100))
+
+// fileServer serves fixed files with range support and counts requests per path.
+type fileServer struct {
+	mu       sync.Mutex
+	files    map[string][]byte
+	types    map[string]string
+	cuts     map[string]int // Bytes after which the next full response breaks off
+	requests map[string]int
+	ranges   []string
+	ifRanges []string
+}
+
+func newFileServer(t *testing.T) (*fileServer, *httptest.Server) {
+	t.Helper()
+	fs := &fileServer{files: map[string][]byte{}, types: map[string]string{}, cuts: map[string]int{}, requests: map[string]int{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fs.mu.Lock()
+		data, ok := fs.files[r.URL.Path]
+		contentType := fs.types[r.URL.Path]
+		fs.requests[r.URL.Path]++
+		rng := r.Header.Get("Range")
+		if rng != "" {
+			fs.ranges = append(fs.ranges, rng)
+			fs.ifRanges = append(fs.ifRanges, r.Header.Get("If-Range"))
+		}
+		cut := fs.cuts[r.URL.Path]
+		if rng == "" {
+			delete(fs.cuts, r.URL.Path)
+		}
+		fs.mu.Unlock()
+
+		if r.Header.Get("User-Agent") != userAgent {
+			t.Errorf("unexpected User-Agent: %q", r.Header.Get("User-Agent"))
+		}
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.Header().Set("ETag", testETag(data))
+		if cut > 0 && rng == "" {
+			// Announce the whole file but drop the connection partway.
+			w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+			_, _ = w.Write(data[:cut])
+			return
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+	}))
+	t.Cleanup(server.Close)
+	return fs, server
+}
+
+func (fs *fileServer) add(path, contentType string, data []byte) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.files[path] = data
+	fs.types[path] = contentType
+}
+
+// cut makes the next full response for path break off after n bytes.
+func (fs *fileServer) cut(path string, n int) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.cuts[path] = n
+}
+
+func (fs *fileServer) count(path string) int {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.requests[path]
+}
+
+func newDownloadClient() *Client {
+	return NewClient(WithRetryConfig(NoRetry()), WithRateLimit(1000))
+}
+
+func testETag(data []byte) string {
+	return `"` + sha256Hex(data)[:16] + `"`
+}
+
+// writePartial writes a resumable partial download of rawURL for path.
+func writePartial(t *testing.T, path, rawURL string, data []byte, contentType string) {
+	t.Helper()
+	if err := os.WriteFile(path+".part", data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	meta := fmt.Sprintf(`{"url":%q,"validator":%q,"contentType":%q}`, rawURL, testETag(testPDF), contentType)
+	if err := os.WriteFile(path+".part.meta", []byte(meta), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// TestDownload tests downloading to a writer with content type sniffing.
+func TestDownload(t *testing.T) {
+	fs, server := newFileServer(t)
+	fs.add("/ds.pdf", "application/octet-stream", testPDF)
+	client := newDownloadClient()
+
+	var buf bytes.Buffer
+	d, err := client.Download(context.Background(), server.URL+"/ds.pdf", &buf, DownloadOptions{ContentTypes: DatasheetTypes})
+	if err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), testPDF) {
+		t.Error("downloaded content differs")
+	}
+	if d.ContentType != "application/pdf" || d.Size != int64(len(testPDF)) || d.SHA256 != sha256Hex(testPDF) {
+		t.Errorf("unexpected download: %+v", d)
+	}
+}
+
+// TestDownloadContentType tests rejection of unexpected content types.
+func TestDownloadContentType(t *testing.T) {
+	fs, server := newFileServer(t)
+	fs.add("/ds.pdf", "text/html; charset=utf-8", []byte("<html>not found</html>"))
+	fs.add("/img.jpg", "image/jpeg", []byte{0xff, 0xd8, 0xff})
+	client := newDownloadClient()
+
+	var buf bytes.Buffer
+	_, err := client.Download(context.Background(), server.URL+"/ds.pdf", &buf, DownloadOptions{ContentTypes: DatasheetTypes})
+	if !errors.Is(err, ErrContentType) {
+		t.Errorf("expected ErrContentType, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Error("expected nothing written for rejected content")
+	}
+
+	if _, err := client.Download(context.Background(), server.URL+"/img.jpg", &buf, DownloadOptions{ContentTypes: ImageTypes}); err != nil {
+		t.Errorf("expected image/ to accept image/jpeg, got %v", err)
+	}
+	if _, err := client.Download(context.Background(), server.URL+"/missing.pdf", &buf, DownloadOptions{}); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+// TestDownloadMaxSize tests the download size limit.
+func TestDownloadMaxSize(t *testing.T) {
+	fs, server := newFileServer(t)
+	fs.add("/ds.pdf", "application/pdf", testPDF)
+	client := newDownloadClient()
+
+	var buf bytes.Buffer
+	_, err := client.Download(context.Background(), server.URL+"/ds.pdf", &buf, DownloadOptions{MaxSize: 100})
+	if !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+
+	// Without a Content-Length the limit applies while streaming.
+	streaming := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.(http.Flusher).Flush()
+		_, _ = w.Write(testPDF)
+	}))
+	defer streaming.Close()
+
+	buf.Reset()
+	_, err = client.Download(context.Background(), streaming.URL, &buf, DownloadOptions{MaxSize: 100})
+	if !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge while streaming, got %v", err)
+	}
+	if buf.Len() > 101 {
+		t.Errorf("expected at most 101 bytes written, got %d", buf.Len())
+	}
+}
+
+// TestDownloadFileResume tests resuming an interrupted download with a
+// conditional range request.
+func TestDownloadFileResume(t *testing.T) {
+	fs, server := newFileServer(t)
+	fs.add("/ds.pdf", "application/pdf", testPDF)
+	fs.cut("/ds.pdf", 500)
+	client := newDownloadClient()
+	url := server.URL + "/ds.pdf"
+
+	path := filepath.Join(t.TempDir(), "ds.pdf")
+	if _, err := client.DownloadFile(context.Background(), url, path, DownloadOptions{}); err == nil {
+		t.Fatal("expected error for interrupted download")
+	}
+	if data, err := os.ReadFile(path + ".part"); err != nil || !bytes.Equal(data, testPDF[:500]) {
+		t.Fatalf("expected 500 bytes kept in partial file, got %d (%v)", len(data), err)
+	}
+
+	d, err := client.DownloadFile(context.Background(), url, path, DownloadOptions{})
+	if err != nil {
+		t.Fatalf("DownloadFile failed: %v", err)
+	}
+	if len(fs.ranges) != 1 || fs.ranges[0] != "bytes=500-" || fs.ifRanges[0] != testETag(testPDF) {
+		t.Errorf("expected a range request from byte 500 with If-Range, got %v %v", fs.ranges, fs.ifRanges)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, testPDF) {
+		t.Error("resumed file differs")
+	}
+	if d.Size != int64(len(testPDF)) || d.SHA256 != sha256Hex(testPDF) || d.Path != path || d.ContentType != "application/pdf" {
+		t.Errorf("unexpected download: %+v", d)
+	}
+	for _, name := range []string{path + ".part", path + ".part.meta"} {
+		if _, err := os.Stat(name); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed", name)
+		}
+	}
+
+	// A complete partial file is answered with 416 and kept as is.
+	writePartial(t, path, url, testPDF, "application/pdf")
+	d, err = client.DownloadFile(context.Background(), url, path, DownloadOptions{})
+	if err != nil {
+		t.Fatalf("DownloadFile of complete file failed: %v", err)
+	}
+	if d.Size != int64(len(testPDF)) || d.SHA256 != sha256Hex(testPDF) || d.ContentType != "application/pdf" {
+		t.Errorf("unexpected download: %+v", d)
+	}
+	if fs.count("/ds.pdf") != 3 {
+		t.Errorf("expected 3 requests, got %d", fs.count("/ds.pdf"))
+	}
+
+	// Nor is it accepted if its content type is not.
+	writePartial(t, path, url, testPDF, "application/pdf")
+	if _, err := client.DownloadFile(context.Background(), url, path, DownloadOptions{ContentTypes: ImageTypes}); !errors.Is(err, ErrContentType) {
+		t.Errorf("expected ErrContentType, got %v", err)
+	}
+	if len(fs.ranges) != 2 {
+		t.Errorf("expected no range request for a rejected type, got %v", fs.ranges)
+	}
+}
+
+// TestDownloadFileChanged tests restarting a download whose content
+// changed since it was interrupted.
+func TestDownloadFileChanged(t *testing.T) {
+	fs, server := newFileServer(t)
+	fs.add("/ds.pdf", "application/pdf", testPDF)
+	fs.cut("/ds.pdf", 500)
+	client := newDownloadClient()
+	url := server.URL + "/ds.pdf"
+	path := filepath.Join(t.TempDir(), "ds.pdf")
+
+	if _, err := client.DownloadFile(context.Background(), url, path, DownloadOptions{}); err == nil {
+		t.Fatal("expected error for interrupted download")
+	}
+	changed := append([]byte("%PDF-1.7\n"), testPDF[9:]...)
+	fs.add("/ds.pdf", "application/pdf", changed)
+
+	d, err := client.DownloadFile(context.Background(), url, path, DownloadOptions{})
+	if err != nil {
+		t.Fatalf("DownloadFile failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); !bytes.Equal(data, changed) {
+		t.Error("expected the changed content")
+	}
+	if d.SHA256 != sha256Hex(changed) {
+		t.Errorf("unexpected hash: %s", d.SHA256)
+	}
+	if len(fs.ifRanges) != 1 || fs.ifRanges[0] != testETag(testPDF) {
+		t.Errorf("expected one request conditional on the old ETag, got %v", fs.ifRanges)
+	}
+
+	// Partial files without a validator are not resumed.
+	fs.ranges = nil
+	if err := os.WriteFile(path+".part", changed[:500], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.DownloadFile(context.Background(), url, path, DownloadOptions{}); err != nil {
+		t.Fatalf("DownloadFile failed: %v", err)
+	}
+	if len(fs.ranges) != 0 {
+		t.Errorf("expected no range request, got %v", fs.ranges)
+	}
+}
+
+// TestDownloadFileStalePart tests restarting downloads whose partial file
+// cannot be a prefix of the content.
+func TestDownloadFileStalePart(t *testing.T) {
+	fs, server := newFileServer(t)
+	fs.add("/ds.pdf", "application/pdf", testPDF)
+	client := newDownloadClient()
+	path := filepath.Join(t.TempDir(), "ds.pdf")
+
+	// Longer than the content: the 416 reports a different size.
+	stale := append(append([]byte{}, testPDF...), "trailing"...)
+	writePartial(t, path, server.URL+"/ds.pdf", stale, "application/pdf")
+	d, err := client.DownloadFile(context.Background(), server.URL+"/ds.pdf", path, DownloadOptions{})
+	if err != nil {
+		t.Fatalf("DownloadFile failed: %v", err)
+	}
+	if d.Size != int64(len(testPDF)) || d.SHA256 != sha256Hex(testPDF) {
+		t.Errorf("unexpected download: %+v", d)
+	}
+	if data, _ := os.ReadFile(path); !bytes.Equal(data, testPDF) {
+		t.Error("restarted file differs")
+	}
+	if fs.count("/ds.pdf") != 2 {
+		t.Errorf("expected 2 requests, got %d", fs.count("/ds.pdf"))
+	}
+
+	// Larger than the size limit: not resumed at all.
+	fs.ranges = nil
+	writePartial(t, path, server.URL+"/ds.pdf", stale, "application/pdf")
+	opts := DownloadOptions{MaxSize: int64(len(testPDF))}
+	if _, err := client.DownloadFile(context.Background(), server.URL+"/ds.pdf", path, opts); err != nil {
+		t.Fatalf("DownloadFile with size limit failed: %v", err)
+	}
+	if len(fs.ranges) != 0 {
+		t.Errorf("expected no range request, got %v", fs.ranges)
+	}
+}
+
+// TestDownloadFileResumeFailure tests that a resume rejected for its size
+// discards the partial file.
+func TestDownloadFileResumeFailure(t *testing.T) {
+	fs, server := newFileServer(t)
+	fs.add("/ds.pdf", "application/pdf", testPDF)
+	client := newDownloadClient()
+	path := filepath.Join(t.TempDir(), "ds.pdf")
+
+	writePartial(t, path, server.URL+"/ds.pdf", testPDF[:500], "application/pdf")
+	opts := DownloadOptions{MaxSize: 600}
+	if _, err := client.DownloadFile(context.Background(), server.URL+"/ds.pdf", path, opts); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+	if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+		t.Error("expected partial file to be removed")
+	}
+}
+
+// TestDownloadToDir tests naming downloaded files after the URL.
+func TestDownloadToDir(t *testing.T) {
+	fs, server := newFileServer(t)
+	fs.add("/datasheet/C1.pdf", "application/pdf", testPDF)
+	client := newDownloadClient()
+
+	dir := filepath.Join(t.TempDir(), "docs")
+	d, err := client.DownloadToDir(context.Background(), server.URL+"/datasheet/C1.pdf?v=2", dir, DownloadOptions{})
+	if err != nil {
+		t.Fatalf("DownloadToDir failed: %v", err)
+	}
+	if d.Path != filepath.Join(dir, "C1.pdf") {
+		t.Errorf("unexpected path: %s", d.Path)
+	}
+
+	if _, err := client.DownloadToDir(context.Background(), server.URL+"/", dir, DownloadOptions{}); err == nil {
+		t.Error("expected error for URL without file name")
+	}
+}
+
+// TestMirrorProduct tests mirroring product files into a blob store.
+func TestMirrorProduct(t *testing.T) {
+	fs, server := newFileServer(t)
+	image := []byte("\x89PNG\r\n\x1a\nimage")
+	fs.add("/ds.pdf", "application/pdf", testPDF)
+	fs.add("/ds-copy.pdf", "application/pdf", testPDF)
+	fs.add("/front.png", "image/png", image)
+	client := newDownloadClient()
+
+	dir := t.TempDir()
+	store, err := NewBlobStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	product := &Product{
+		ProductCode:     "C1",
+		PdfUrl:          server.URL + "/ds.pdf",
+		ProductImageUrl: server.URL + "/front.png",
+		ProductImages:   []string{server.URL + "/front.png"},
+	}
+	downloads, err := client.MirrorProduct(context.Background(), store, product)
+	if err != nil {
+		t.Fatalf("MirrorProduct failed: %v", err)
+	}
+	if len(downloads) != 2 {
+		t.Fatalf("expected 2 downloads, got %d", len(downloads))
+	}
+	if downloads[0].Path != store.Path(sha256Hex(testPDF)) || downloads[1].ContentType != "image/png" {
+		t.Errorf("unexpected downloads: %+v %+v", downloads[0], downloads[1])
+	}
+
+	// Mirroring again, even through a new store, does not refetch.
+	store, err = NewBlobStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.MirrorProduct(context.Background(), store, product); err != nil {
+		t.Fatalf("second MirrorProduct failed: %v", err)
+	}
+	if fs.count("/ds.pdf") != 1 || fs.count("/front.png") != 1 {
+		t.Errorf("expected one request per URL, got %d and %d", fs.count("/ds.pdf"), fs.count("/front.png"))
+	}
+
+	// Identical content from another URL is stored once.
+	d, err := client.Mirror(context.Background(), store, server.URL+"/ds-copy.pdf", DownloadOptions{})
+	if err != nil {
+		t.Fatalf("Mirror failed: %v", err)
+	}
+	if d.Path != downloads[0].Path {
+		t.Errorf("expected deduplicated path %s, got %s", downloads[0].Path, d.Path)
+	}
+	blobs, _ := filepath.Glob(filepath.Join(dir, "*", "*"))
+	if len(blobs) != 2 {
+		t.Errorf("expected 2 stored blobs, got %v", blobs)
+	}
+	if _, ok := store.Lookup(server.URL + "/ds-copy.pdf"); !ok {
+		t.Error("expected copy URL in index")
+	}
+}
+
+// TestMirrorConcurrent tests that concurrent mirrors of one URL download
+// it once.
+func TestMirrorConcurrent(t *testing.T) {
+	fs, server := newFileServer(t)
+	fs.add("/ds.pdf", "application/pdf", testPDF)
+	client := newDownloadClient()
+	store, err := NewBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d, err := client.Mirror(context.Background(), store, server.URL+"/ds.pdf", DownloadOptions{})
+			if err == nil && d.SHA256 != sha256Hex(testPDF) {
+				err = fmt.Errorf("unexpected hash %s", d.SHA256)
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Mirror failed: %v", err)
+		}
+	}
+	if fs.count("/ds.pdf") != 1 {
+		t.Errorf("expected 1 request, got %d", fs.count("/ds.pdf"))
+	}
+	if len(store.mirrors) != 0 {
+		t.Errorf("expected no mirror locks left, got %d", len(store.mirrors))
+	}
+}
+
+// TestAcceptsContentType tests content type matching.
+func TestAcceptsContentType(t *testing.T) {
+	tests := []struct {
+		accepted    []string
+		contentType string
+		expected    bool
+	}{
+		{nil, "text/html", true},
+		{DatasheetTypes, "application/pdf", true},
+		{DatasheetTypes, "text/html", false},
+		{ImageTypes, "image/webp", true},
+		{ImageTypes, "imagex/webp", false},
+		{[]string{"Application/PDF"}, "application/pdf", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v_%s", tt.accepted, tt.contentType), func(t *testing.T) {
+			if got := acceptsContentType(tt.accepted, tt.contentType); got != tt.expected {
+				t.Errorf("acceptsContentType(%v, %q) = %v, expected %v", tt.accepted, tt.contentType, got, tt.expected)
+			}
+		})
+	}
+}
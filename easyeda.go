@@ -0,0 +1,588 @@
+package lcsc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// easyedaVersion is the editor version sent with component requests.
+const easyedaVersion = "6.4.19.5"
+
+// EasyEDAUnitMM is the size of one EasyEDA coordinate unit (10 mil) in millimetres.
+const EasyEDAUnitMM = 0.254
+
+// ErrNoCADData is returned when a part has no EasyEDA symbol or footprint.
+var ErrNoCADData = errors.New("lcsc: no EasyEDA CAD data")
+
+// PinType is the electrical type of a symbol pin.
+type PinType int
+
+const (
+	PinUnspecified   PinType = 0
+	PinInput         PinType = 1
+	PinOutput        PinType = 2
+	PinBidirectional PinType = 3
+	PinPower         PinType = 4
+)
+
+// PadShape is the copper shape of a footprint pad.
+type PadShape string
+
+const (
+	PadRect    PadShape = "RECT"
+	PadEllipse PadShape = "ELLIPSE"
+	PadOval    PadShape = "OVAL"
+	PadPolygon PadShape = "POLYGON"
+)
+
+// EasyEDA footprint layer IDs.
+const (
+	LayerTopCopper    = 1
+	LayerBottomCopper = 2
+	LayerTopSilk      = 3
+	LayerBottomSilk   = 4
+	LayerTopPaste     = 5
+	LayerBottomPaste  = 6
+	LayerTopMask      = 7
+	LayerBottomMask   = 8
+	LayerBoardOutline = 10
+	LayerMulti        = 11
+	LayerDocument     = 12
+	LayerTopAssembly  = 13
+	LayerComponent    = 99
+)
+
+// Point is a coordinate in EasyEDA units.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// CADData is the EasyEDA CAD data behind an LCSC part. Coordinates are in
+// EasyEDA units (see EasyEDAUnitMM) with Y pointing down, as in the source.
+type CADData struct {
+	ProductCode string
+	Symbol      *Symbol
+	Footprint   *Footprint
+	Model3D     *Model3D // Nil when the footprint has no 3D model
+}
+
+// Symbol is a schematic symbol.
+type Symbol struct {
+	UUID       string
+	Title      string
+	Prefix     string // Reference prefix like "U?"
+	Origin     Point
+	Pins       []SymbolPin
+	Rectangles []SymbolRect
+	Polylines  []Polyline
+	Circles    []Circle
+	Arcs       []Arc
+}
+
+// SymbolPin is a pin of a schematic symbol.
+type SymbolPin struct {
+	Number   string
+	Name     string
+	Type     PinType
+	Position Point   // Connection point
+	Rotation float64 // Direction from Position towards the body in degrees, counter-clockwise from +X
+	Length   float64
+}
+
+// SymbolRect is a rectangle in a symbol.
+type SymbolRect struct {
+	X, Y          float64
+	Width, Height float64
+	StrokeWidth   float64
+	Filled        bool
+}
+
+// Polyline is a sequence of connected points. Closed polylines are polygons.
+type Polyline struct {
+	Layer       int // Footprint layer; zero in symbols
+	Points      []Point
+	StrokeWidth float64
+	Closed      bool
+	Filled      bool
+}
+
+// Circle is a circle outline.
+type Circle struct {
+	Layer       int // Footprint layer; zero in symbols
+	Center      Point
+	Radius      float64
+	StrokeWidth float64
+	Filled      bool
+}
+
+// Arc is an arc given as an SVG path ("M x y A rx ry rot large sweep x y").
+type Arc struct {
+	Layer       int // Footprint layer; zero in symbols
+	Path        string
+	StrokeWidth float64
+}
+
+// Footprint is a PCB footprint.
+type Footprint struct {
+	UUID       string
+	Title      string // Package name like "LQFP-48_L7.0-W7.0-P0.50-LS9.0-BL"
+	Origin     Point
+	SMD        bool
+	Pads       []Pad
+	Holes      []Hole
+	Tracks     []Polyline
+	Circles    []Circle
+	Arcs       []Arc
+	Rectangles []FootprintRect
+}
+
+// Pad is a footprint pad.
+type Pad struct {
+	Number     string
+	Shape      PadShape
+	Position   Point
+	Width      float64
+	Height     float64
+	Rotation   float64 // Degrees
+	Layer      int     // LayerTopCopper, LayerBottomCopper or LayerMulti for through-hole
+	HoleRadius float64 // Zero for SMD pads
+	HoleLength float64 // Slot length; zero for round holes
+	Points     []Point // Outline of PadPolygon pads
+	Plated     bool
+}
+
+// Hole is a non-plated hole.
+type Hole struct {
+	Position Point
+	Radius   float64
+}
+
+// FootprintRect is a rectangle on a footprint layer.
+type FootprintRect struct {
+	Layer         int
+	X, Y          float64
+	Width, Height float64
+	StrokeWidth   float64
+}
+
+// Model3D references the 3D model of a footprint.
+type Model3D struct {
+	UUID     string
+	Title    string
+	Origin   Point      // Placement origin in footprint coordinates
+	Z        float64    // Offset above the board
+	Rotation [3]float64 // Rotation around X, Y and Z in degrees
+}
+
+// OBJURL returns the download URL of the model in Wavefront OBJ format.
+func (m *Model3D) OBJURL() string {
+	return "https://modules.easyeda.com/3dmodel/" + m.UUID
+}
+
+// STEPURL returns the download URL of the model in STEP format.
+func (m *Model3D) STEPURL() string {
+	return "https://modules.easyeda.com/qAxj6KHrDKw4blvCG8QJPs7Y/" + m.UUID
+}
+
+// easyedaResponse is the response envelope of the EasyEDA API.
+type easyedaResponse struct {
+	Success bool            `json:"success"`
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// easyedaComponent is the component result of the EasyEDA API.
+type easyedaComponent struct {
+	UUID          string          `json:"uuid"`
+	Title         string          `json:"title"`
+	DataStr       json.RawMessage `json:"dataStr"`
+	PackageDetail struct {
+		UUID    string          `json:"uuid"`
+		Title   string          `json:"title"`
+		DataStr json.RawMessage `json:"dataStr"`
+	} `json:"packageDetail"`
+	SMT bool `json:"SMT"`
+}
+
+// easyedaDocument is the dataStr of a symbol or footprint.
+type easyedaDocument struct {
+	Head struct {
+		X     FlexFloat64       `json:"x"`
+		Y     FlexFloat64       `json:"y"`
+		CPara map[string]string `json:"c_para"`
+	} `json:"head"`
+	Shape []string `json:"shape"`
+}
+
+// GetCADData retrieves the EasyEDA symbol, footprint and 3D model
+// reference of a part. Requests go through the client's rate limiter,
+// retries and cache.
+func (c *Client) GetCADData(ctx context.Context, productCode string) (*CADData, error) {
+	productCode = strings.ToUpper(strings.TrimSpace(productCode))
+	if productCode == "" {
+		return nil, fmt.Errorf("productCode is required")
+	}
+
+	cacheKey := c.getCacheKeyCAD(productCode)
+	if c.cache != nil {
+		if cached, ok := c.cacheGet(ctx, "GET", "/api/products/{code}/components", cacheKey); ok {
+			if data, err := parseCADData(productCode, cached); err == nil {
+				return data, nil
+			}
+		}
+	}
+
+	params := url.Values{}
+	params.Set("version", easyedaVersion)
+	path := fmt.Sprintf("%s/api/products/%s/components", c.easyedaURL, url.PathEscape(productCode))
+
+	body, err := c.doRequest(ctx, "GET", path, params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := parseCADData(productCode, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.cache != nil {
+		c.cache.Set(cacheKey, body, 24*time.Hour)
+	}
+
+	return data, nil
+}
+
+// getCacheKeyCAD generates a cache key for CAD data requests.
+func (c *Client) getCacheKeyCAD(productCode string) string {
+	return fmt.Sprintf("cad:%s", productCode)
+}
+
+// parseCADData parses an EasyEDA component response.
+func parseCADData(productCode string, body []byte) (*CADData, error) {
+	var resp easyedaResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if !resp.Success {
+		return nil, errorFromCode(resp.Code, resp.Message)
+	}
+
+	var comp easyedaComponent
+	if len(resp.Result) == 0 || string(resp.Result) == "null" {
+		return nil, ErrNoCADData
+	}
+	if err := json.Unmarshal(resp.Result, &comp); err != nil {
+		return nil, fmt.Errorf("failed to parse result: %w", err)
+	}
+
+	data := &CADData{ProductCode: productCode}
+	if doc, ok, err := decodeEasyEDADocument(comp.DataStr); err != nil {
+		return nil, fmt.Errorf("failed to parse symbol: %w", err)
+	} else if ok {
+		data.Symbol = parseSymbol(comp.UUID, comp.Title, doc)
+	}
+	if doc, ok, err := decodeEasyEDADocument(comp.PackageDetail.DataStr); err != nil {
+		return nil, fmt.Errorf("failed to parse footprint: %w", err)
+	} else if ok {
+		data.Footprint, data.Model3D = parseFootprint(comp.PackageDetail.UUID, comp.PackageDetail.Title, doc)
+		data.Footprint.SMD = comp.SMT
+	}
+
+	if data.Symbol == nil && data.Footprint == nil {
+		return nil, ErrNoCADData
+	}
+	return data, nil
+}
+
+// decodeEasyEDADocument decodes a dataStr value, which is either an object
+// or a JSON string containing one.
+func decodeEasyEDADocument(raw json.RawMessage) (*easyedaDocument, bool, error) {
+	if len(raw) == 0 || string(raw) == "null" || string(raw) == `""` {
+		return nil, false, nil
+	}
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, false, err
+		}
+		raw = json.RawMessage(s)
+	}
+
+	var doc easyedaDocument
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, false, err
+	}
+	return &doc, true, nil
+}
+
+// parseSymbol builds a Symbol from its EasyEDA document. Unknown shapes
+// such as text are ignored.
+func parseSymbol(uuid, title string, doc *easyedaDocument) *Symbol {
+	s := &Symbol{
+		UUID:   uuid,
+		Title:  title,
+		Prefix: doc.Head.CPara["pre"],
+		Origin: Point{X: float64(doc.Head.X), Y: float64(doc.Head.Y)},
+	}
+
+	for _, shape := range doc.Shape {
+		f := strings.Split(shape, "~")
+		switch f[0] {
+		case "P":
+			if pin, ok := parseSymbolPin(shape); ok {
+				s.Pins = append(s.Pins, pin)
+			}
+		case "R":
+			// R~x~y~rx~ry~width~height~stroke~strokeWidth~style~fill~id
+			s.Rectangles = append(s.Rectangles, SymbolRect{
+				X:           field(f, 1),
+				Y:           field(f, 2),
+				Width:       field(f, 5),
+				Height:      field(f, 6),
+				StrokeWidth: field(f, 8),
+				Filled:      isFilled(fieldString(f, 10)),
+			})
+		case "PL", "PG":
+			// PL~points~stroke~strokeWidth~style~fill~id
+			s.Polylines = append(s.Polylines, Polyline{
+				Points:      parsePoints(fieldString(f, 1)),
+				StrokeWidth: field(f, 3),
+				Closed:      f[0] == "PG",
+				Filled:      isFilled(fieldString(f, 5)),
+			})
+		case "C":
+			// C~cx~cy~r~stroke~strokeWidth~style~fill~id
+			s.Circles = append(s.Circles, Circle{
+				Center:      Point{X: field(f, 1), Y: field(f, 2)},
+				Radius:      field(f, 3),
+				StrokeWidth: field(f, 5),
+				Filled:      isFilled(fieldString(f, 7)),
+			})
+		case "E":
+			// E~cx~cy~rx~ry~stroke~strokeWidth~style~fill~id; only circles are kept
+			if field(f, 3) == field(f, 4) {
+				s.Circles = append(s.Circles, Circle{
+					Center:      Point{X: field(f, 1), Y: field(f, 2)},
+					Radius:      field(f, 3),
+					StrokeWidth: field(f, 6),
+					Filled:      isFilled(fieldString(f, 8)),
+				})
+			}
+		case "A":
+			// A~path~helperDots~stroke~strokeWidth~style~fill~id
+			s.Arcs = append(s.Arcs, Arc{Path: fieldString(f, 1), StrokeWidth: field(f, 4)})
+		}
+	}
+	return s
+}
+
+// parseSymbolPin parses a pin shape. Pins consist of "^^"-separated
+// segments: settings, dot, path, name, number and decorations.
+//
+//	P~show~type~spiceNumber~x~y~rotation~id~locked^^dotX~dotY^^M x y h -10~color^^show~x~y~rot~name~...^^show~x~y~rot~number~...
+func parseSymbolPin(shape string) (SymbolPin, bool) {
+	segments := strings.Split(shape, "^^")
+	if len(segments) < 5 {
+		return SymbolPin{}, false
+	}
+
+	settings := strings.Split(segments[0], "~")
+	name := strings.Split(segments[3], "~")
+	number := strings.Split(segments[4], "~")
+
+	pin := SymbolPin{
+		Number:   fieldString(number, 4),
+		Name:     fieldString(name, 4),
+		Type:     PinType(field(settings, 2)),
+		Position: Point{X: field(settings, 4), Y: field(settings, 5)},
+		// EasyEDA rotations are clockwise on screen.
+		Rotation: math.Mod(360-field(settings, 6), 360),
+	}
+	if dx, dy, ok := pinVector(strings.Split(segments[2], "~")[0]); ok {
+		pin.Length = math.Hypot(dx, dy)
+		pin.Rotation = math.Mod(math.Atan2(-dy, dx)*180/math.Pi+360, 360)
+	}
+	if pin.Number == "" {
+		pin.Number = fieldString(settings, 3)
+	}
+	return pin, true
+}
+
+// pinVector returns the offset from the start to the end of a pin path
+// like "M 410 300 h -10", or false if the path has no line segment.
+func pinVector(path string) (dx, dy float64, ok bool) {
+	f := strings.Fields(path)
+	if len(f) < 5 || f[0] != "M" {
+		return 0, 0, false
+	}
+	x, _ := strconv.ParseFloat(f[1], 64)
+	y, _ := strconv.ParseFloat(f[2], 64)
+	n, err := strconv.ParseFloat(f[4], 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	switch f[3] {
+	case "h":
+		return n, 0, true
+	case "v":
+		return 0, n, true
+	case "H":
+		return n - x, 0, true
+	case "V":
+		return 0, n - y, true
+	case "L":
+		if len(f) < 6 {
+			return 0, 0, false
+		}
+		ly, _ := strconv.ParseFloat(f[5], 64)
+		return n - x, ly - y, true
+	}
+	return 0, 0, false
+}
+
+// parseFootprint builds a Footprint and its 3D model reference from an
+// EasyEDA document. Text, vias and solid regions are ignored.
+func parseFootprint(uuid, title string, doc *easyedaDocument) (*Footprint, *Model3D) {
+	fp := &Footprint{
+		UUID:   uuid,
+		Title:  title,
+		Origin: Point{X: float64(doc.Head.X), Y: float64(doc.Head.Y)},
+	}
+	if pkg := doc.Head.CPara["package"]; pkg != "" && fp.Title == "" {
+		fp.Title = pkg
+	}
+
+	var model *Model3D
+	for _, shape := range doc.Shape {
+		f := strings.Split(shape, "~")
+		switch f[0] {
+		case "PAD":
+			// PAD~shape~x~y~width~height~layer~net~number~holeRadius~points~rotation~id~holeLength~holePoints~plated
+			pad := Pad{
+				Shape:      PadShape(fieldString(f, 1)),
+				Position:   Point{X: field(f, 2), Y: field(f, 3)},
+				Width:      field(f, 4),
+				Height:     field(f, 5),
+				Layer:      int(field(f, 6)),
+				Number:     fieldString(f, 8),
+				HoleRadius: field(f, 9),
+				Rotation:   field(f, 11),
+				HoleLength: field(f, 13),
+				Plated:     fieldString(f, 15) != "N",
+			}
+			if pad.Shape == PadPolygon {
+				pad.Points = parsePoints(fieldString(f, 10))
+			}
+			fp.Pads = append(fp.Pads, pad)
+		case "HOLE":
+			// HOLE~x~y~radius~id
+			fp.Holes = append(fp.Holes, Hole{
+				Position: Point{X: field(f, 1), Y: field(f, 2)},
+				Radius:   field(f, 3),
+			})
+		case "TRACK":
+			// TRACK~strokeWidth~layer~net~points~id
+			fp.Tracks = append(fp.Tracks, Polyline{
+				Layer:       int(field(f, 2)),
+				Points:      parsePoints(fieldString(f, 4)),
+				StrokeWidth: field(f, 1),
+			})
+		case "CIRCLE":
+			// CIRCLE~cx~cy~r~strokeWidth~layer~id
+			fp.Circles = append(fp.Circles, Circle{
+				Layer:       int(field(f, 5)),
+				Center:      Point{X: field(f, 1), Y: field(f, 2)},
+				Radius:      field(f, 3),
+				StrokeWidth: field(f, 4),
+			})
+		case "ARC":
+			// ARC~strokeWidth~layer~net~path~helperDots~id
+			fp.Arcs = append(fp.Arcs, Arc{
+				Layer:       int(field(f, 2)),
+				Path:        fieldString(f, 4),
+				StrokeWidth: field(f, 1),
+			})
+		case "RECT":
+			// RECT~x~y~width~height~layer~id~locked~strokeWidth
+			fp.Rectangles = append(fp.Rectangles, FootprintRect{
+				Layer:       int(field(f, 5)),
+				X:           field(f, 1),
+				Y:           field(f, 2),
+				Width:       field(f, 3),
+				Height:      field(f, 4),
+				StrokeWidth: field(f, 8),
+			})
+		case "SVGNODE":
+			if m, ok := parseModel3D(strings.TrimPrefix(shape, "SVGNODE~")); ok {
+				model = m
+			}
+		}
+	}
+	return fp, model
+}
+
+// parseModel3D parses the JSON payload of an SVGNODE shape referencing a 3D model.
+func parseModel3D(payload string) (*Model3D, bool) {
+	var node struct {
+		Attrs struct {
+			UUID     string `json:"uuid"`
+			Title    string `json:"title"`
+			Origin   string `json:"c_origin"`
+			Z        string `json:"z"`
+			Rotation string `json:"c_rotation"`
+		} `json:"attrs"`
+	}
+	if err := json.Unmarshal([]byte(payload), &node); err != nil || node.Attrs.UUID == "" {
+		return nil, false
+	}
+
+	m := &Model3D{UUID: node.Attrs.UUID, Title: node.Attrs.Title}
+	origin := strings.Split(node.Attrs.Origin, ",")
+	m.Origin = Point{X: field(origin, 0), Y: field(origin, 1)}
+	m.Z, _ = strconv.ParseFloat(strings.TrimSpace(node.Attrs.Z), 64)
+	rotation := strings.Split(node.Attrs.Rotation, ",")
+	for i := range m.Rotation {
+		m.Rotation[i] = field(rotation, i)
+	}
+	return m, true
+}
+
+// parsePoints parses a space-separated coordinate list like "x1 y1 x2 y2".
+func parsePoints(s string) []Point {
+	f := strings.Fields(strings.ReplaceAll(s, ",", " "))
+	points := make([]Point, 0, len(f)/2)
+	for i := 0; i+1 < len(f); i += 2 {
+		points = append(points, Point{X: field(f, i), Y: field(f, i+1)})
+	}
+	return points
+}
+
+// field returns f[i] parsed as a number, or 0 if missing or invalid.
+func field(f []string, i int) float64 {
+	n, _ := strconv.ParseFloat(fieldString(f, i), 64)
+	return n
+}
+
+// fieldString returns f[i] trimmed, or "" if missing.
+func fieldString(f []string, i int) string {
+	if i >= len(f) {
+		return ""
+	}
+	return strings.TrimSpace(f[i])
+}
+
+// isFilled reports whether an EasyEDA fill color is set.
+func isFilled(fill string) bool {
+	return fill != "" && !strings.EqualFold(fill, "none")
+}
@@ -0,0 +1,212 @@
+package lcsc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newEasyEDAClient returns a client whose EasyEDA endpoint serves body for
+// C20526 and an empty result for other parts, counting requests.
+func newEasyEDAClient(t *testing.T, body []byte, requests *int32, opts ...ClientOption) *Client {
+	t.Helper()
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		if r.URL.Query().Get("version") == "" {
+			t.Errorf("expected version parameter, got %s", r.URL.RawQuery)
+		}
+		if r.URL.Path != "/api/products/C20526/components" {
+			_, _ = w.Write([]byte(`{"success":true,"code":0,"result":null}`))
+			return
+		}
+		_, _ = w.Write(body)
+	}
+
+	client := newTestClient(t, handler, opts...)
+	client.easyedaURL = client.baseURL
+	return client
+}
+
+// readEasyEDAFixture reads the recorded EasyEDA component response.
+func readEasyEDAFixture(t *testing.T) []byte {
+	t.Helper()
+	body, err := os.ReadFile("testdata/easyeda_component.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	return body
+}
+
+// TestGetCADDataSymbol tests symbol parsing.
+func TestGetCADDataSymbol(t *testing.T) {
+	var requests int32
+	client := newEasyEDAClient(t, readEasyEDAFixture(t), &requests)
+
+	data, err := client.GetCADData(context.Background(), "c20526")
+	if err != nil {
+		t.Fatalf("GetCADData failed: %v", err)
+	}
+	if data.ProductCode != "C20526" {
+		t.Errorf("expected ProductCode C20526, got %s", data.ProductCode)
+	}
+
+	s := data.Symbol
+	if s == nil {
+		t.Fatal("expected symbol")
+	}
+	if s.Title != "MMBT3904" || s.Prefix != "Q?" || s.Origin != (Point{400, 300}) {
+		t.Errorf("unexpected symbol header: %+v", s)
+	}
+
+	if len(s.Pins) != 3 {
+		t.Fatalf("expected 3 pins, got %d", len(s.Pins))
+	}
+	expected := []SymbolPin{
+		{Number: "1", Name: "B", Type: PinUnspecified, Position: Point{370, 300}, Rotation: 0, Length: 15},
+		{Number: "3", Name: "C", Type: PinOutput, Position: Point{420, 290}, Rotation: 180, Length: 15},
+		{Number: "2", Name: "E", Type: PinPower, Position: Point{420, 310}, Rotation: 180, Length: 15},
+	}
+	for i, want := range expected {
+		if s.Pins[i] != want {
+			t.Errorf("pin %d: expected %+v, got %+v", i, want, s.Pins[i])
+		}
+	}
+
+	if len(s.Rectangles) != 1 || s.Rectangles[0].Width != 20 || s.Rectangles[0].Height != 30 || s.Rectangles[0].Filled != true {
+		t.Errorf("unexpected rectangles: %+v", s.Rectangles)
+	}
+	if len(s.Polylines) != 2 || len(s.Polylines[0].Points) != 3 || s.Polylines[0].Closed || !s.Polylines[1].Closed || !s.Polylines[1].Filled {
+		t.Errorf("unexpected polylines: %+v", s.Polylines)
+	}
+	if len(s.Circles) != 2 || s.Circles[0].Radius != 8 || s.Circles[0].Filled || !s.Circles[1].Filled {
+		t.Errorf("unexpected circles: %+v", s.Circles)
+	}
+	if len(s.Arcs) != 1 || !strings.HasPrefix(s.Arcs[0].Path, "M 388 295 A") {
+		t.Errorf("unexpected arcs: %+v", s.Arcs)
+	}
+}
+
+// TestGetCADDataFootprint tests footprint and 3D model parsing.
+func TestGetCADDataFootprint(t *testing.T) {
+	var requests int32
+	client := newEasyEDAClient(t, readEasyEDAFixture(t), &requests)
+
+	data, err := client.GetCADData(context.Background(), "C20526")
+	if err != nil {
+		t.Fatalf("GetCADData failed: %v", err)
+	}
+
+	fp := data.Footprint
+	if fp == nil {
+		t.Fatal("expected footprint")
+	}
+	if fp.Title != "SOT-23-3_L2.9-W1.3-P1.90-LS2.4-BR" || !fp.SMD || fp.Origin != (Point{4000, 3000}) {
+		t.Errorf("unexpected footprint header: %+v", fp)
+	}
+
+	if len(fp.Pads) != 4 {
+		t.Fatalf("expected 4 pads, got %d", len(fp.Pads))
+	}
+	pad := fp.Pads[0]
+	if pad.Number != "1" || pad.Shape != PadRect || pad.Position != (Point{3996.26, 3004.33}) ||
+		pad.Width != 3.15 || pad.Height != 2.36 || pad.Rotation != 90 || pad.Layer != LayerTopCopper || pad.HoleRadius != 0 {
+		t.Errorf("unexpected pad 1: %+v", pad)
+	}
+	if fp.Pads[2].Shape != PadPolygon || len(fp.Pads[2].Points) != 4 {
+		t.Errorf("expected polygon pad with 4 points, got %+v", fp.Pads[2])
+	}
+	if len(fp.Pads[0].Points) != 0 {
+		t.Errorf("expected no outline for rectangular pad, got %+v", fp.Pads[0].Points)
+	}
+	th := fp.Pads[3]
+	if th.Shape != PadEllipse || th.Layer != LayerMulti || th.HoleRadius != 1.5 || !th.Plated {
+		t.Errorf("unexpected through-hole pad: %+v", th)
+	}
+
+	if len(fp.Holes) != 1 || fp.Holes[0].Radius != 0.6 {
+		t.Errorf("unexpected holes: %+v", fp.Holes)
+	}
+	if len(fp.Tracks) != 2 || fp.Tracks[0].Layer != LayerTopSilk || fp.Tracks[0].StrokeWidth != 0.6 || len(fp.Tracks[0].Points) != 3 {
+		t.Errorf("unexpected tracks: %+v", fp.Tracks)
+	}
+	if len(fp.Circles) != 1 || fp.Circles[0].Radius != 0.3 || fp.Circles[0].Layer != LayerTopSilk {
+		t.Errorf("unexpected circles: %+v", fp.Circles)
+	}
+	if len(fp.Arcs) != 1 || fp.Arcs[0].Layer != LayerTopSilk {
+		t.Errorf("unexpected arcs: %+v", fp.Arcs)
+	}
+	if len(fp.Rectangles) != 1 || fp.Rectangles[0].Layer != LayerDocument || fp.Rectangles[0].Width != 11.6 {
+		t.Errorf("unexpected rectangles: %+v", fp.Rectangles)
+	}
+
+	m := data.Model3D
+	if m == nil {
+		t.Fatal("expected 3D model")
+	}
+	if m.UUID != "0f2b5a9c4c1e4fd8a3b6f9e2d7c8a1b0" || m.Origin != (Point{4000, 3000}) || m.Z != -0.5 || m.Rotation != [3]float64{0, 0, 90} {
+		t.Errorf("unexpected 3D model: %+v", m)
+	}
+	if !strings.HasSuffix(m.OBJURL(), "/3dmodel/"+m.UUID) || !strings.HasSuffix(m.STEPURL(), "/"+m.UUID) {
+		t.Errorf("unexpected model URLs: %s %s", m.OBJURL(), m.STEPURL())
+	}
+}
+
+// TestGetCADDataCache tests that CAD data is served from the client cache.
+func TestGetCADDataCache(t *testing.T) {
+	var requests int32
+	client := newEasyEDAClient(t, readEasyEDAFixture(t), &requests, WithCache(NewMemoryCache(time.Hour)))
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetCADData(context.Background(), "C20526"); err != nil {
+			t.Fatalf("GetCADData failed: %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
+
+// TestGetCADDataErrors tests missing data and API errors.
+func TestGetCADDataErrors(t *testing.T) {
+	var requests int32
+	client := newEasyEDAClient(t, []byte(`{"success":false,"code":404,"message":"not found"}`), &requests)
+
+	if _, err := client.GetCADData(context.Background(), "C1"); !errors.Is(err, ErrNoCADData) {
+		t.Errorf("expected ErrNoCADData, got %v", err)
+	}
+	if _, err := client.GetCADData(context.Background(), "C20526"); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("expected ErrProductNotFound, got %v", err)
+	}
+	if _, err := client.GetCADData(context.Background(), " "); err == nil {
+		t.Error("expected error for empty product code")
+	}
+}
+
+// TestPinVector tests pin direction extraction from SVG paths.
+func TestPinVector(t *testing.T) {
+	tests := []struct {
+		path   string
+		dx, dy float64
+		ok     bool
+	}{
+		{"M 410 300 h -10", -10, 0, true},
+		{"M 400 300 v 20", 0, 20, true},
+		{"M 400 300 H 385", -15, 0, true},
+		{"M 400 300 V 310", 0, 10, true},
+		{"M 400 300 L 400 290", 0, -10, true},
+		{"", 0, 0, false},
+		{"M 400 300", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		dx, dy, ok := pinVector(tt.path)
+		if dx != tt.dx || dy != tt.dy || ok != tt.ok {
+			t.Errorf("pinVector(%q) = %v, %v, %v, expected %v, %v, %v", tt.path, dx, dy, ok, tt.dx, tt.dy, tt.ok)
+		}
+	}
+}
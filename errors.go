@@ -10,6 +10,9 @@ var (
 	ErrProductNotFound    = errors.New("lcsc: product not found")
 	ErrInternalServer     = errors.New("lcsc: internal server error")
 	ErrServiceUnavailable = errors.New("lcsc: service unavailable")
+	ErrBlocked            = errors.New("lcsc: request blocked")
+
+	ErrManufacturerNotFound = errors.New("lcsc: manufacturer not found")
 )
 
 // APIError represents an error returned by the LCSC API.
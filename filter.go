@@ -0,0 +1,234 @@
+package lcsc
+
+import (
+	"sort"
+	"strings"
+)
+
+// Filter reports whether a product should be kept.
+type Filter func(p *Product) bool
+
+// Order compares two products for sorting, returning a negative number when
+// a sorts before b, a positive number when b sorts before a, and zero when
+// they are equal.
+type Order func(a, b *Product) int
+
+// FilterProducts returns the products accepted by all filters, keeping
+// their order. The input slice is not modified.
+func FilterProducts(products []Product, filters ...Filter) []Product {
+	keep := All(filters...)
+	result := make([]Product, 0, len(products))
+	for i := range products {
+		if keep(&products[i]) {
+			result = append(result, products[i])
+		}
+	}
+	return result
+}
+
+// SortProducts sorts products in place by the given orders, using later
+// orders to break ties. The sort is stable, so products equal under all
+// orders keep their relevance order.
+func SortProducts(products []Product, orders ...Order) {
+	if len(orders) == 0 {
+		return
+	}
+	sort.SliceStable(products, func(i, j int) bool {
+		for _, order := range orders {
+			if c := order(&products[i], &products[j]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+// All returns a filter accepting products accepted by every filter.
+func All(filters ...Filter) Filter {
+	return func(p *Product) bool {
+		for _, f := range filters {
+			if !f(p) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Any returns a filter accepting products accepted by at least one filter.
+func Any(filters ...Filter) Filter {
+	return func(p *Product) bool {
+		for _, f := range filters {
+			if f(p) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a filter accepting products rejected by f.
+func Not(f Filter) Filter {
+	return func(p *Product) bool {
+		return !f(p)
+	}
+}
+
+// InStock accepts products with stock for at least qty units.
+func InStock(qty int) Filter {
+	if qty < 1 {
+		qty = 1
+	}
+	return func(p *Product) bool {
+		return int(p.StockNumber) >= qty
+	}
+}
+
+// MaxUnitPrice accepts priced products whose unit price when ordering qty
+// units is at most price.
+func MaxUnitPrice(qty int, price float64) Filter {
+	return func(p *Product) bool {
+		unit := p.UnitPrice(qty)
+		return unit > 0 && unit <= price
+	}
+}
+
+// Brand accepts products from any of the given manufacturers, matched
+// across spellings with ManufacturersMatch, so "TI" accepts
+// "TI(Texas Instruments)".
+func Brand(names ...string) Filter {
+	return func(p *Product) bool {
+		for _, name := range names {
+			if ManufacturersMatch(p.BrandNameEn, name) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Package accepts products in any of the given packages (EncapStandard),
+// compared case-insensitively.
+func Package(packages ...string) Filter {
+	return func(p *Product) bool {
+		return equalsAny(p.EncapStandard, packages)
+	}
+}
+
+// ParamRange accepts products whose named parameter lies within min and max,
+// inclusive. Bounds are parameter values such as "10nF" or "25V"; an empty
+// bound is open. Products without the parameter, or with a value that does
+// not parse or has a different unit than a bound, are rejected.
+func ParamRange(name, min, max string) Filter {
+	lo, hi := ParseParamValue(min), ParseParamValue(max)
+	return func(p *Product) bool {
+		v, ok := p.ParsedParam(name)
+		if !ok || !v.Valid {
+			return false
+		}
+		if strings.TrimSpace(min) != "" && (!comparableUnits(lo, v) || v.Value < lo.Value*(1-valueTolerance)) {
+			return false
+		}
+		if strings.TrimSpace(max) != "" && (!comparableUnits(hi, v) || v.Value > hi.Value*(1+valueTolerance)) {
+			return false
+		}
+		return true
+	}
+}
+
+// ParamEquals accepts products whose named parameter equals value, so
+// "100nF" matches "0.1uF". Values that do not parse are compared as text.
+func ParamEquals(name, value string) Filter {
+	want := ParseParamValue(value)
+	return func(p *Product) bool {
+		have, ok := p.ParsedParam(name)
+		return ok && sameParamValue(want, have)
+	}
+}
+
+// ByStock orders products by stock, most first.
+func ByStock() Order {
+	return func(a, b *Product) int {
+		return compareFloat(float64(b.StockNumber), float64(a.StockNumber))
+	}
+}
+
+// ByUnitPrice orders products by unit price when ordering qty units,
+// cheapest first. Products without pricing sort last.
+func ByUnitPrice(qty int) Order {
+	return func(a, b *Product) int {
+		pa, pb := a.UnitPrice(qty), b.UnitPrice(qty)
+		if pa == 0 || pb == 0 {
+			return compareFloat(pb, pa)
+		}
+		return compareFloat(pa, pb)
+	}
+}
+
+// ByParam orders products by the parsed value of the named parameter,
+// lowest first. Products without a numeric value sort last.
+func ByParam(name string) Order {
+	return func(a, b *Product) int {
+		va, oka := a.ParsedParam(name)
+		vb, okb := b.ParsedParam(name)
+		oka, okb = oka && va.Valid, okb && vb.Valid
+		if !oka || !okb {
+			return compareBool(okb, oka)
+		}
+		return compareFloat(va.Value, vb.Value)
+	}
+}
+
+// Reverse returns an order sorting the opposite way, including products
+// with missing values.
+func Reverse(o Order) Order {
+	return func(a, b *Product) int {
+		return o(b, a)
+	}
+}
+
+// applyListOptions filters and sorts a page of products as requested by
+// the Filters and Sort of a request. Without either, products is returned
+// as is.
+func applyListOptions(products []Product, filters []Filter, orders []Order) []Product {
+	if len(filters) == 0 && len(orders) == 0 {
+		return products
+	}
+	result := FilterProducts(products, filters...)
+	SortProducts(result, orders...)
+	return result
+}
+
+// equalsAny reports whether s equals any of values, ignoring case and
+// surrounding space.
+func equalsAny(s string, values []string) bool {
+	s = strings.TrimSpace(s)
+	for _, v := range values {
+		if strings.EqualFold(s, strings.TrimSpace(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareFloat returns -1, 0 or 1 comparing a and b.
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareBool orders false before true.
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,121 @@
+package lcsc
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+// filterProducts are capacitors in relevance order for filter tests.
+var filterProducts = []Product{
+	withBrand(mlcc("C1", "100nF", "±10%", "50V", "X7R", 0, 0.001), "Samsung"),
+	withBrand(mlcc("C2", "0.1uF", "±10%", "25V", "X7R", 5000, 0.003), "Yageo"),
+	withBrand(mlcc("C3", "100nF", "±10%", "16V", "X7R", 20000, 0.002), "Samsung"),
+	withBrand(mlcc("C4", "1uF", "±10%", "50V", "X7R", 800, 0.004), "Murata"),
+	withBrand(mlcc("C5", "100nF", "±10%", "100V", "X7R", 5000, 0), "Murata"),
+}
+
+// withBrand sets the manufacturer of a test product.
+func withBrand(p Product, brand string) Product {
+	p.BrandNameEn = brand
+	return p
+}
+
+// codes returns the product codes of products.
+func codes(products []Product) []string {
+	result := make([]string, len(products))
+	for i, p := range products {
+		result[i] = p.ProductCode
+	}
+	return result
+}
+
+// TestFilterProducts tests composing product filters.
+func TestFilterProducts(t *testing.T) {
+	tests := []struct {
+		name     string
+		filters  []Filter
+		expected []string
+	}{
+		{"no filters", nil, []string{"C1", "C2", "C3", "C4", "C5"}},
+		{"in stock", []Filter{InStock(1000)}, []string{"C2", "C3", "C5"}},
+		{"brand", []Filter{Brand(" samsung ")}, []string{"C1", "C3"}},
+		{"brand alias", []Filter{Brand("SEMCO")}, []string{"C1", "C3"}},
+		{"package", []Filter{Package("0805")}, []string{}},
+		{"value", []Filter{ParamEquals("Capacitance", "100nF")}, []string{"C1", "C2", "C3", "C5"}},
+		{"voltage range", []Filter{ParamRange("Voltage Rated", "25V", "50V")}, []string{"C1", "C2", "C4"}},
+		{"open range", []Filter{ParamRange("Voltage Rated", "", "20V")}, []string{"C3"}},
+		{"wrong unit", []Filter{ParamRange("Voltage Rated", "1A", "")}, []string{}},
+		{"max price", []Filter{MaxUnitPrice(100, 0.002)}, []string{"C1", "C3"}},
+		{"any", []Filter{Any(Brand("Yageo"), Brand("Murata"))}, []string{"C2", "C4", "C5"}},
+		{"not", []Filter{Not(Brand("Samsung")), InStock(1)}, []string{"C2", "C4", "C5"}},
+	}
+
+	for _, test := range tests {
+		got := codes(FilterProducts(filterProducts, test.filters...))
+		if len(got) != len(test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.expected[i] {
+				t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+				break
+			}
+		}
+	}
+}
+
+// TestSortProducts tests multi-key sorting with missing values last.
+func TestSortProducts(t *testing.T) {
+	tests := []struct {
+		name     string
+		orders   []Order
+		expected string
+	}{
+		{"none", nil, "C1 C2 C3 C4 C5"},
+		{"price", []Order{ByUnitPrice(100)}, "C1 C3 C2 C4 C5"},
+		{"stock then price", []Order{ByStock(), ByUnitPrice(100)}, "C3 C2 C5 C4 C1"},
+		{"voltage", []Order{ByParam("Voltage Rated")}, "C3 C2 C1 C4 C5"},
+		{"voltage descending", []Order{Reverse(ByParam("Voltage Rated"))}, "C5 C1 C4 C2 C3"},
+	}
+
+	for _, test := range tests {
+		products := append([]Product(nil), filterProducts...)
+		SortProducts(products, test.orders...)
+		got := ""
+		for i, code := range codes(products) {
+			if i > 0 {
+				got += " "
+			}
+			got += code
+		}
+		if got != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, got)
+		}
+	}
+}
+
+// TestKeywordSearchFilters tests filter and sort options on searches.
+func TestKeywordSearchFilters(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeAPIResult(t, w, map[string]interface{}{
+			"productSearchResultVO": map[string]interface{}{"productList": filterProducts, "totalCount": 5},
+		})
+	})
+
+	resp, err := client.KeywordSearch(context.Background(), SearchRequest{
+		Keyword: "100nF 0603",
+		Filters: []Filter{InStock(100), ParamEquals("Capacitance", "100nF")},
+		Sort:    []Order{ByUnitPrice(100)},
+	})
+	if err != nil {
+		t.Fatalf("KeywordSearch failed: %v", err)
+	}
+	if got := codes(resp.Products); len(got) != 3 || got[0] != "C3" || got[1] != "C2" || got[2] != "C5" {
+		t.Errorf("unexpected products: %v", got)
+	}
+	if resp.TotalCount != 5 {
+		t.Errorf("expected unfiltered total count, got %d", resp.TotalCount)
+	}
+}
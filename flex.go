@@ -0,0 +1,149 @@
+package lcsc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The LCSC endpoints are not consistent about JSON types: the same field may
+// arrive as a number, a quoted number, an empty string or null depending on
+// the product and endpoint. The Flex types below accept all of these forms so
+// that a single odd field does not fail decoding of a whole product.
+//
+// Null and empty-string values decode to the zero value. Use the NullFlex
+// variants when the distinction between "absent" and "zero" matters.
+
+// FlexFloat64 handles JSON values that may be either a number or a string.
+type FlexFloat64 float64
+
+// UnmarshalJSON implements json.Unmarshaler for FlexFloat64.
+func (f *FlexFloat64) UnmarshalJSON(data []byte) error {
+	num, _, err := parseFlexFloat(data)
+	if err != nil {
+		return err
+	}
+	*f = FlexFloat64(num)
+	return nil
+}
+
+// FlexInt handles JSON integer values that may be a number, a string or null.
+type FlexInt int
+
+// UnmarshalJSON implements json.Unmarshaler for FlexInt.
+func (i *FlexInt) UnmarshalJSON(data []byte) error {
+	num, _, err := parseFlexInt(data)
+	if err != nil {
+		return err
+	}
+	*i = FlexInt(num)
+	return nil
+}
+
+// NullFlexFloat64 is a FlexFloat64 that records whether a value was present.
+// Valid is false when the JSON value was null or an empty string.
+type NullFlexFloat64 struct {
+	Float64 float64
+	Valid   bool
+}
+
+// UnmarshalJSON implements json.Unmarshaler for NullFlexFloat64.
+func (n *NullFlexFloat64) UnmarshalJSON(data []byte) error {
+	num, null, err := parseFlexFloat(data)
+	if err != nil {
+		return err
+	}
+	n.Float64, n.Valid = num, !null
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler for NullFlexFloat64.
+func (n NullFlexFloat64) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Float64)
+}
+
+// NullFlexInt is a FlexInt that records whether a value was present.
+// Valid is false when the JSON value was null or an empty string.
+type NullFlexInt struct {
+	Int   int
+	Valid bool
+}
+
+// UnmarshalJSON implements json.Unmarshaler for NullFlexInt.
+func (n *NullFlexInt) UnmarshalJSON(data []byte) error {
+	num, null, err := parseFlexInt(data)
+	if err != nil {
+		return err
+	}
+	n.Int, n.Valid = num, !null
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler for NullFlexInt.
+func (n NullFlexInt) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Int)
+}
+
+// flexString extracts the textual form of a JSON scalar. It reports null for
+// JSON null and for empty or whitespace-only strings.
+func flexString(data []byte) (s string, null bool, err error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return "", true, nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return "", false, err
+		}
+		s = strings.TrimSpace(s)
+		return s, s == "", nil
+	}
+	return string(data), false, nil
+}
+
+// parseFlexFloat decodes a number or quoted number into a float64.
+func parseFlexFloat(data []byte) (float64, bool, error) {
+	s, null, err := flexString(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("cannot unmarshal %s into FlexFloat64", string(data))
+	}
+	if null {
+		return 0, true, nil
+	}
+	num, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(num) || math.IsInf(num, 0) {
+		return 0, false, fmt.Errorf("cannot parse %q as float64", s)
+	}
+	return num, false, nil
+}
+
+// parseFlexInt decodes a number or quoted number into an int. Values written
+// with a zero fraction or exponent ("12.0", "1e3") are accepted; other
+// fractions are errors. Values must fit in 32 bits in either form, so they
+// decode alike on every platform.
+func parseFlexInt(data []byte) (int, bool, error) {
+	s, null, err := flexString(data)
+	if err != nil {
+		return 0, false, fmt.Errorf("cannot unmarshal %s into FlexInt", string(data))
+	}
+	if null {
+		return 0, true, nil
+	}
+	if num, err := strconv.ParseInt(s, 10, 32); err == nil {
+		return int(num), false, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) || f < math.MinInt32 || f > math.MaxInt32 {
+		return 0, false, fmt.Errorf("cannot parse %q as int", s)
+	}
+	return int(f), false, nil
+}
@@ -0,0 +1,275 @@
+package lcsc
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestFlexIntUnmarshal tests unmarshaling the accepted FlexInt forms.
+func TestFlexIntUnmarshal(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected int
+	}{
+		{`42`, 42},
+		{`"42"`, 42},
+		{`" 42 "`, 42},
+		{`-7`, -7},
+		{`"-7"`, -7},
+		{`12.0`, 12},
+		{`"12.0"`, 12},
+		{`1e3`, 1000},
+		{`2147483647`, 2147483647},
+		{`"-2147483648"`, -2147483648},
+		{`null`, 0},
+		{`""`, 0},
+	}
+
+	for _, test := range tests {
+		var i FlexInt
+		if err := json.Unmarshal([]byte(test.data), &i); err != nil {
+			t.Errorf("failed to unmarshal %s: %v", test.data, err)
+			continue
+		}
+		if int(i) != test.expected {
+			t.Errorf("expected %d for input %s, got %d", test.expected, test.data, i)
+		}
+	}
+}
+
+// TestFlexIntUnmarshalInvalid tests error handling for invalid FlexInt values.
+func TestFlexIntUnmarshalInvalid(t *testing.T) {
+	invalidData := []string{
+		`"abc"`,
+		`12.5`,
+		`"12.5"`,
+		`true`,
+		`[]`,
+		`{}`,
+		`"NaN"`,
+		`2147483648`,
+		`"-2147483649"`,
+		`2147483648.0`,
+	}
+
+	for _, data := range invalidData {
+		var i FlexInt
+		if err := json.Unmarshal([]byte(data), &i); err == nil {
+			t.Errorf("expected error for invalid data %s, but got none", data)
+		}
+	}
+}
+
+// TestFlexFloat64UnmarshalNullAndEmpty tests that null and "" decode to zero.
+func TestFlexFloat64UnmarshalNullAndEmpty(t *testing.T) {
+	for _, data := range []string{`null`, `""`, `"  "`} {
+		f := FlexFloat64(1)
+		if err := json.Unmarshal([]byte(data), &f); err != nil {
+			t.Errorf("failed to unmarshal %s: %v", data, err)
+			continue
+		}
+		if f != 0 {
+			t.Errorf("expected 0 for input %s, got %f", data, f)
+		}
+	}
+}
+
+// TestNullFlexTypesValidity tests that nullable variants track presence.
+func TestNullFlexTypesValidity(t *testing.T) {
+	var v struct {
+		A NullFlexInt     `json:"a"`
+		B NullFlexInt     `json:"b"`
+		C NullFlexFloat64 `json:"c"`
+		D NullFlexFloat64 `json:"d"`
+	}
+
+	data := []byte(`{"a": "0", "b": null, "c": "1.5", "d": ""}`)
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if !v.A.Valid || v.A.Int != 0 {
+		t.Errorf("expected valid 0, got %+v", v.A)
+	}
+	if v.B.Valid {
+		t.Errorf("expected null int to be invalid, got %+v", v.B)
+	}
+	if !v.C.Valid || v.C.Float64 != 1.5 {
+		t.Errorf("expected valid 1.5, got %+v", v.C)
+	}
+	if v.D.Valid {
+		t.Errorf("expected empty float to be invalid, got %+v", v.D)
+	}
+}
+
+// TestNullFlexTypesMarshal tests that nullable variants marshal to null or a scalar.
+func TestNullFlexTypesMarshal(t *testing.T) {
+	v := struct {
+		A NullFlexInt     `json:"a"`
+		B NullFlexInt     `json:"b"`
+		C NullFlexFloat64 `json:"c"`
+	}{
+		A: NullFlexInt{Int: 5, Valid: true},
+		C: NullFlexFloat64{Float64: 2.5, Valid: true},
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	expected := `{"a":5,"b":null,"c":2.5}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+}
+
+// TestProductUnmarshalInconsistentTypes tests decoding a product whose numeric
+// fields arrive as strings or null.
+func TestProductUnmarshalInconsistentTypes(t *testing.T) {
+	data := []byte(`{
+		"productCode": "C8734",
+		"stockNumber": "1200",
+		"minPacketNumber": null,
+		"weight": "",
+		"productPriceList": [
+			{"ladder": "1", "productPrice": "0.5", "currencySymbol": "US$"},
+			{"ladder": 100, "productPrice": 0.4, "currencySymbol": "US$"}
+		]
+	}`)
+
+	var product Product
+	if err := json.Unmarshal(data, &product); err != nil {
+		t.Fatalf("failed to unmarshal product: %v", err)
+	}
+
+	if product.StockNumber != 1200 {
+		t.Errorf("expected stock 1200, got %d", product.StockNumber)
+	}
+	if product.MinPacketNumber != 0 {
+		t.Errorf("expected min packet 0, got %d", product.MinPacketNumber)
+	}
+	if product.Weight != 0 {
+		t.Errorf("expected weight 0, got %f", product.Weight)
+	}
+	if len(product.ProductPriceList) != 2 || product.ProductPriceList[0].Ladder != 1 {
+		t.Errorf("unexpected price list: %+v", product.ProductPriceList)
+	}
+}
+
+// TestProductRoundTrip tests that a product survives a marshal/unmarshal cycle,
+// as it does when stored in the cache.
+func TestProductRoundTrip(t *testing.T) {
+	original := Product{
+		ProductCode:      "C1",
+		StockNumber:      10,
+		MinPacketNumber:  5,
+		Weight:           0.25,
+		ProductPriceList: []PriceBreak{{Ladder: 5, ProductPrice: 0.1, CurrencySymbol: "US$"}},
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	var decoded Product
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if decoded.StockNumber != original.StockNumber ||
+		decoded.MinPacketNumber != original.MinPacketNumber ||
+		decoded.Weight != original.Weight ||
+		decoded.ProductPriceList[0].Ladder != original.ProductPriceList[0].Ladder {
+		t.Errorf("round trip mismatch: %+v vs %+v", original, decoded)
+	}
+}
+
+// flexSeeds are shared seed inputs for the flexible type fuzz tests.
+var flexSeeds = []string{
+	`0`, `1`, `-1`, `1.5`, `"1.5"`, `"12"`, `""`, `null`, `true`, `false`,
+	`"true"`, `"yes"`, `[]`, `{}`, `"abc"`, `1e3`, `"1e309"`, `"NaN"`, `99999999999999999999`,
+}
+
+// FuzzFlexFloat64 checks that FlexFloat64 never panics and round-trips.
+func FuzzFlexFloat64(f *testing.F) {
+	for _, seed := range flexSeeds {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var v FlexFloat64
+		if err := json.Unmarshal(data, &v); err != nil {
+			return
+		}
+		out, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("failed to marshal %v: %v", v, err)
+		}
+		var back FlexFloat64
+		if err := json.Unmarshal(out, &back); err != nil || back != v {
+			t.Fatalf("round trip of %s failed: %v (%v != %v)", data, err, back, v)
+		}
+	})
+}
+
+// FuzzFlexInt checks that FlexInt never panics and round-trips.
+func FuzzFlexInt(f *testing.F) {
+	for _, seed := range flexSeeds {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var v FlexInt
+		if err := json.Unmarshal(data, &v); err != nil {
+			return
+		}
+		out, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("failed to marshal %v: %v", v, err)
+		}
+		var back FlexInt
+		if err := json.Unmarshal(out, &back); err != nil || back != v {
+			t.Fatalf("round trip of %s failed: %v (%v != %v)", data, err, back, v)
+		}
+	})
+}
+
+// FuzzNullFlexTypes checks that the nullable variants never panic, round-trip,
+// and agree with their non-nullable counterparts.
+func FuzzNullFlexTypes(f *testing.F) {
+	for _, seed := range flexSeeds {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var ni NullFlexInt
+		var i FlexInt
+		if errN, err := json.Unmarshal(data, &ni), json.Unmarshal(data, &i); (errN == nil) != (err == nil) {
+			t.Fatalf("NullFlexInt and FlexInt disagree on %s: %v vs %v", data, errN, err)
+		} else if errN == nil {
+			if ni.Int != int(i) {
+				t.Fatalf("NullFlexInt %d != FlexInt %d for %s", ni.Int, i, data)
+			}
+			roundTripNull(t, ni, &NullFlexInt{})
+		}
+
+		var nf NullFlexFloat64
+		if err := json.Unmarshal(data, &nf); err == nil {
+			roundTripNull(t, nf, &NullFlexFloat64{})
+		}
+	})
+}
+
+// roundTripNull marshals v, decodes it into back and compares the results.
+func roundTripNull[T comparable](t *testing.T, v T, back *T) {
+	t.Helper()
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to marshal %+v: %v", v, err)
+	}
+	if err := json.Unmarshal(out, back); err != nil {
+		t.Fatalf("failed to unmarshal %s: %v", out, err)
+	}
+	if *back != v {
+		t.Fatalf("round trip mismatch: %+v != %+v", *back, v)
+	}
+}
@@ -0,0 +1,258 @@
+package lcsc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoSnapshot is returned when a snapshot store has no snapshot for a product.
+var ErrNoSnapshot = errors.New("lcsc: no snapshot")
+
+// ProductSnapshot is a product as fetched at a point in time.
+type ProductSnapshot struct {
+	Time     time.Time `json:"time"`
+	Currency string    `json:"currency,omitempty"` // Currency code of the prices; empty if unknown
+	Product  Product   `json:"product"`
+}
+
+// HistoryPoint is the stock and pricing of a product at one snapshot.
+type HistoryPoint struct {
+	Time        time.Time
+	StockNumber int
+	PriceList   []PriceBreak
+	Currency    string
+}
+
+// SnapshotChange is the difference between two consecutive snapshots.
+type SnapshotChange struct {
+	From time.Time
+	To   time.Time
+	Diff *ProductDiff
+}
+
+// SnapshotStore keeps product snapshots on disk, keyed by ProductCode and
+// timestamp, as one JSON file per snapshot under a directory per product.
+// It is safe for concurrent use.
+type SnapshotStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewSnapshotStore creates a snapshot store in dir, creating the directory
+// if needed.
+func NewSnapshotStore(dir string) (*SnapshotStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	return &SnapshotStore{dir: dir}, nil
+}
+
+// Save stores a snapshot of product taken now.
+func (s *SnapshotStore) Save(product *Product) error {
+	return s.SaveAt(product, time.Now())
+}
+
+// SaveAt stores a snapshot of product taken at t. A snapshot with the same
+// code and timestamp is replaced. The currency is taken from the symbol of
+// the product's prices.
+func (s *SnapshotStore) SaveAt(product *Product, t time.Time) error {
+	if product == nil {
+		return fmt.Errorf("product is required")
+	}
+	dir, err := s.productDir(product.ProductCode)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(ProductSnapshot{Time: t, Currency: priceCurrency(product), Product: *product})
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	name := fmt.Sprintf("%020d.json", t.UnixNano())
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// Snapshots returns all snapshots of a product, oldest first.
+func (s *SnapshotStore) Snapshots(productCode string) ([]ProductSnapshot, error) {
+	return s.Range(productCode, time.Time{}, time.Time{})
+}
+
+// Range returns the snapshots of a product taken in [from, to], oldest
+// first. A zero from or to leaves that end of the range open.
+func (s *SnapshotStore) Range(productCode string, from, to time.Time) ([]ProductSnapshot, error) {
+	dir, err := s.productDir(productCode)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	var snapshots []ProductSnapshot
+	for _, entry := range entries {
+		nanos, err := strconv.ParseInt(strings.TrimSuffix(entry.Name(), ".json"), 10, 64)
+		if err != nil || entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		t := time.Unix(0, nanos)
+		if (!from.IsZero() && t.Before(from)) || (!to.IsZero() && t.After(to)) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		var snapshot ProductSnapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return nil, fmt.Errorf("failed to parse snapshot %s: %w", entry.Name(), err)
+		}
+		if snapshot.Currency == "" {
+			snapshot.Currency = priceCurrency(&snapshot.Product)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Time.Before(snapshots[j].Time) })
+	return snapshots, nil
+}
+
+// Latest returns the most recent snapshot of a product, or ErrNoSnapshot.
+func (s *SnapshotStore) Latest(productCode string) (*ProductSnapshot, error) {
+	return s.At(productCode, time.Time{})
+}
+
+// At returns the latest snapshot of a product taken at or before t, or
+// ErrNoSnapshot. A zero t returns the most recent snapshot.
+func (s *SnapshotStore) At(productCode string, t time.Time) (*ProductSnapshot, error) {
+	snapshots, err := s.Range(productCode, time.Time{}, t)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, ErrNoSnapshot
+	}
+	return &snapshots[len(snapshots)-1], nil
+}
+
+// History returns the stock and price history of a product in [from, to],
+// oldest first. A zero from or to leaves that end of the range open.
+func (s *SnapshotStore) History(productCode string, from, to time.Time) ([]HistoryPoint, error) {
+	snapshots, err := s.Range(productCode, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]HistoryPoint, len(snapshots))
+	for i, snapshot := range snapshots {
+		points[i] = HistoryPoint{
+			Time:        snapshot.Time,
+			StockNumber: int(snapshot.Product.StockNumber),
+			PriceList:   snapshot.Product.ProductPriceList,
+			Currency:    snapshot.Currency,
+		}
+	}
+	return points, nil
+}
+
+// Changes returns the differences between consecutive snapshots of a
+// product, oldest first. Snapshots identical to their predecessor are
+// skipped. Prices are only compared between snapshots in the same currency.
+func (s *SnapshotStore) Changes(productCode string) ([]SnapshotChange, error) {
+	snapshots, err := s.Snapshots(productCode)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []SnapshotChange
+	for i := 1; i < len(snapshots); i++ {
+		before, after := &snapshots[i-1], &snapshots[i]
+		diff := DiffProducts(&before.Product, &after.Product)
+		if before.Currency != "" && after.Currency != "" && before.Currency != after.Currency {
+			diff.PriceBreaks = nil
+		}
+		if diff.Empty() {
+			continue
+		}
+		changes = append(changes, SnapshotChange{
+			From: before.Time,
+			To:   after.Time,
+			Diff: diff,
+		})
+	}
+	return changes, nil
+}
+
+// Codes returns the product codes with at least one snapshot, sorted.
+func (s *SnapshotStore) Codes() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	var codes []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			codes = append(codes, entry.Name())
+		}
+	}
+	sort.Strings(codes)
+	return codes, nil
+}
+
+// priceCurrency returns the currency code of a product's prices, from the
+// symbol of its first price break, or "" if unknown.
+func priceCurrency(p *Product) string {
+	if len(p.ProductPriceList) == 0 {
+		return ""
+	}
+	if cur, ok := CurrencyBySymbol(p.ProductPriceList[0].CurrencySymbol); ok {
+		return cur.Code
+	}
+	return ""
+}
+
+// productDir returns the directory holding a product's snapshots.
+// Product codes are upper-cased and must consist of letters, digits,
+// '-' and '_', so they cannot escape the store directory.
+func (s *SnapshotStore) productDir(productCode string) (string, error) {
+	code := strings.ToUpper(strings.TrimSpace(productCode))
+	if code == "" {
+		return "", fmt.Errorf("productCode is required")
+	}
+	for _, r := range code {
+		if !(r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return "", fmt.Errorf("invalid productCode %q", productCode)
+		}
+	}
+	return filepath.Join(s.dir, code), nil
+}
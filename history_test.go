@@ -0,0 +1,152 @@
+package lcsc
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestSnapshotStoreHistory tests saving snapshots and querying history.
+func TestSnapshotStoreHistory(t *testing.T) {
+	store, err := NewSnapshotStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, stock := range []FlexInt{1000, 800, 800} {
+		p := diffTestProduct()
+		p.StockNumber = stock
+		if err := store.SaveAt(p, base.Add(time.Duration(i)*24*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history, err := store.History("c1525", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("expected 3 history points, got %d", len(history))
+	}
+	if history[0].StockNumber != 1000 || history[1].StockNumber != 800 || !history[2].Time.Equal(base.Add(48*time.Hour)) {
+		t.Errorf("unexpected history: %+v", history)
+	}
+	if len(history[0].PriceList) != 2 {
+		t.Errorf("expected price list in history, got %+v", history[0].PriceList)
+	}
+
+	history, _ = store.History("C1525", base.Add(time.Hour), base.Add(36*time.Hour))
+	if len(history) != 1 || history[0].StockNumber != 800 {
+		t.Errorf("expected one point in range, got %+v", history)
+	}
+}
+
+// TestSnapshotStoreLatestAndAt tests point-in-time lookups.
+func TestSnapshotStoreLatestAndAt(t *testing.T) {
+	store, _ := NewSnapshotStore(t.TempDir())
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := store.Latest("C1525"); !errors.Is(err, ErrNoSnapshot) {
+		t.Errorf("expected ErrNoSnapshot, got %v", err)
+	}
+
+	p := diffTestProduct()
+	_ = store.SaveAt(p, base)
+	p.StockNumber = 5
+	_ = store.SaveAt(p, base.Add(time.Hour))
+
+	latest, err := store.Latest("C1525")
+	if err != nil || latest.Product.StockNumber != 5 {
+		t.Errorf("expected latest stock 5, got %+v (%v)", latest, err)
+	}
+
+	at, err := store.At("C1525", base.Add(30*time.Minute))
+	if err != nil || at.Product.StockNumber != 1000 {
+		t.Errorf("expected stock 1000 at base+30m, got %+v (%v)", at, err)
+	}
+
+	if _, err := store.At("C1525", base.Add(-time.Hour)); !errors.Is(err, ErrNoSnapshot) {
+		t.Errorf("expected ErrNoSnapshot before first snapshot, got %v", err)
+	}
+}
+
+// TestSnapshotStoreChanges tests diffs between consecutive snapshots.
+func TestSnapshotStoreChanges(t *testing.T) {
+	store, _ := NewSnapshotStore(t.TempDir())
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	p := diffTestProduct()
+	_ = store.SaveAt(p, base)
+	_ = store.SaveAt(p, base.Add(time.Hour))
+	p.ProductPriceList[1].ProductPrice = 0.001
+	_ = store.SaveAt(p, base.Add(2*time.Hour))
+
+	changes, err := store.Changes("C1525")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 change, got %d", len(changes))
+	}
+	c := changes[0]
+	if !c.From.Equal(base.Add(time.Hour)) || !c.To.Equal(base.Add(2*time.Hour)) {
+		t.Errorf("unexpected change interval %v - %v", c.From, c.To)
+	}
+	if len(c.Diff.PriceBreaks) != 1 || c.Diff.PriceBreaks[0].Ladder != 100 {
+		t.Errorf("unexpected diff: %+v", c.Diff)
+	}
+}
+
+// TestSnapshotStoreCurrency tests that prices in different currencies are
+// not compared.
+func TestSnapshotStoreCurrency(t *testing.T) {
+	store, _ := NewSnapshotStore(t.TempDir())
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	withSymbol := func(p *Product, symbol string, factor float64) *Product {
+		for i := range p.ProductPriceList {
+			p.ProductPriceList[i].CurrencySymbol = symbol
+			p.ProductPriceList[i].ProductPrice *= FlexFloat64(factor)
+		}
+		return p
+	}
+	_ = store.SaveAt(withSymbol(diffTestProduct(), "US$", 1), base)
+	eur := withSymbol(diffTestProduct(), "€", 0.9)
+	eur.StockNumber++
+	_ = store.SaveAt(eur, base.Add(time.Hour))
+
+	latest, err := store.Latest("C1525")
+	if err != nil || latest.Currency != "EUR" {
+		t.Fatalf("expected a EUR snapshot, got %+v, %v", latest, err)
+	}
+	changes, err := store.Changes("C1525")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || len(changes[0].Diff.PriceBreaks) != 0 || len(changes[0].Diff.Fields) != 1 {
+		t.Errorf("expected only the stock change, got %+v", changes)
+	}
+}
+
+// TestSnapshotStoreCodes tests listing stored codes and rejecting invalid ones.
+func TestSnapshotStoreCodes(t *testing.T) {
+	store, _ := NewSnapshotStore(t.TempDir())
+	_ = store.Save(&Product{ProductCode: "C2"})
+	_ = store.Save(&Product{ProductCode: "c1"})
+
+	codes, err := store.Codes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != 2 || codes[0] != "C1" || codes[1] != "C2" {
+		t.Errorf("unexpected codes: %v", codes)
+	}
+
+	if err := store.Save(&Product{ProductCode: "../C1"}); err == nil {
+		t.Error("expected error for invalid product code")
+	}
+	if err := store.Save(&Product{}); err == nil {
+		t.Error("expected error for empty product code")
+	}
+}
@@ -0,0 +1,226 @@
+package kicad
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	lcsc "github.com/PatrickWalther/go-lcsc"
+)
+
+// footprintVersion is the .kicad_mod file format version written (KiCad 6).
+const footprintVersion = "20211014"
+
+// graphicLayers maps EasyEDA layer IDs to KiCad layers for outlines.
+// Shapes on other layers are dropped.
+var graphicLayers = map[int]string{
+	lcsc.LayerTopSilk:      "F.SilkS",
+	lcsc.LayerBottomSilk:   "B.SilkS",
+	lcsc.LayerTopPaste:     "F.Paste",
+	lcsc.LayerBottomPaste:  "B.Paste",
+	lcsc.LayerTopMask:      "F.Mask",
+	lcsc.LayerBottomMask:   "B.Mask",
+	lcsc.LayerBoardOutline: "Edge.Cuts",
+	lcsc.LayerDocument:     "Cmts.User",
+	lcsc.LayerTopAssembly:  "F.Fab",
+	14:                     "B.Fab",
+	15:                     "Dwgs.User",
+	lcsc.LayerComponent:    "F.Fab",
+	100:                    "F.Fab",
+	101:                    "F.Fab",
+}
+
+// FootprintName returns the name used for a part's footprint: the EasyEDA
+// package title, falling back to the LCSC code.
+func FootprintName(cad *lcsc.CADData) string {
+	if cad == nil {
+		return "footprint"
+	}
+	if cad.Footprint != nil {
+		if name := safeName(cad.Footprint.Title); name != "" {
+			return name
+		}
+	}
+	if name := safeName(cad.ProductCode); name != "" {
+		return name
+	}
+	return "footprint"
+}
+
+// WriteFootprint writes the .kicad_mod footprint of a part. modelPath is
+// the 3D model file referenced by the footprint, like
+// "${KIPRJMOD}/lcsc.3dshapes/SOT-23.step"; it is ignored if empty or if the
+// part has no 3D model.
+func WriteFootprint(w io.Writer, product *lcsc.Product, cad *lcsc.CADData, modelPath string) error {
+	if cad == nil || cad.Footprint == nil {
+		return ErrNoFootprint
+	}
+	if product == nil {
+		product = &lcsc.Product{}
+	}
+	fp := cad.Footprint
+
+	pt := func(p lcsc.Point) string {
+		return num(mm(p.X-fp.Origin.X)) + " " + num(mm(p.Y-fp.Origin.Y))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "(footprint %s (version %s) (generator %s) (layer \"F.Cu\")\n", quote(FootprintName(cad)), footprintVersion, generator)
+	if product.ProductIntroEn != "" {
+		fmt.Fprintf(&b, "  (descr %s)\n", quote(product.ProductIntroEn))
+	}
+	fmt.Fprintf(&b, "  (tags %s)\n", quote(strings.TrimSpace("LCSC "+firstNonEmpty(product.ProductCode, cad.ProductCode))))
+	if fp.SMD {
+		b.WriteString("  (attr smd)\n")
+	} else {
+		b.WriteString("  (attr through_hole)\n")
+	}
+
+	top, bottom := footprintBounds(fp)
+	fmt.Fprintf(&b, "  (fp_text reference \"REF**\" (at 0 %s) (layer \"F.SilkS\")\n    (effects (font (size 1 1) (thickness 0.15)))\n  )\n", num(top-1.5))
+	fmt.Fprintf(&b, "  (fp_text value %s (at 0 %s) (layer \"F.Fab\")\n    (effects (font (size 1 1) (thickness 0.15)))\n  )\n",
+		quote(firstNonEmpty(product.ProductModel, FootprintName(cad))), num(bottom+1.5))
+
+	for _, t := range fp.Tracks {
+		layer, ok := graphicLayers[t.Layer]
+		if !ok {
+			continue
+		}
+		for i := 1; i < len(t.Points); i++ {
+			fmt.Fprintf(&b, "  (fp_line (start %s) (end %s) (layer %s) (width %s))\n",
+				pt(t.Points[i-1]), pt(t.Points[i]), quote(layer), num(mm(t.StrokeWidth)))
+		}
+	}
+	for _, r := range fp.Rectangles {
+		layer, ok := graphicLayers[r.Layer]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, "  (fp_rect (start %s) (end %s) (layer %s) (width %s))\n",
+			pt(lcsc.Point{X: r.X, Y: r.Y}), pt(lcsc.Point{X: r.X + r.Width, Y: r.Y + r.Height}), quote(layer), num(mm(r.StrokeWidth)))
+	}
+	for _, c := range fp.Circles {
+		layer, ok := graphicLayers[c.Layer]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, "  (fp_circle (center %s) (end %s) (layer %s) (width %s))\n",
+			pt(c.Center), pt(lcsc.Point{X: c.Center.X + c.Radius, Y: c.Center.Y}), quote(layer), num(mm(c.StrokeWidth)))
+	}
+	for _, a := range fp.Arcs {
+		layer, ok := graphicLayers[a.Layer]
+		start, mid, end, valid := arcPoints(a.Path)
+		if !ok || !valid {
+			continue
+		}
+		fmt.Fprintf(&b, "  (fp_arc (start %s) (mid %s) (end %s) (layer %s) (width %s))\n",
+			pt(start), pt(mid), pt(end), quote(layer), num(mm(a.StrokeWidth)))
+	}
+
+	for _, pad := range fp.Pads {
+		b.WriteString(padExpr(pad, pt))
+	}
+	for _, h := range fp.Holes {
+		d := num(mm(2 * h.Radius))
+		fmt.Fprintf(&b, "  (pad \"\" np_thru_hole circle (at %s) (size %s %s) (drill %s) (layers \"*.Cu\" \"*.Mask\"))\n", pt(h.Position), d, d, d)
+	}
+
+	if m := cad.Model3D; m != nil && modelPath != "" {
+		rot := func(deg float64) string { return num(math.Mod(-deg, 360)) }
+		fmt.Fprintf(&b, "  (model %s\n    (offset (xyz %s %s %s))\n    (scale (xyz 1 1 1))\n    (rotate (xyz %s %s %s))\n  )\n",
+			quote(modelPath), num(mm(m.Origin.X-fp.Origin.X)), num(-mm(m.Origin.Y-fp.Origin.Y)), num(mm(m.Z)),
+			rot(m.Rotation[0]), rot(m.Rotation[1]), rot(m.Rotation[2]))
+	}
+
+	b.WriteString(")\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// padExpr returns the (pad ...) expression for a pad. pt formats a point
+// in footprint coordinates.
+func padExpr(pad lcsc.Pad, pt func(lcsc.Point) string) string {
+	padType, layers := "smd", `"F.Cu" "F.Paste" "F.Mask"`
+	switch {
+	case pad.HoleRadius > 0 && !pad.Plated:
+		padType, layers = "np_thru_hole", `"*.Cu" "*.Mask"`
+	case pad.HoleRadius > 0 || pad.Layer == lcsc.LayerMulti:
+		padType, layers = "thru_hole", `"*.Cu" "*.Mask"`
+	case pad.Layer == lcsc.LayerBottomCopper:
+		layers = `"B.Cu" "B.Paste" "B.Mask"`
+	}
+
+	shape := "rect"
+	switch pad.Shape {
+	case lcsc.PadEllipse:
+		shape = "oval"
+		if pad.Width == pad.Height {
+			shape = "circle"
+		}
+	case lcsc.PadOval:
+		shape = "oval"
+	case lcsc.PadPolygon:
+		if len(pad.Points) >= 3 {
+			shape = "custom"
+		}
+	}
+
+	at := pt(pad.Position)
+	if shape != "custom" && math.Mod(pad.Rotation, 360) != 0 {
+		at += " " + num(pad.Rotation)
+	}
+	size := num(mm(pad.Width)) + " " + num(mm(pad.Height))
+	if shape == "custom" {
+		size = "0.1 0.1"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "  (pad %s %s %s (at %s) (size %s)", quote(pad.Number), padType, shape, at, size)
+	if pad.HoleRadius > 0 {
+		d := mm(2 * pad.HoleRadius)
+		switch {
+		case pad.HoleLength > 0 && pad.Width >= pad.Height:
+			fmt.Fprintf(&b, " (drill oval %s %s)", num(mm(pad.HoleLength)), num(d))
+		case pad.HoleLength > 0:
+			fmt.Fprintf(&b, " (drill oval %s %s)", num(d), num(mm(pad.HoleLength)))
+		default:
+			fmt.Fprintf(&b, " (drill %s)", num(d))
+		}
+	}
+	fmt.Fprintf(&b, " (layers %s)", layers)
+
+	if shape == "custom" {
+		xy := make([]string, len(pad.Points))
+		for i, p := range pad.Points {
+			xy[i] = fmt.Sprintf("(xy %s %s)", num(mm(p.X-pad.Position.X)), num(mm(p.Y-pad.Position.Y)))
+		}
+		fmt.Fprintf(&b, "\n    (options (clearance outline) (anchor circle))\n    (primitives\n      (gr_poly (pts %s) (width 0) (fill yes))\n    )\n  ", strings.Join(xy, " "))
+	}
+	b.WriteString(")\n")
+	return b.String()
+}
+
+// footprintBounds returns the top and bottom of a footprint's pads and
+// outlines in KiCad coordinates (millimetres, Y down).
+func footprintBounds(fp *lcsc.Footprint) (top, bottom float64) {
+	top, bottom = math.Inf(1), math.Inf(-1)
+	add := func(y float64) {
+		y = mm(y - fp.Origin.Y)
+		top, bottom = math.Min(top, y), math.Max(bottom, y)
+	}
+	for _, pad := range fp.Pads {
+		half := math.Max(pad.Width, pad.Height) / 2
+		add(pad.Position.Y - half)
+		add(pad.Position.Y + half)
+	}
+	for _, t := range fp.Tracks {
+		for _, p := range t.Points {
+			add(p.Y)
+		}
+	}
+	if math.IsInf(top, 0) {
+		return 0, 0
+	}
+	return top, bottom
+}
@@ -0,0 +1,242 @@
+// Package kicad generates KiCad symbol and footprint libraries from the
+// EasyEDA CAD data of LCSC parts.
+//
+// # Usage
+//
+//	lib, err := kicad.NewLibrary("lib", "lcsc")
+//	part, err := lib.AddPart(ctx, client, "C20526")
+//
+//	// lib/lcsc.kicad_sym          symbol library, one symbol per part
+//	// lib/lcsc.pretty/*.kicad_mod footprints
+//	fmt.Println(part.Symbol, part.Footprint, part.ModelURL)
+//
+// Symbols carry the MPN, manufacturer, datasheet URL and LCSC part number
+// as fields. Footprints reference a STEP model under lib/lcsc.3dshapes;
+// the model itself is not downloaded.
+package kicad
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	lcsc "github.com/PatrickWalther/go-lcsc"
+)
+
+var (
+	// ErrNoSymbol is returned when a part has no EasyEDA symbol.
+	ErrNoSymbol = errors.New("kicad: part has no symbol")
+	// ErrNoFootprint is returned when a part has no EasyEDA footprint.
+	ErrNoFootprint = errors.New("kicad: part has no footprint")
+)
+
+// Library is a project-local KiCad library directory holding a symbol
+// library <Name>.kicad_sym, a footprint library <Name>.pretty and 3D models
+// in <Name>.3dshapes.
+type Library struct {
+	Dir  string
+	Name string
+	// ModelPath is the directory 3D model references point to. It defaults
+	// to the models directory under ${KIPRJMOD} for a relative Dir, assumed
+	// to be relative to the KiCad project, and to the absolute path otherwise.
+	ModelPath string
+}
+
+// Part describes the library items generated for one LCSC part.
+type Part struct {
+	ProductCode   string
+	Symbol        string // Symbol name in the symbol library; empty without a symbol
+	Footprint     string // "library:name" footprint reference; empty without a footprint
+	FootprintFile string // Path of the written .kicad_mod file
+	ModelPath     string // 3D model path referenced by the footprint; empty without a model
+	ModelURL      string // STEP download URL of the 3D model
+}
+
+// NewLibrary creates the library directories under dir.
+func NewLibrary(dir, name string) (*Library, error) {
+	name = safeName(name)
+	if name == "" {
+		return nil, fmt.Errorf("library name is required")
+	}
+
+	l := &Library{Dir: dir, Name: name}
+	if filepath.IsAbs(dir) {
+		l.ModelPath = filepath.ToSlash(l.ModelDir())
+	} else {
+		l.ModelPath = "${KIPRJMOD}/" + filepath.ToSlash(l.ModelDir())
+	}
+
+	if err := os.MkdirAll(l.FootprintDir(), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create library directory: %w", err)
+	}
+	return l, nil
+}
+
+// SymbolFile returns the path of the symbol library.
+func (l *Library) SymbolFile() string {
+	return filepath.Join(l.Dir, l.Name+".kicad_sym")
+}
+
+// FootprintDir returns the path of the footprint library.
+func (l *Library) FootprintDir() string {
+	return filepath.Join(l.Dir, l.Name+".pretty")
+}
+
+// ModelDir returns the directory 3D models are expected in.
+func (l *Library) ModelDir() string {
+	return filepath.Join(l.Dir, l.Name+".3dshapes")
+}
+
+// AddPart fetches a part's details and CAD data through client and adds it
+// to the library.
+func (l *Library) AddPart(ctx context.Context, client *lcsc.Client, productCode string) (*Part, error) {
+	product, err := client.GetProductDetails(ctx, productCode)
+	if err != nil {
+		return nil, err
+	}
+	cad, err := client.GetCADData(ctx, productCode)
+	if err != nil {
+		return nil, err
+	}
+	return l.Add(product, cad)
+}
+
+// Add writes a part's footprint and adds its symbol to the symbol library,
+// replacing an existing symbol of the same name. Parts with only a symbol
+// or only a footprint are added partially.
+func (l *Library) Add(product *lcsc.Product, cad *lcsc.CADData) (*Part, error) {
+	if cad == nil || (cad.Symbol == nil && cad.Footprint == nil) {
+		return nil, lcsc.ErrNoCADData
+	}
+
+	part := &Part{ProductCode: cad.ProductCode}
+	if product != nil && product.ProductCode != "" {
+		part.ProductCode = product.ProductCode
+	}
+
+	if cad.Footprint != nil {
+		name := FootprintName(cad)
+		part.Footprint = l.Name + ":" + name
+		part.FootprintFile = filepath.Join(l.FootprintDir(), name+".kicad_mod")
+		if m := cad.Model3D; m != nil {
+			part.ModelPath = strings.TrimSuffix(l.ModelPath, "/") + "/" + name + ".step"
+			part.ModelURL = m.STEPURL()
+		}
+
+		var b strings.Builder
+		if err := WriteFootprint(&b, product, cad, part.ModelPath); err != nil {
+			return nil, err
+		}
+		if err := writeFileAtomic(part.FootprintFile, []byte(b.String())); err != nil {
+			return nil, err
+		}
+	}
+
+	if cad.Symbol != nil {
+		part.Symbol = SymbolName(product, cad)
+		block, err := symbolBlock(product, cad, part.Footprint)
+		if err != nil {
+			return nil, err
+		}
+
+		existing, err := os.ReadFile(l.SymbolFile())
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read symbol library: %w", err)
+		}
+		merged, err := mergeSymbol(string(existing), part.Symbol, block)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeFileAtomic(l.SymbolFile(), []byte(merged)); err != nil {
+			return nil, err
+		}
+	}
+
+	return part, nil
+}
+
+// mergeSymbol adds a symbol block to the contents of a symbol library,
+// removing any top-level symbol with the same name.
+func mergeSymbol(library, name, block string) (string, error) {
+	if strings.TrimSpace(library) == "" {
+		return symbolLibrary(block), nil
+	}
+
+	prefix := "(symbol " + quote(name)
+	depth, inString, start := 0, false, -1
+	for i := 0; i < len(library); i++ {
+		c := library[i]
+		switch {
+		case inString:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '(':
+			if depth == 1 && strings.HasPrefix(library[i:], prefix) && isDelimiter(library, i+len(prefix)) {
+				start = i
+			}
+			depth++
+		case c == ')':
+			depth--
+			if depth == 1 && start >= 0 {
+				lineStart := strings.LastIndexByte(library[:start], '\n') + 1
+				if strings.TrimSpace(library[lineStart:start]) != "" {
+					lineStart = start
+				}
+				end := i + 1
+				if end < len(library) && library[end] == '\n' {
+					end++
+				}
+				library = library[:lineStart] + library[end:]
+				i = lineStart - 1
+				start = -1
+			}
+		}
+	}
+
+	end := strings.LastIndexByte(library, ')')
+	if end < 0 || !strings.HasPrefix(strings.TrimSpace(library), "(kicad_symbol_lib") {
+		return "", fmt.Errorf("invalid symbol library")
+	}
+	return library[:end] + block + library[end:], nil
+}
+
+// isDelimiter reports whether s[i] ends an S-expression token.
+func isDelimiter(s string, i int) bool {
+	if i >= len(s) {
+		return true
+	}
+	switch s[i] {
+	case ' ', '\t', '\n', '\r', '(', ')':
+		return true
+	}
+	return false
+}
+
+// writeFileAtomic writes data to path through a temporary file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	_ = tmp.Chmod(0o644)
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package kicad
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	lcsc "github.com/PatrickWalther/go-lcsc"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// testProduct is the LCSC product matching the EasyEDA fixture.
+var testProduct = lcsc.Product{
+	ProductCode:    "C20526",
+	ProductModel:   "MMBT3904",
+	BrandNameEn:    "CJ(Jiangsu Changjing Electronics Technology Co., Ltd.)",
+	ProductIntroEn: "40V 200mA 310mW NPN SOT-23 Bipolar Transistors - BJT",
+	PdfUrl:         "https://www.lcsc.com/datasheet/lcsc_datasheet_C20526.pdf",
+	EncapStandard:  "SOT-23",
+}
+
+// newTestClient returns a client backed by a server serving testProduct
+// and the EasyEDA fixture of the root package.
+func newTestClient(t *testing.T) *lcsc.Client {
+	t.Helper()
+	fixture, err := os.ReadFile("../testdata/easyeda_component.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/products/C20526/components":
+			_, _ = w.Write(fixture)
+		case "/product/detail":
+			result, _ := json.Marshal(testProduct)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 200, "result": json.RawMessage(result)})
+		default:
+			_, _ = w.Write([]byte(`{"success":true,"code":0,"result":null}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return lcsc.NewClient(
+		lcsc.WithBaseURL(server.URL),
+		lcsc.WithEasyEDABaseURL(server.URL),
+		lcsc.WithRetryConfig(lcsc.NoRetry()),
+		lcsc.WithRateLimit(1000),
+	)
+}
+
+// testCADData fetches the fixture CAD data.
+func testCADData(t *testing.T) *lcsc.CADData {
+	t.Helper()
+	cad, err := newTestClient(t).GetCADData(context.Background(), "C20526")
+	if err != nil {
+		t.Fatalf("GetCADData failed: %v", err)
+	}
+	return cad
+}
+
+// assertGolden compares got with testdata/name, rewriting it with -update.
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s (run with -update to accept):\n%s", path, got)
+	}
+}
+
+// TestWriteSymbol tests symbol generation against a golden file.
+func TestWriteSymbol(t *testing.T) {
+	var b strings.Builder
+	if err := WriteSymbol(&b, &testProduct, testCADData(t), "lcsc:SOT-23-3_L2.9-W1.3-P1.90-LS2.4-BR"); err != nil {
+		t.Fatalf("WriteSymbol failed: %v", err)
+	}
+	assertGolden(t, "MMBT3904.kicad_sym", b.String())
+}
+
+// TestWriteFootprint tests footprint generation against a golden file.
+func TestWriteFootprint(t *testing.T) {
+	var b strings.Builder
+	if err := WriteFootprint(&b, &testProduct, testCADData(t), "${KIPRJMOD}/lib/lcsc.3dshapes/SOT-23.step"); err != nil {
+		t.Fatalf("WriteFootprint failed: %v", err)
+	}
+	assertGolden(t, "SOT-23-3_L2.9-W1.3-P1.90-LS2.4-BR.kicad_mod", b.String())
+}
+
+// TestWriteMissingData tests errors for parts without CAD data.
+func TestWriteMissingData(t *testing.T) {
+	var b strings.Builder
+	if err := WriteSymbol(&b, &testProduct, &lcsc.CADData{}, ""); !errors.Is(err, ErrNoSymbol) {
+		t.Errorf("expected ErrNoSymbol, got %v", err)
+	}
+	if err := WriteFootprint(&b, &testProduct, &lcsc.CADData{}, ""); !errors.Is(err, ErrNoFootprint) {
+		t.Errorf("expected ErrNoFootprint, got %v", err)
+	}
+}
+
+// TestLibraryAddPart tests writing a part into a library directory.
+func TestLibraryAddPart(t *testing.T) {
+	dir := t.TempDir()
+	lib, err := NewLibrary(filepath.Join(dir, "lib"), "lcsc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	part, err := lib.AddPart(context.Background(), newTestClient(t), "C20526")
+	if err != nil {
+		t.Fatalf("AddPart failed: %v", err)
+	}
+
+	if part.Symbol != "MMBT3904" || part.Footprint != "lcsc:SOT-23-3_L2.9-W1.3-P1.90-LS2.4-BR" {
+		t.Errorf("unexpected part: %+v", part)
+	}
+	if !strings.HasSuffix(part.ModelPath, "/lib/lcsc.3dshapes/SOT-23-3_L2.9-W1.3-P1.90-LS2.4-BR.step") {
+		t.Errorf("unexpected model path: %s", part.ModelPath)
+	}
+	if !strings.Contains(part.ModelURL, "0f2b5a9c4c1e4fd8a3b6f9e2d7c8a1b0") {
+		t.Errorf("unexpected model URL: %s", part.ModelURL)
+	}
+
+	footprint, err := os.ReadFile(part.FootprintFile)
+	if err != nil {
+		t.Fatalf("footprint not written: %v", err)
+	}
+	if !strings.Contains(string(footprint), part.ModelPath) {
+		t.Error("expected footprint to reference the model path")
+	}
+
+	symbols, err := os.ReadFile(lib.SymbolFile())
+	if err != nil {
+		t.Fatalf("symbol library not written: %v", err)
+	}
+	for _, want := range []string{`(property "Footprint" "lcsc:SOT-23-3_L2.9-W1.3-P1.90-LS2.4-BR"`, `(property "LCSC" "C20526"`, `(property "MPN" "MMBT3904"`} {
+		if !strings.Contains(string(symbols), want) {
+			t.Errorf("expected symbol library to contain %s", want)
+		}
+	}
+}
+
+// TestLibraryRelativeModelPath tests ${KIPRJMOD} model paths for relative directories.
+func TestLibraryRelativeModelPath(t *testing.T) {
+	wd, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(wd) }()
+
+	lib, err := NewLibrary("lib", "my lib")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lib.Name != "my_lib" || lib.ModelPath != "${KIPRJMOD}/lib/my_lib.3dshapes" {
+		t.Errorf("unexpected library: %+v", lib)
+	}
+}
+
+// TestMergeSymbol tests adding and replacing symbols in a library file.
+func TestMergeSymbol(t *testing.T) {
+	block := func(name, value string) string {
+		return "  (symbol " + quote(name) + " (property \"Value\" " + quote(value) + ")\n    (symbol " + quote(name+"_0_1") + ")\n  )\n"
+	}
+
+	lib, err := mergeSymbol("", "A", block("A", "1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lib, _ = mergeSymbol(lib, "B", block("B", "(x)"))
+	lib, _ = mergeSymbol(lib, "AB", block("AB", "1"))
+	lib, err = mergeSymbol(lib, "A", block("A", "2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := symbolLibrary(block("B", "(x)"), block("AB", "1"), block("A", "2"))
+	if lib != expected {
+		t.Errorf("unexpected library:\n%s\nexpected:\n%s", lib, expected)
+	}
+
+	if _, err := mergeSymbol("garbage", "A", block("A", "1")); err == nil {
+		t.Error("expected error for invalid library")
+	}
+}
+
+// TestArcPoints tests conversion of SVG arcs to start, mid and end points.
+func TestArcPoints(t *testing.T) {
+	tests := []struct {
+		path string
+		mid  lcsc.Point
+	}{
+		{"M 388 295 A 5 5 0 0 1 388 305", lcsc.Point{X: 393, Y: 300}},
+		{"M 388 295 A 5 5 0 0 0 388 305", lcsc.Point{X: 383, Y: 300}},
+		{"M0,0 A10,10 0 0,1 10,10", lcsc.Point{X: 7.0711, Y: 2.9289}},
+		{"M 0 0 A 10 10 0 1 1 10 10", lcsc.Point{X: 17.0711, Y: -7.0711}},
+	}
+
+	for _, tt := range tests {
+		_, mid, _, ok := arcPoints(tt.path)
+		if !ok {
+			t.Errorf("arcPoints(%q) failed", tt.path)
+			continue
+		}
+		if math.Abs(mid.X-tt.mid.X) > 1e-3 || math.Abs(mid.Y-tt.mid.Y) > 1e-3 {
+			t.Errorf("arcPoints(%q) mid = %+v, expected %+v", tt.path, mid, tt.mid)
+		}
+	}
+
+	if _, _, _, ok := arcPoints("M 0 0 L 1 1"); ok {
+		t.Error("expected failure for non-arc path")
+	}
+}
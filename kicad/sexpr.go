@@ -0,0 +1,91 @@
+package kicad
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	lcsc "github.com/PatrickWalther/go-lcsc"
+)
+
+// quote returns s as a KiCad string literal.
+func quote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", "")
+	return `"` + r.Replace(s) + `"`
+}
+
+// num formats a coordinate or size in millimetres with at most four decimals.
+func num(v float64) string {
+	v = math.Round(v*10000) / 10000
+	if v == 0 {
+		v = 0 // Avoid "-0"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// mm converts EasyEDA units to millimetres.
+func mm(v float64) float64 {
+	return v * lcsc.EasyEDAUnitMM
+}
+
+// arcPoints returns the start, midpoint and end of the first circular arc
+// in an SVG path like "M x1 y1 A r r 0 large sweep x2 y2". Elliptical arcs
+// are approximated with their X radius.
+func arcPoints(path string) (start, mid, end lcsc.Point, ok bool) {
+	f := strings.Fields(strings.NewReplacer(",", " ", "M", " M ", "A", " A ").Replace(path))
+	if len(f) < 11 || f[0] != "M" || f[3] != "A" {
+		return start, mid, end, false
+	}
+
+	// values: x1 y1 rx ry rotation large sweep x2 y2
+	values := make([]float64, 0, 9)
+	for _, s := range append(append([]string(nil), f[1:3]...), f[4:11]...) {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return start, mid, end, false
+		}
+		values = append(values, v)
+	}
+	start = lcsc.Point{X: values[0], Y: values[1]}
+	r, large, sweep := values[2], values[5] != 0, values[6] != 0
+	end = lcsc.Point{X: values[7], Y: values[8]}
+
+	// Endpoint to center parameterization (SVG 1.1, appendix F.6.5).
+	hx, hy := (start.X-end.X)/2, (start.Y-end.Y)/2
+	d := math.Hypot(hx, hy)
+	if d == 0 {
+		return start, mid, end, false
+	}
+	if r < d {
+		r = d
+	}
+	k := math.Sqrt(math.Max(r*r-d*d, 0)) / d
+	if large == sweep {
+		k = -k
+	}
+	cx := k*hy + (start.X+end.X)/2
+	cy := -k*hx + (start.Y+end.Y)/2
+
+	a1 := math.Atan2(start.Y-cy, start.X-cx)
+	delta := math.Atan2(end.Y-cy, end.X-cx) - a1
+	if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	} else if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	}
+	mid = lcsc.Point{X: cx + r*math.Cos(a1+delta/2), Y: cy + r*math.Sin(a1+delta/2)}
+	return start, mid, end, true
+}
+
+// safeName replaces characters KiCad does not allow in library item and
+// file names.
+func safeName(s string) string {
+	s = strings.TrimSpace(s)
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '"', '*', '?', '<', '>', '|', ' ', '\t':
+			return '_'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,216 @@
+package kicad
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	lcsc "github.com/PatrickWalther/go-lcsc"
+)
+
+// symbolLibVersion is the .kicad_sym file format version written (KiCad 6).
+const symbolLibVersion = "20211014"
+
+// generator is the generator name written into KiCad files.
+const generator = "go-lcsc"
+
+// pinTypes maps EasyEDA pin types to KiCad electrical types. EasyEDA marks
+// most passive pins as unspecified.
+var pinTypes = map[lcsc.PinType]string{
+	lcsc.PinUnspecified:   "passive",
+	lcsc.PinInput:         "input",
+	lcsc.PinOutput:        "output",
+	lcsc.PinBidirectional: "bidirectional",
+	lcsc.PinPower:         "power_in",
+}
+
+// WriteSymbol writes a .kicad_sym library containing the symbol of one
+// part. footprint is the "library:name" footprint reference stored in the
+// symbol's Footprint field; it may be empty.
+func WriteSymbol(w io.Writer, product *lcsc.Product, cad *lcsc.CADData, footprint string) error {
+	block, err := symbolBlock(product, cad, footprint)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, symbolLibrary(block))
+	return err
+}
+
+// SymbolName returns the name used for a part's symbol: the MPN, falling
+// back to the EasyEDA title and the LCSC code.
+func SymbolName(product *lcsc.Product, cad *lcsc.CADData) string {
+	if product == nil {
+		product = &lcsc.Product{}
+	}
+	code := product.ProductCode
+	if cad != nil && code == "" {
+		code = cad.ProductCode
+	}
+	for _, name := range []string{product.ProductModel, symbolTitle(cad), code} {
+		if name = safeName(name); name != "" {
+			return name
+		}
+	}
+	return "symbol"
+}
+
+// symbolLibrary wraps symbol blocks into a library file.
+func symbolLibrary(blocks ...string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "(kicad_symbol_lib (version %s) (generator %s)\n", symbolLibVersion, generator)
+	for _, block := range blocks {
+		b.WriteString(block)
+	}
+	b.WriteString(")\n")
+	return b.String()
+}
+
+// symbolBlock returns the (symbol ...) expression for a part, indented for
+// inclusion in a library file.
+func symbolBlock(product *lcsc.Product, cad *lcsc.CADData, footprint string) (string, error) {
+	if cad == nil || cad.Symbol == nil {
+		return "", ErrNoSymbol
+	}
+	if product == nil {
+		product = &lcsc.Product{}
+	}
+	s := cad.Symbol
+	name := SymbolName(product, cad)
+
+	pt := func(p lcsc.Point) string {
+		return num(mm(p.X-s.Origin.X)) + " " + num(-mm(p.Y-s.Origin.Y))
+	}
+	stroke := func(width float64) string {
+		return fmt.Sprintf("(stroke (width %s) (type default) (color 0 0 0 0))", num(mm(width)))
+	}
+	fill := func(filled bool) string {
+		if filled {
+			return "(fill (type background))"
+		}
+		return "(fill (type none))"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "  (symbol %s (in_bom yes) (on_board yes)\n", quote(name))
+
+	top, bottom := symbolBounds(s)
+	reference := strings.TrimRight(s.Prefix, "?")
+	if reference == "" {
+		reference = "U"
+	}
+	properties := []struct {
+		key, value string
+		y          float64
+		hide       bool
+	}{
+		{"Reference", reference, top + 2.54, false},
+		{"Value", name, bottom - 2.54, false},
+		{"Footprint", footprint, bottom - 5.08, true},
+		{"Datasheet", product.PdfUrl, bottom - 7.62, true},
+		{"ki_description", product.ProductIntroEn, 0, true},
+		{"Manufacturer", product.BrandNameEn, 0, true},
+		{"MPN", product.ProductModel, 0, true},
+		{"LCSC", firstNonEmpty(product.ProductCode, cad.ProductCode), 0, true},
+	}
+	for id, p := range properties {
+		hide := ""
+		if p.hide {
+			hide = " hide"
+		}
+		fmt.Fprintf(&b, "    (property %s %s (id %d) (at 0 %s 0)\n      (effects (font (size 1.27 1.27))%s)\n    )\n",
+			quote(p.key), quote(p.value), id, num(p.y), hide)
+	}
+
+	fmt.Fprintf(&b, "    (symbol %s\n", quote(name+"_0_1"))
+	for _, r := range s.Rectangles {
+		fmt.Fprintf(&b, "      (rectangle (start %s) (end %s) %s %s)\n",
+			pt(lcsc.Point{X: r.X, Y: r.Y}), pt(lcsc.Point{X: r.X + r.Width, Y: r.Y + r.Height}), stroke(r.StrokeWidth), fill(r.Filled))
+	}
+	for _, pl := range s.Polylines {
+		points := pl.Points
+		if pl.Closed && len(points) > 0 {
+			points = append(append([]lcsc.Point(nil), points...), points[0])
+		}
+		xy := make([]string, len(points))
+		for i, p := range points {
+			xy[i] = "(xy " + pt(p) + ")"
+		}
+		fmt.Fprintf(&b, "      (polyline (pts %s) %s %s)\n", strings.Join(xy, " "), stroke(pl.StrokeWidth), fill(pl.Filled))
+	}
+	for _, c := range s.Circles {
+		fmt.Fprintf(&b, "      (circle (center %s) (radius %s) %s %s)\n", pt(c.Center), num(mm(c.Radius)), stroke(c.StrokeWidth), fill(c.Filled))
+	}
+	for _, a := range s.Arcs {
+		start, mid, end, ok := arcPoints(a.Path)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, "      (arc (start %s) (mid %s) (end %s) %s %s)\n", pt(start), pt(mid), pt(end), stroke(a.StrokeWidth), fill(false))
+	}
+	b.WriteString("    )\n")
+
+	fmt.Fprintf(&b, "    (symbol %s\n", quote(name+"_1_1"))
+	for _, pin := range s.Pins {
+		pinType, ok := pinTypes[pin.Type]
+		if !ok {
+			pinType = "unspecified"
+		}
+		pinName := pin.Name
+		if pinName == "" {
+			pinName = "~"
+		}
+		fmt.Fprintf(&b, "      (pin %s line (at %s %s) (length %s)\n        (name %s (effects (font (size 1.27 1.27))))\n        (number %s (effects (font (size 1.27 1.27))))\n      )\n",
+			pinType, pt(pin.Position), num(math.Round(pin.Rotation)), num(mm(pin.Length)), quote(pinName), quote(pin.Number))
+	}
+	b.WriteString("    )\n  )\n")
+	return b.String(), nil
+}
+
+// symbolBounds returns the top and bottom of a symbol's body and pins in
+// KiCad coordinates (millimetres, Y up).
+func symbolBounds(s *lcsc.Symbol) (top, bottom float64) {
+	top, bottom = math.Inf(-1), math.Inf(1)
+	add := func(y float64) {
+		y = -mm(y - s.Origin.Y)
+		top, bottom = math.Max(top, y), math.Min(bottom, y)
+	}
+	for _, r := range s.Rectangles {
+		add(r.Y)
+		add(r.Y + r.Height)
+	}
+	for _, pl := range s.Polylines {
+		for _, p := range pl.Points {
+			add(p.Y)
+		}
+	}
+	for _, c := range s.Circles {
+		add(c.Center.Y - c.Radius)
+		add(c.Center.Y + c.Radius)
+	}
+	for _, pin := range s.Pins {
+		add(pin.Position.Y)
+	}
+	if math.IsInf(top, 0) {
+		return 0, 0
+	}
+	return top, bottom
+}
+
+// symbolTitle returns the EasyEDA symbol title, if any.
+func symbolTitle(cad *lcsc.CADData) string {
+	if cad == nil || cad.Symbol == nil {
+		return ""
+	}
+	return cad.Symbol.Title
+}
+
+// firstNonEmpty returns the first non-empty string.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package lcsc
+
+import "strings"
+
+// Language selects the language of product text.
+type Language string
+
+const (
+	LanguageEnglish Language = "en"
+	LanguageChinese Language = "zh"
+)
+
+// languageCookie is the cookie LCSC reads the site language from.
+const languageCookie = "lang"
+
+// WithLanguage sets the language of product text. The language is sent as
+// a cookie and Accept-Language header, and language-specific fields are
+// decoded into Product alongside the English ones; use Localize for a
+// language-neutral view. The default is English.
+func WithLanguage(lang Language) ClientOption {
+	return func(c *Client) {
+		c.language = Language(strings.ToLower(strings.TrimSpace(string(lang))))
+	}
+}
+
+// Language returns the language used for product text.
+func (c *Client) Language() Language {
+	if c.language == "" {
+		return LanguageEnglish
+	}
+	return c.language
+}
+
+// LocalizedParameter is a product parameter in one language.
+type LocalizedParameter struct {
+	Name  string
+	Value string
+}
+
+// LocalizedProduct is the text of a product in one language, with English
+// text for fields the language has no translation for.
+type LocalizedProduct struct {
+	ProductCode string
+	Language    Language
+	Name        string
+	Description string
+	Brand       string
+	Category    string
+	Parameters  []LocalizedParameter
+}
+
+// Localize returns the text of p in the client's language.
+func (c *Client) Localize(p *Product) *LocalizedProduct {
+	return p.Localized(c.Language())
+}
+
+// Localized returns the text of p in lang, falling back to English for
+// missing translations and to the MPN for a missing name.
+func (p *Product) Localized(lang Language) *LocalizedProduct {
+	l := &LocalizedProduct{
+		ProductCode: p.ProductCode,
+		Language:    lang,
+		Name:        firstText(p.ProductNameEn, p.ProductModel),
+		Description: p.ProductIntroEn,
+		Brand:       p.BrandNameEn,
+		Category:    p.CatalogName,
+		Parameters:  make([]LocalizedParameter, 0, len(p.ParamVOList)),
+	}
+	if lang == LanguageChinese {
+		l.Name = firstText(p.ProductNameCn, l.Name)
+		l.Description = firstText(p.ProductIntroCn, l.Description)
+		l.Brand = firstText(p.BrandNameCn, l.Brand)
+		l.Category = firstText(p.CatalogNameCn, l.Category)
+	}
+
+	for _, param := range p.ParamVOList {
+		lp := LocalizedParameter{Name: param.ParamNameEn, Value: param.ParamValueEn}
+		if lang == LanguageChinese {
+			lp.Name = firstText(param.ParamNameCn, lp.Name)
+			lp.Value = firstText(param.ParamValueCn, lp.Value)
+		}
+		l.Parameters = append(l.Parameters, lp)
+	}
+	return l
+}
+
+// firstText returns the first value that is not blank.
+func firstText(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package lcsc
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// chineseProduct has English and Chinese text, with some translations missing.
+var chineseProduct = Product{
+	ProductCode:    "C8734",
+	ProductModel:   "STM32F103C8T6",
+	ProductNameCn:  "单片机",
+	BrandNameEn:    "STMicroelectronics",
+	BrandNameCn:    "意法半导体",
+	ProductIntroEn: "ARM Cortex-M3 MCU",
+	CatalogName:    "Microcontrollers",
+	ParamVOList: []Parameter{
+		{ParamNameEn: "Core", ParamValueEn: "ARM Cortex-M3", ParamNameCn: "内核"},
+		{ParamNameEn: "Flash", ParamValueEn: "64KB", ParamNameCn: "闪存", ParamValueCn: "64KB"},
+	},
+}
+
+// TestLocalized tests the language-neutral product view with English fallback.
+func TestLocalized(t *testing.T) {
+	zh := chineseProduct.Localized(LanguageChinese)
+	if zh.Name != "单片机" || zh.Brand != "意法半导体" {
+		t.Errorf("expected Chinese name and brand, got %q and %q", zh.Name, zh.Brand)
+	}
+	if zh.Description != "ARM Cortex-M3 MCU" || zh.Category != "Microcontrollers" {
+		t.Errorf("expected English fallback, got %q and %q", zh.Description, zh.Category)
+	}
+	if zh.Parameters[0] != (LocalizedParameter{Name: "内核", Value: "ARM Cortex-M3"}) {
+		t.Errorf("unexpected parameter: %+v", zh.Parameters[0])
+	}
+
+	en := chineseProduct.Localized(LanguageEnglish)
+	if en.Name != "STM32F103C8T6" || en.Brand != "STMicroelectronics" || en.Parameters[1].Name != "Flash" {
+		t.Errorf("unexpected English view: %+v", en)
+	}
+}
+
+// TestWithLanguage tests the language cookie, header and cache separation.
+func TestWithLanguage(t *testing.T) {
+	var cookie, acceptLanguage string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		cookie = r.Header.Get("Cookie")
+		acceptLanguage = r.Header.Get("Accept-Language")
+		writeAPIResult(t, w, chineseProduct)
+	}
+
+	cache := NewMemoryCache(time.Minute)
+	defer cache.Close()
+	client := newTestClient(t, handler, WithLanguage("ZH"), WithCache(cache))
+	if client.Language() != LanguageChinese {
+		t.Errorf("expected language zh, got %s", client.Language())
+	}
+
+	product, err := client.GetProductDetails(context.Background(), "C8734")
+	if err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+	if cookie != "currencyCode=USD; lang=zh" || acceptLanguage != "zh" {
+		t.Errorf("unexpected Cookie %q and Accept-Language %q", cookie, acceptLanguage)
+	}
+	if got := client.Localize(product); got.Name != "单片机" {
+		t.Errorf("unexpected localized name: %q", got.Name)
+	}
+
+	english := newTestClient(t, handler, WithCache(cache))
+	if english.Language() != LanguageEnglish {
+		t.Errorf("expected default language en, got %s", english.Language())
+	}
+	if english.getCacheKeyProduct("C8734") == client.getCacheKeyProduct("C8734") {
+		t.Error("expected cache keys to differ by language")
+	}
+	if _, err := english.GetProductDetails(context.Background(), "C8734"); err != nil {
+		t.Fatal(err)
+	}
+	if cookie != "currencyCode=USD" || acceptLanguage != "" {
+		t.Errorf("unexpected Cookie %q and Accept-Language %q for English", cookie, acceptLanguage)
+	}
+}
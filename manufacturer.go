@@ -0,0 +1,177 @@
+package lcsc
+
+import (
+	"strings"
+	"unicode"
+)
+
+// manufacturerAliases maps normalized manufacturer spellings to a canonical
+// name. Keys are produced by manufacturerKey.
+var manufacturerAliases = map[string]string{
+	"ti":                             "Texas Instruments",
+	"texasinstruments":               "Texas Instruments",
+	"st":                             "STMicroelectronics",
+	"stm":                            "STMicroelectronics",
+	"stmicro":                        "STMicroelectronics",
+	"stmicroelectronics":             "STMicroelectronics",
+	"adi":                            "Analog Devices",
+	"analogdevices":                  "Analog Devices",
+	"lineartechnology":               "Analog Devices",
+	"maxim":                          "Analog Devices",
+	"maximintegrated":                "Analog Devices",
+	"nxp":                            "NXP Semiconductors",
+	"nxpsemiconductors":              "NXP Semiconductors",
+	"freescale":                      "NXP Semiconductors",
+	"onsemi":                         "onsemi",
+	"on":                             "onsemi",
+	"onsemiconductor":                "onsemi",
+	"fairchild":                      "onsemi",
+	"fairchildsemiconductor":         "onsemi",
+	"microchip":                      "Microchip Technology",
+	"microchiptechnology":            "Microchip Technology",
+	"atmel":                          "Microchip Technology",
+	"infineon":                       "Infineon Technologies",
+	"infineontechnologies":           "Infineon Technologies",
+	"cypress":                        "Infineon Technologies",
+	"cypresssemiconductor":           "Infineon Technologies",
+	"internationalrectifier":         "Infineon Technologies",
+	"irf":                            "Infineon Technologies",
+	"renesas":                        "Renesas Electronics",
+	"renesaselectronics":             "Renesas Electronics",
+	"intersil":                       "Renesas Electronics",
+	"idt":                            "Renesas Electronics",
+	"rohm":                           "ROHM Semiconductor",
+	"rohmsemiconductor":              "ROHM Semiconductor",
+	"vishay":                         "Vishay",
+	"vishayintertechnology":          "Vishay",
+	"vishaysiliconix":                "Vishay",
+	"diodes":                         "Diodes Incorporated",
+	"diodesincorporated":             "Diodes Incorporated",
+	"nexperia":                       "Nexperia",
+	"toshiba":                        "Toshiba",
+	"murata":                         "Murata",
+	"murataelectronics":              "Murata",
+	"muratamanufacturing":            "Murata",
+	"samsung":                        "Samsung Electro-Mechanics",
+	"samsungelectromechanics":        "Samsung Electro-Mechanics",
+	"semco":                          "Samsung Electro-Mechanics",
+	"tdk":                            "TDK",
+	"yageo":                          "YAGEO",
+	"uniroyal":                       "UNI-ROYAL",
+	"uniroyalelec":                   "UNI-ROYAL",
+	"uniroyalelectronics":            "UNI-ROYAL",
+	"fh":                             "Guangdong Fenghua Advanced Tech",
+	"fenghua":                        "Guangdong Fenghua Advanced Tech",
+	"guangdongfenghuaadvancedtech":   "Guangdong Fenghua Advanced Tech",
+	"espressif":                      "Espressif Systems",
+	"espressifsystems":               "Espressif Systems",
+	"wch":                            "WCH",
+	"nanjingqinhengmicroelectronics": "WCH",
+	"gigadevice":                     "GigaDevice",
+	"gigadevicesemiconductor":        "GigaDevice",
+	"bosch":                          "Bosch Sensortec",
+	"boschsensortec":                 "Bosch Sensortec",
+	"te":                             "TE Connectivity",
+	"teconnectivity":                 "TE Connectivity",
+	"molex":                          "Molex",
+	"jst":                            "JST",
+	"jstsalescenter":                 "JST",
+	"kemet":                          "KEMET",
+	"avx":                            "KYOCERA AVX",
+	"kyoceraavx":                     "KYOCERA AVX",
+	"panasonic":                      "Panasonic",
+	"littelfuse":                     "Littelfuse",
+	"bourns":                         "Bourns",
+}
+
+// manufacturerSuffixes are corporate suffixes ignored when comparing names.
+var manufacturerSuffixes = []string{
+	"incorporated", "inc", "corporation", "corp", "company", "co", "limited", "ltd", "llc",
+	"gmbh", "ag", "sa", "bv", "plc",
+}
+
+// CanonicalManufacturer returns the canonical spelling of a manufacturer
+// name. LCSC often combines an abbreviation with the full name, as in
+// "TI(Texas Instruments)"; each part is tried in turn. Names not present in
+// the alias table are returned trimmed but otherwise unchanged.
+func CanonicalManufacturer(name string) string {
+	name = strings.TrimSpace(name)
+	for _, part := range manufacturerParts(name) {
+		if canonical, ok := manufacturerAliases[manufacturerKey(part)]; ok {
+			return canonical
+		}
+	}
+	return name
+}
+
+// ManufacturersMatch reports whether two manufacturer names refer to the same
+// company after alias normalization. Empty names never match.
+func ManufacturersMatch(a, b string) bool {
+	if strings.TrimSpace(a) == "" || strings.TrimSpace(b) == "" {
+		return false
+	}
+	keysA := manufacturerKeys(a)
+	for _, kb := range manufacturerKeys(b) {
+		for _, ka := range keysA {
+			if ka == kb {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// manufacturerKeys returns the comparison keys for a manufacturer name: the
+// canonical name of each part plus the raw normalized parts.
+func manufacturerKeys(name string) []string {
+	var keys []string
+	for _, part := range manufacturerParts(name) {
+		key := manufacturerKey(part)
+		if key == "" {
+			continue
+		}
+		if canonical, ok := manufacturerAliases[key]; ok {
+			keys = append(keys, manufacturerKey(canonical))
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// manufacturerParts splits names like "TI(Texas Instruments)" into the full
+// string followed by its outer and parenthesized parts.
+func manufacturerParts(name string) []string {
+	parts := []string{name}
+	open := strings.IndexAny(name, "(（")
+	if open <= 0 {
+		return parts
+	}
+	parts = append(parts, name[:open])
+	inner := strings.TrimRight(name[open:], ")）")
+	inner = strings.TrimLeft(inner, "(（")
+	if inner != "" {
+		parts = append(parts, inner)
+	}
+	return parts
+}
+
+// manufacturerKey lowercases a name, drops corporate suffixes and removes
+// everything except letters and digits.
+func manufacturerKey(name string) string {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for len(fields) > 1 && isManufacturerSuffix(fields[len(fields)-1]) {
+		fields = fields[:len(fields)-1]
+	}
+	return strings.Join(fields, "")
+}
+
+func isManufacturerSuffix(s string) bool {
+	for _, suffix := range manufacturerSuffixes {
+		if s == suffix {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package lcsc
+
+import "testing"
+
+// TestCanonicalManufacturer tests alias resolution for manufacturer names.
+func TestCanonicalManufacturer(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"TI", "Texas Instruments"},
+		{"Texas Instruments", "Texas Instruments"},
+		{"TI(Texas Instruments)", "Texas Instruments"},
+		{"texas instruments inc.", "Texas Instruments"},
+		{"ST(STMicroelectronics)", "STMicroelectronics"},
+		{"ON Semiconductor", "onsemi"},
+		{"onsemi(ON Semiconductor)", "onsemi"},
+		{"Maxim Integrated", "Analog Devices"},
+		{"  Unknown Vendor  ", "Unknown Vendor"},
+	}
+
+	for _, test := range tests {
+		if got := CanonicalManufacturer(test.name); got != test.expected {
+			t.Errorf("CanonicalManufacturer(%q) = %q, expected %q", test.name, got, test.expected)
+		}
+	}
+}
+
+// TestManufacturersMatch tests manufacturer comparison across spellings.
+func TestManufacturersMatch(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"TI", "TI(Texas Instruments)", true},
+		{"Texas Instruments", "TI", true},
+		{"STMicroelectronics", "ST(STMicroelectronics)", true},
+		{"Acme Corp", "ACME", true},
+		{"TI", "STMicroelectronics", false},
+		{"", "TI", false},
+		{"TI", "", false},
+	}
+
+	for _, test := range tests {
+		if got := ManufacturersMatch(test.a, test.b); got != test.expected {
+			t.Errorf("ManufacturersMatch(%q, %q) = %v, expected %v", test.a, test.b, got, test.expected)
+		}
+	}
+}
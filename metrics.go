@@ -0,0 +1,108 @@
+package lcsc
+
+import (
+	"context"
+	"expvar"
+	"strconv"
+	"sync"
+)
+
+// ExpvarObserver collects request metrics in an expvar.Map, served as JSON
+// at /debug/vars by the expvar package. The map holds:
+//
+//	requests, errors, attempts, retries   request and attempt counts
+//	cache_hits, cache_misses              cache lookups
+//	bytes                                 response bytes received
+//	duration_seconds                      total request time, including retries
+//	rate_limit_wait_seconds               time spent waiting for the rate limiter
+//	status                                final HTTP status code counts
+//	endpoints                             requests, errors, bytes and
+//	                                      duration_seconds per endpoint
+type ExpvarObserver struct {
+	m *expvar.Map
+}
+
+// expvarMu serializes creation of metric maps, which observers sharing a
+// published map may attempt concurrently.
+var expvarMu sync.Mutex
+
+// NewExpvarObserver returns an observer publishing its metrics under name,
+// reusing the map if an observer with the same name was created before.
+// An empty name keeps the metrics unpublished. Like expvar.Publish, it
+// panics if name is taken by a variable that is not a map.
+func NewExpvarObserver(name string) *ExpvarObserver {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+
+	var m *expvar.Map
+	switch v := expvar.Get(name).(type) {
+	case *expvar.Map:
+		m = v
+	default:
+		if name == "" {
+			m = new(expvar.Map).Init()
+		} else {
+			m = expvar.NewMap(name)
+		}
+	}
+	childMap(m, "status")
+	childMap(m, "endpoints")
+	return &ExpvarObserver{m: m}
+}
+
+// Map returns the map holding the metrics.
+func (o *ExpvarObserver) Map() *expvar.Map {
+	return o.m
+}
+
+// Observe records event.
+func (o *ExpvarObserver) Observe(_ context.Context, event RequestEvent) {
+	switch event.Type {
+	case EventAttempt:
+		o.m.Add("attempts", 1)
+	case EventRetry:
+		o.m.Add("retries", 1)
+	case EventCacheHit:
+		o.m.Add("cache_hits", 1)
+	case EventCacheMiss:
+		o.m.Add("cache_misses", 1)
+	case EventRateLimitWait:
+		o.m.AddFloat("rate_limit_wait_seconds", event.Duration.Seconds())
+	case EventRequestDone:
+		endpoint := o.endpoint(event.Endpoint)
+		for _, m := range []*expvar.Map{o.m, endpoint} {
+			m.Add("requests", 1)
+			m.Add("bytes", event.Bytes)
+			m.AddFloat("duration_seconds", event.Duration.Seconds())
+			if event.Err != nil {
+				m.Add("errors", 1)
+			}
+		}
+		if event.Status > 0 {
+			o.m.Get("status").(*expvar.Map).Add(strconv.Itoa(event.Status), 1)
+		}
+	}
+}
+
+// endpoint returns the metrics map of an endpoint, creating it if needed.
+func (o *ExpvarObserver) endpoint(name string) *expvar.Map {
+	endpoints := o.m.Get("endpoints").(*expvar.Map)
+	if m, ok := endpoints.Get(name).(*expvar.Map); ok {
+		return m
+	}
+
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+	return childMap(endpoints, name)
+}
+
+// childMap returns the map stored under key in parent, creating it if
+// needed. The caller must hold expvarMu.
+func childMap(parent *expvar.Map, key string) *expvar.Map {
+	if m, ok := parent.Get(key).(*expvar.Map); ok {
+		return m
+	}
+	m := new(expvar.Map).Init()
+	parent.Set(key, m)
+	return m
+}
@@ -0,0 +1,67 @@
+package lcsc
+
+import "net/http"
+
+// Middleware wraps an http.RoundTripper to intercept requests and
+// responses, for example to add headers, log, sign requests or rewrite
+// responses.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to the http.RoundTripper interface.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f.
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// WithMiddleware adds middleware around the HTTP transport of all requests
+// the client makes, including EasyEDA and download requests. Middleware
+// runs in the order given, across calls: the first sees each request first
+// and its response last. Requests reaching middleware already carry the
+// client's Accept, User-Agent and Cookie headers, which middleware may
+// change. Each retry attempt passes through the chain again.
+//
+// The transport of a client given with WithHTTPClient is wrapped in a copy;
+// the original http.Client is not modified.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *Client) {
+		for _, m := range middleware {
+			if m != nil {
+				c.middleware = append(c.middleware, m)
+			}
+		}
+	}
+}
+
+// SetHeader returns middleware that sets a request header, replacing any
+// value set by the client.
+func SetHeader(key, value string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.Header.Set(key, value)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// applyMiddleware replaces the client's HTTP client with a copy whose
+// transport is wrapped in the configured middleware.
+func (c *Client) applyMiddleware() {
+	if len(c.middleware) == 0 {
+		return
+	}
+
+	transport := c.httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		transport = c.middleware[i](transport)
+	}
+
+	httpClient := *c.httpClient
+	httpClient.Transport = transport
+	c.httpClient = &httpClient
+}
@@ -0,0 +1,162 @@
+package lcsc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestMiddlewareOrder tests that middleware runs in the order given.
+func TestMiddlewareOrder(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				calls = append(calls, name+" request")
+				mu.Unlock()
+				resp, err := next.RoundTrip(req)
+				mu.Lock()
+				calls = append(calls, name+" response")
+				mu.Unlock()
+				return resp, err
+			})
+		}
+	}
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeAPIResult(t, w, Product{ProductCode: "C1"})
+	}, WithMiddleware(trace("a"), trace("b")), WithMiddleware(trace("c")))
+
+	if _, err := client.GetProductDetails(context.Background(), "C1"); err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+
+	expected := []string{"a request", "b request", "c request", "c response", "b response", "a response"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("unexpected call order: %v", calls)
+	}
+}
+
+// TestMiddlewareHeaders tests header injection and overriding client headers.
+func TestMiddlewareHeaders(t *testing.T) {
+	var got http.Header
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		writeAPIResult(t, w, Product{ProductCode: "C1"})
+	}, WithMiddleware(SetHeader("X-Api-Key", "secret"), SetHeader("User-Agent", "my-app/2.0")))
+
+	if _, err := client.GetProductDetails(context.Background(), "C1"); err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+
+	if got.Get("X-Api-Key") != "secret" || got.Get("User-Agent") != "my-app/2.0" {
+		t.Errorf("unexpected headers: %v", got)
+	}
+	if got.Get("Cookie") != "currencyCode=USD" {
+		t.Errorf("expected client headers to be kept, got %v", got)
+	}
+}
+
+// TestMiddlewareResponseRewrite tests middleware rewriting responses.
+func TestMiddlewareResponseRewrite(t *testing.T) {
+	rewrite := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				return nil, err
+			}
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(bytes.ReplaceAll(body, []byte("C1"), []byte("C2"))))
+			return resp, nil
+		})
+	}
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeAPIResult(t, w, Product{ProductCode: "C1"})
+	}, WithMiddleware(rewrite))
+
+	product, err := client.GetProductDetails(context.Background(), "C1")
+	if err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+	if product.ProductCode != "C2" {
+		t.Errorf("expected rewritten product code, got %s", product.ProductCode)
+	}
+}
+
+// TestMiddlewareRetries tests that each retry attempt passes through middleware.
+func TestMiddlewareRetries(t *testing.T) {
+	var attempts int
+	count := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return next.RoundTrip(req)
+		})
+	}
+
+	retry := RetryConfig{MaxRetries: 2, InitialBackoff: 1, MaxBackoff: 1, Multiplier: 1}
+	client := newTestClient(t, flakyDetailHandler(t, 2), WithRetryConfig(retry), WithMiddleware(count))
+	if _, err := client.GetProductDetails(context.Background(), "C1"); err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts through middleware, got %d", attempts)
+	}
+}
+
+// TestMiddlewareCustomHTTPClient tests that a custom HTTP client is not modified.
+func TestMiddlewareCustomHTTPClient(t *testing.T) {
+	httpClient := &http.Client{}
+	var called bool
+	mark := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			called = true
+			return next.RoundTrip(req)
+		})
+	}
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeAPIResult(t, w, Product{ProductCode: "C1"})
+	}, WithMiddleware(mark), WithHTTPClient(httpClient))
+
+	if _, err := client.GetProductDetails(context.Background(), "C1"); err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+	if !called {
+		t.Error("expected middleware to wrap the custom client's transport")
+	}
+	if httpClient.Transport != nil {
+		t.Error("expected custom http.Client to be left unchanged")
+	}
+}
+
+// TestMiddlewareDownload tests that downloads pass through middleware.
+func TestMiddlewareDownload(t *testing.T) {
+	fs, server := newFileServer(t)
+	fs.add("/ds.pdf", "application/pdf", testPDF)
+
+	var paths []string
+	record := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			paths = append(paths, req.URL.Path)
+			return next.RoundTrip(req)
+		})
+	}
+	client := NewClient(WithRetryConfig(NoRetry()), WithRateLimit(1000), WithMiddleware(record))
+
+	var buf strings.Builder
+	if _, err := client.Download(context.Background(), server.URL+"/ds.pdf", &buf, DownloadOptions{}); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if !reflect.DeepEqual(paths, []string{"/ds.pdf"}) {
+		t.Errorf("unexpected paths: %v", paths)
+	}
+}
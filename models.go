@@ -3,42 +3,19 @@ package lcsc
 import (
 	"encoding/json"
 	"fmt"
-	"strconv"
 )
 
 // Parameter represents a product specification/parameter.
 type Parameter struct {
 	ParamNameEn  string `json:"paramNameEn"`
 	ParamValueEn string `json:"paramValueEn"`
-}
-
-// FlexFloat64 handles JSON values that may be either a number or a string.
-type FlexFloat64 float64
-
-// UnmarshalJSON implements json.Unmarshaler for FlexFloat64.
-func (f *FlexFloat64) UnmarshalJSON(data []byte) error {
-	// Try as number first
-	var num float64
-	if err := json.Unmarshal(data, &num); err == nil {
-		*f = FlexFloat64(num)
-		return nil
-	}
-	// Try as string
-	var str string
-	if err := json.Unmarshal(data, &str); err == nil {
-		num, err := strconv.ParseFloat(str, 64)
-		if err != nil {
-			return fmt.Errorf("cannot parse %q as float64: %w", str, err)
-		}
-		*f = FlexFloat64(num)
-		return nil
-	}
-	return fmt.Errorf("cannot unmarshal %s into FlexFloat64", string(data))
+	ParamNameCn  string `json:"paramNameCn"`  // Chinese name, with LanguageChinese
+	ParamValueCn string `json:"paramValueCn"` // Chinese value, with LanguageChinese
 }
 
 // PriceBreak represents a quantity-based price tier.
 type PriceBreak struct {
-	Ladder         int         `json:"ladder"`         // Quantity threshold
+	Ladder         FlexInt     `json:"ladder"`         // Quantity threshold
 	ProductPrice   FlexFloat64 `json:"productPrice"`   // Price in selected currency
 	CurrencySymbol string      `json:"currencySymbol"` // Currency symbol like "US$"
 }
@@ -52,14 +29,21 @@ type Product struct {
 	PdfUrl            string       `json:"pdfUrl"`            // Datasheet URL
 	ProductImages     []string     `json:"productImages"`     // Multiple images
 	ProductImageUrl   string       `json:"productImageUrl"`   // Primary image
-	StockNumber       int          `json:"stockNumber"`       // Stock quantity
-	MinPacketNumber   int          `json:"minPacketNumber"`   // Min order qty
+	StockNumber       FlexInt      `json:"stockNumber"`       // Stock quantity
+	MinPacketNumber   FlexInt      `json:"minPacketNumber"`   // Min order qty
 	ProductPriceList  []PriceBreak `json:"productPriceList"`  // Price breaks
 	ParamVOList       []Parameter  `json:"paramVOList"`       // Specs/parameters
 	EncapStandard     string       `json:"encapStandard"`     // Footprint/package
 	ParentCatalogName string       `json:"parentCatalogName"` // Parent category
 	CatalogName       string       `json:"catalogName"`       // Subcategory
-	Weight            float64      `json:"weight"`            // Weight in grams
+	Weight            FlexFloat64  `json:"weight"`            // Weight in grams
+
+	// Language-specific text, returned with WithLanguage. See Localized.
+	ProductNameEn  string `json:"productNameEn"`  // Product name
+	ProductNameCn  string `json:"productNameCn"`  // Chinese product name
+	BrandNameCn    string `json:"brandNameCn"`    // Chinese manufacturer name
+	ProductIntroCn string `json:"productIntroCn"` // Chinese description
+	CatalogNameCn  string `json:"catalogNameCn"`  // Chinese subcategory
 }
 
 // GetProductURL returns the LCSC product page URL.
@@ -67,6 +51,39 @@ func (p *Product) GetProductURL() string {
 	return fmt.Sprintf("https://www.lcsc.com/product-detail/%s.html", p.ProductCode)
 }
 
+// PriceAt returns the price break that applies when ordering qty units: the
+// break with the largest Ladder not exceeding qty. Quantities below the first
+// ladder use the lowest break. It reports false if there are no price breaks.
+func (p *Product) PriceAt(qty int) (PriceBreak, bool) {
+	var best, lowest PriceBreak
+	found := false
+	for i, pb := range p.ProductPriceList {
+		if i == 0 || pb.Ladder < lowest.Ladder {
+			lowest = pb
+		}
+		if int(pb.Ladder) <= qty && (!found || pb.Ladder > best.Ladder) {
+			best, found = pb, true
+		}
+	}
+	if len(p.ProductPriceList) == 0 {
+		return PriceBreak{}, false
+	}
+	if !found {
+		return lowest, true
+	}
+	return best, true
+}
+
+// UnitPrice returns the unit price when ordering qty units, or 0 if the
+// product has no price breaks.
+func (p *Product) UnitPrice(qty int) float64 {
+	pb, ok := p.PriceAt(qty)
+	if !ok {
+		return 0
+	}
+	return float64(pb.ProductPrice)
+}
+
 // SearchRequest contains parameters for a product search.
 type SearchRequest struct {
 	Keyword     string
@@ -74,6 +91,12 @@ type SearchRequest struct {
 	PageSize    int
 	IsAvailable bool
 	MatchType   string // "exact" or "fuzzy"
+	Currency    string // Overrides the client currency for this search
+
+	// Filters and Sort are applied to the returned products client-side,
+	// after caching; TotalCount still reports LCSC's unfiltered count.
+	Filters []Filter
+	Sort    []Order
 }
 
 // SearchResponse contains the results of a product search.
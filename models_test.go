@@ -271,3 +271,37 @@ func TestSearchRequestStructure(t *testing.T) {
 		t.Error("expected IsAvailable to be true")
 	}
 }
+
+// TestProductPriceAt tests price break selection by quantity.
+func TestProductPriceAt(t *testing.T) {
+	product := &Product{ProductPriceList: []PriceBreak{
+		{Ladder: 100, ProductPrice: 0.08},
+		{Ladder: 10, ProductPrice: 0.1},
+		{Ladder: 1000, ProductPrice: 0.05},
+	}}
+
+	tests := []struct {
+		qty      int
+		expected float64
+	}{
+		{1, 0.1},
+		{10, 0.1},
+		{99, 0.1},
+		{100, 0.08},
+		{5000, 0.05},
+	}
+
+	for _, test := range tests {
+		if got := product.UnitPrice(test.qty); got != test.expected {
+			t.Errorf("UnitPrice(%d) = %f, expected %f", test.qty, got, test.expected)
+		}
+	}
+
+	empty := &Product{}
+	if _, ok := empty.PriceAt(10); ok {
+		t.Error("expected no price break for product without pricing")
+	}
+	if empty.UnitPrice(10) != 0 {
+		t.Error("expected zero unit price for product without pricing")
+	}
+}
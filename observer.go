@@ -0,0 +1,177 @@
+package lcsc
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RequestEventType identifies the kind of request event.
+type RequestEventType string
+
+const (
+	// EventRequestStart is emitted when a request is started, after any
+	// response cache lookup missed.
+	EventRequestStart RequestEventType = "request_start"
+	// EventRateLimitWait is emitted after waiting for the rate limiter, with
+	// the time spent waiting.
+	EventRateLimitWait RequestEventType = "rate_limit_wait"
+	// EventAttempt is emitted after each HTTP attempt, with its status and
+	// duration.
+	EventAttempt RequestEventType = "attempt"
+	// EventRetry is emitted when a failed attempt is retried, with the
+	// backoff delay before the next attempt.
+	EventRetry RequestEventType = "retry"
+	// EventCacheHit is emitted when a response is served from the cache.
+	EventCacheHit RequestEventType = "cache_hit"
+	// EventCacheMiss is emitted when a cache lookup finds no response.
+	EventCacheMiss RequestEventType = "cache_miss"
+	// EventRequestDone is emitted when a request completes or fails, with
+	// the total duration including retries.
+	EventRequestDone RequestEventType = "request_done"
+)
+
+// RequestEvent describes a step of an API or download request.
+type RequestEvent struct {
+	Type     RequestEventType
+	Method   string
+	Endpoint string        // Request path with product codes replaced by "{code}", or "download"
+	Attempt  int           // 1-based attempt number; for EventRequestDone, the number of attempts made
+	Status   int           // HTTP status code of the attempt or final response; 0 if none
+	Duration time.Duration // Wait, attempt, backoff or total request duration, depending on Type
+	Bytes    int64         // Response body size, for EventAttempt and EventRequestDone
+	Err      error         // Attempt or request error
+}
+
+// Observer receives request events. Observe is called synchronously from
+// the requesting goroutine and must be safe for concurrent use; it should
+// return quickly.
+type Observer interface {
+	Observe(ctx context.Context, event RequestEvent)
+}
+
+// ObserverFunc adapts a function to the Observer interface.
+type ObserverFunc func(ctx context.Context, event RequestEvent)
+
+// Observe calls f.
+func (f ObserverFunc) Observe(ctx context.Context, event RequestEvent) {
+	f(ctx, event)
+}
+
+// WithObserver adds an observer of request events. It can be given
+// multiple times; observers are called in order.
+func WithObserver(observer Observer) ClientOption {
+	return func(c *Client) {
+		if observer != nil {
+			c.observers = append(c.observers, observer)
+		}
+	}
+}
+
+// observe passes an event to the client's observers.
+func (c *Client) observe(ctx context.Context, event RequestEvent) {
+	for _, o := range c.observers {
+		o.Observe(ctx, event)
+	}
+}
+
+// cacheGet looks up key in the client's cache, reporting the hit or miss
+// for endpoint to observers.
+func (c *Client) cacheGet(ctx context.Context, method, endpoint, key string) ([]byte, bool) {
+	if c.cache == nil {
+		return nil, false
+	}
+	value, ok := c.cache.Get(key)
+	event := RequestEvent{Type: EventCacheMiss, Method: method, Endpoint: endpointName(endpoint)}
+	if ok {
+		event.Type = EventCacheHit
+		event.Bytes = int64(len(value))
+	}
+	c.observe(ctx, event)
+	return value, ok
+}
+
+// endpointName returns the path of a request path or URL, with LCSC
+// product codes replaced so names stay few enough to use as metric labels.
+func endpointName(path string) string {
+	if strings.Contains(path, "://") {
+		if u, err := url.Parse(path); err == nil {
+			path = u.Path
+		}
+	}
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if isProductCode(s) {
+			segments[i] = "{code}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// isProductCode reports whether s looks like an LCSC part number like "C2040".
+func isProductCode(s string) bool {
+	if len(s) < 2 || (s[0] != 'C' && s[0] != 'c') {
+		return false
+	}
+	for _, r := range s[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// SlogObserver logs request events to a slog.Logger. Completed requests are
+// logged at Info level, retries and failed requests at Warn, and all other
+// events at Debug.
+type SlogObserver struct {
+	logger *slog.Logger
+}
+
+// NewSlogObserver returns an observer logging to logger, or to
+// slog.Default() if logger is nil.
+func NewSlogObserver(logger *slog.Logger) *SlogObserver {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SlogObserver{logger: logger}
+}
+
+// Observe logs event.
+func (o *SlogObserver) Observe(ctx context.Context, event RequestEvent) {
+	level := slog.LevelDebug
+	switch {
+	case event.Type == EventRetry:
+		level = slog.LevelWarn
+	case event.Type == EventRequestDone && event.Err != nil:
+		level = slog.LevelWarn
+	case event.Type == EventRequestDone:
+		level = slog.LevelInfo
+	}
+	if !o.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", event.Method),
+		slog.String("endpoint", event.Endpoint),
+	}
+	if event.Attempt > 0 {
+		attrs = append(attrs, slog.Int("attempt", event.Attempt))
+	}
+	if event.Status > 0 {
+		attrs = append(attrs, slog.Int("status", event.Status))
+	}
+	if event.Duration > 0 {
+		attrs = append(attrs, slog.Duration("duration", event.Duration))
+	}
+	if event.Bytes > 0 {
+		attrs = append(attrs, slog.Int64("bytes", event.Bytes))
+	}
+	if event.Err != nil {
+		attrs = append(attrs, slog.String("error", event.Err.Error()))
+	}
+	o.logger.LogAttrs(ctx, level, "lcsc "+string(event.Type), attrs...)
+}
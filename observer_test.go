@@ -0,0 +1,231 @@
+package lcsc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"expvar"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// eventRecorder records request events.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []RequestEvent
+}
+
+func (r *eventRecorder) Observe(_ context.Context, event RequestEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder) types() []RequestEventType {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	types := make([]RequestEventType, len(r.events))
+	for i, e := range r.events {
+		types[i] = e.Type
+	}
+	return types
+}
+
+func (r *eventRecorder) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
+
+func (r *eventRecorder) last() RequestEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.events[len(r.events)-1]
+}
+
+// flakyDetailHandler fails the first failures requests with 503 and then
+// serves a product.
+func flakyDetailHandler(t *testing.T, failures int32) http.HandlerFunc {
+	var calls int32
+	return func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeAPIResult(t, w, Product{ProductCode: r.URL.Query().Get("productCode")})
+	}
+}
+
+// TestObserverEvents tests the events emitted for a retried request.
+func TestObserverEvents(t *testing.T) {
+	rec := &eventRecorder{}
+	retry := RetryConfig{MaxRetries: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Multiplier: 1}
+	client := newTestClient(t, flakyDetailHandler(t, 1), WithRetryConfig(retry), WithObserver(rec))
+
+	if _, err := client.GetProductDetails(context.Background(), "C2040"); err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+
+	expected := []RequestEventType{
+		EventRequestStart,
+		EventRateLimitWait, EventAttempt,
+		EventRetry,
+		EventRateLimitWait, EventAttempt,
+		EventRequestDone,
+	}
+	if got := rec.types(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("unexpected events: %v", got)
+	}
+
+	first := rec.events[2]
+	if first.Status != http.StatusServiceUnavailable || first.Err == nil || first.Attempt != 1 {
+		t.Errorf("unexpected first attempt: %+v", first)
+	}
+	if rec.events[3].Attempt != 2 || rec.events[3].Duration != time.Millisecond {
+		t.Errorf("unexpected retry event: %+v", rec.events[3])
+	}
+
+	done := rec.last()
+	if done.Endpoint != "/product/detail" || done.Method != "GET" || done.Status != http.StatusOK ||
+		done.Attempt != 2 || done.Bytes == 0 || done.Err != nil || done.Duration <= 0 {
+		t.Errorf("unexpected done event: %+v", done)
+	}
+}
+
+// TestObserverCache tests cache hit and miss events.
+func TestObserverCache(t *testing.T) {
+	rec := &eventRecorder{}
+	client := newTestClient(t, flakyDetailHandler(t, 0), WithCache(NewMemoryCache(time.Minute)), WithObserver(rec))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetProductDetails(context.Background(), "C2040"); err != nil {
+			t.Fatalf("GetProductDetails failed: %v", err)
+		}
+	}
+
+	types := rec.types()
+	if types[0] != EventCacheMiss || types[len(types)-1] != EventCacheHit {
+		t.Errorf("expected a miss first and a hit last, got %v", types)
+	}
+	if hit := rec.last(); hit.Endpoint != "/product/detail" || hit.Bytes == 0 {
+		t.Errorf("unexpected hit event: %+v", hit)
+	}
+	if n := countCacheEvents(types); n != 2 {
+		t.Errorf("expected one cache event per call, got %d in %v", n, types)
+	}
+
+	// Once the product is evicted, the call misses once and fetches it.
+	rec.reset()
+	client.cache.Delete(client.getCacheKeyProduct("C2040"))
+	if _, err := client.GetProductDetails(context.Background(), "C2040"); err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+	types = rec.types()
+	if n := countCacheEvents(types); n != 1 || types[0] != EventCacheMiss || rec.last().Type != EventRequestDone {
+		t.Errorf("expected a single miss and a request, got %v", types)
+	}
+}
+
+// countCacheEvents returns the number of cache hits and misses in types.
+func countCacheEvents(types []RequestEventType) int {
+	n := 0
+	for _, typ := range types {
+		if typ == EventCacheHit || typ == EventCacheMiss {
+			n++
+		}
+	}
+	return n
+}
+
+// TestObserverFailure tests the done event of a failed request.
+func TestObserverFailure(t *testing.T) {
+	rec := &eventRecorder{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}, WithObserver(rec))
+
+	if _, err := client.GetProductDetails(context.Background(), "C2040"); err == nil {
+		t.Fatal("expected error")
+	}
+	done := rec.last()
+	if done.Type != EventRequestDone || done.Status != http.StatusBadRequest || done.Err == nil {
+		t.Errorf("unexpected done event: %+v", done)
+	}
+}
+
+// TestEndpointName tests normalization of endpoint names.
+func TestEndpointName(t *testing.T) {
+	tests := map[string]string{
+		"/product/detail":   "/product/detail",
+		"/search/v2/global": "/search/v2/global",
+		"https://easyeda.com/api/products/C20526/components": "/api/products/{code}/components",
+		"/api/products/Catalog":                              "/api/products/Catalog",
+	}
+	for path, expected := range tests {
+		if got := endpointName(path); got != expected {
+			t.Errorf("endpointName(%q) = %q, expected %q", path, got, expected)
+		}
+	}
+}
+
+// TestSlogObserver tests logging of request events.
+func TestSlogObserver(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	client := newTestClient(t, flakyDetailHandler(t, 0), WithObserver(NewSlogObserver(logger)))
+
+	if _, err := client.GetProductDetails(context.Background(), "C2040"); err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+
+	out := buf.String()
+	if strings.Count(out, "\n") != 1 {
+		t.Errorf("expected only the done event at Info level, got:\n%s", out)
+	}
+	for _, want := range []string{`msg="lcsc request_done"`, "endpoint=/product/detail", "status=200", "attempt=1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected log to contain %s, got:\n%s", want, out)
+		}
+	}
+}
+
+// TestExpvarObserver tests request metrics.
+func TestExpvarObserver(t *testing.T) {
+	metrics := NewExpvarObserver("lcsc_test")
+	retry := RetryConfig{MaxRetries: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Multiplier: 1}
+	client := newTestClient(t, flakyDetailHandler(t, 1), WithRetryConfig(retry), WithObserver(metrics))
+
+	if _, err := client.GetProductDetails(context.Background(), "C2040"); err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+
+	if expvar.Get("lcsc_test") != metrics.Map() {
+		t.Error("expected metrics to be published")
+	}
+	if NewExpvarObserver("lcsc_test").Map() != metrics.Map() {
+		t.Error("expected observers with the same name to share metrics")
+	}
+
+	var got struct {
+		Requests  int64                     `json:"requests"`
+		Attempts  int64                     `json:"attempts"`
+		Retries   int64                     `json:"retries"`
+		Status    map[string]int64          `json:"status"`
+		Endpoints map[string]map[string]any `json:"endpoints"`
+	}
+	if err := json.Unmarshal([]byte(metrics.Map().String()), &got); err != nil {
+		t.Fatalf("invalid metrics JSON: %v", err)
+	}
+	if got.Requests != 1 || got.Attempts != 2 || got.Retries != 1 || got.Status["200"] != 1 {
+		t.Errorf("unexpected metrics: %+v", got)
+	}
+	if got.Endpoints["/product/detail"]["requests"] != float64(1) {
+		t.Errorf("unexpected endpoint metrics: %+v", got.Endpoints)
+	}
+}
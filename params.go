@@ -0,0 +1,128 @@
+package lcsc
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParamValue is a parsed numeric parameter value such as "100nF", "±10%" or
+// "50V". Value is expressed in the base unit with any SI prefix applied, so
+// "100nF" becomes 1e-7 with Unit "F".
+type ParamValue struct {
+	Raw   string  // Original text
+	Value float64 // Numeric value in base units
+	Unit  string  // Normalized base unit such as "F", "Ω", "V" or "%"
+	Valid bool    // Whether a numeric value was found
+}
+
+// siPrefixes maps SI prefix characters to their multipliers.
+var siPrefixes = map[rune]float64{
+	'p': 1e-12,
+	'n': 1e-9,
+	'u': 1e-6,
+	'µ': 1e-6,
+	'μ': 1e-6,
+	'm': 1e-3,
+	'k': 1e3,
+	'K': 1e3,
+	'M': 1e6,
+	'G': 1e9,
+}
+
+// unitAliases maps lowercased unit spellings found in LCSC parameters to a
+// normalized unit.
+var unitAliases = map[string]string{
+	"f":    "F",
+	"ω":    "Ω", // Ohm sign and capital omega, lowercased
+	"ohm":  "Ω",
+	"ohms": "Ω",
+	"r":    "Ω",
+	"h":    "H",
+	"v":    "V",
+	"a":    "A",
+	"w":    "W",
+	"hz":   "Hz",
+	"s":    "s",
+	"%":    "%",
+}
+
+// ParseParamValue parses the leading number, SI prefix and unit of a
+// parameter value. Leading "±" and "+" signs are ignored. Text without a
+// leading number yields a ParamValue with Valid set to false.
+func ParseParamValue(raw string) ParamValue {
+	pv := ParamValue{Raw: raw}
+
+	s := strings.TrimSpace(raw)
+	s = strings.TrimLeft(s, "±+ ")
+
+	end := 0
+	for end < len(s) && (s[end] >= '0' && s[end] <= '9' || s[end] == '.' || (end == 0 && s[end] == '-')) {
+		end++
+	}
+	num, err := strconv.ParseFloat(s[:end], 64)
+	if err != nil {
+		return pv
+	}
+
+	unit := strings.TrimSpace(s[end:])
+	if cut := strings.IndexAny(unit, " ~,(/"); cut >= 0 && !strings.HasPrefix(unit, "ppm") {
+		unit = unit[:cut]
+	}
+
+	if r, size := firstRune(unit); size > 0 {
+		if mult, ok := siPrefixes[r]; ok {
+			rest := unit[size:]
+			if _, known := unitAliases[strings.ToLower(rest)]; rest == "" || known {
+				num *= mult
+				unit = rest
+			}
+		}
+	}
+
+	if normalized, ok := unitAliases[strings.ToLower(unit)]; ok {
+		unit = normalized
+	}
+
+	pv.Value = num
+	pv.Unit = unit
+	pv.Valid = true
+	return pv
+}
+
+// firstRune returns the first rune of s and its byte length.
+func firstRune(s string) (rune, int) {
+	for _, r := range s {
+		return r, len(string(r))
+	}
+	return 0, 0
+}
+
+// Param returns the value of the named parameter from ParamVOList.
+// Names are compared case-insensitively.
+func (p *Product) Param(name string) (string, bool) {
+	for _, param := range p.ParamVOList {
+		if strings.EqualFold(strings.TrimSpace(param.ParamNameEn), name) {
+			return param.ParamValueEn, true
+		}
+	}
+	return "", false
+}
+
+// ParsedParam returns the named parameter parsed with ParseParamValue.
+func (p *Product) ParsedParam(name string) (ParamValue, bool) {
+	raw, ok := p.Param(name)
+	if !ok {
+		return ParamValue{}, false
+	}
+	return ParseParamValue(raw), true
+}
+
+// firstParam returns the first of names present on the product.
+func (p *Product) firstParam(names []string) (string, string, bool) {
+	for _, name := range names {
+		if value, ok := p.Param(name); ok && strings.TrimSpace(value) != "" && value != "-" {
+			return name, value, true
+		}
+	}
+	return "", "", false
+}
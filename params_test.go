@@ -0,0 +1,72 @@
+package lcsc
+
+import (
+	"math"
+	"testing"
+)
+
+// TestParseParamValue tests parsing of numeric parameter values.
+func TestParseParamValue(t *testing.T) {
+	tests := []struct {
+		raw   string
+		value float64
+		unit  string
+	}{
+		{"100nF", 100e-9, "F"},
+		{"4.7uF", 4.7e-6, "F"},
+		{"10µF", 10e-6, "F"},
+		{"10kΩ", 10e3, "Ω"},
+		{"10mΩ", 10e-3, "Ω"},
+		{"1MΩ", 1e6, "Ω"},
+		{"100Ω", 100, "Ω"},
+		{"±10%", 10, "%"},
+		{"±1%", 1, "%"},
+		{"50V", 50, "V"},
+		{"2.2uH", 2.2e-6, "H"},
+		{"8MHz", 8e6, "Hz"},
+		{"±100ppm/℃", 100, "ppm/℃"},
+		{"-55℃~+125℃", -55, "℃"},
+		{"1M", 1e6, ""},
+	}
+
+	for _, test := range tests {
+		pv := ParseParamValue(test.raw)
+		if !pv.Valid {
+			t.Errorf("ParseParamValue(%q) not valid", test.raw)
+			continue
+		}
+		if math.Abs(pv.Value-test.value) > 1e-9*math.Max(1, math.Abs(test.value)) || pv.Unit != test.unit {
+			t.Errorf("ParseParamValue(%q) = %g %q, expected %g %q", test.raw, pv.Value, pv.Unit, test.value, test.unit)
+		}
+	}
+}
+
+// TestParseParamValueInvalid tests values without a leading number.
+func TestParseParamValueInvalid(t *testing.T) {
+	for _, raw := range []string{"X7R", "", "-", "C0G"} {
+		if pv := ParseParamValue(raw); pv.Valid {
+			t.Errorf("expected ParseParamValue(%q) to be invalid, got %+v", raw, pv)
+		}
+	}
+}
+
+// TestProductParam tests case-insensitive parameter lookup.
+func TestProductParam(t *testing.T) {
+	product := Product{ParamVOList: []Parameter{
+		{ParamNameEn: "Capacitance", ParamValueEn: "100nF"},
+		{ParamNameEn: "Voltage Rated", ParamValueEn: "50V"},
+	}}
+
+	if value, ok := product.Param("capacitance"); !ok || value != "100nF" {
+		t.Errorf("expected 100nF, got %q (%v)", value, ok)
+	}
+
+	if _, ok := product.Param("Tolerance"); ok {
+		t.Error("expected missing parameter")
+	}
+
+	pv, ok := product.ParsedParam("Voltage Rated")
+	if !ok || pv.Value != 50 || pv.Unit != "V" {
+		t.Errorf("expected 50V, got %+v", pv)
+	}
+}
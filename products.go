@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
-	"time"
 )
 
 // searchRequestBody is the JSON body for the search endpoint.
@@ -21,12 +20,21 @@ func (c *Client) KeywordSearch(ctx context.Context, req SearchRequest) (*SearchR
 	if keyword == "" {
 		return nil, fmt.Errorf("keyword is required")
 	}
+	if req.Currency != "" {
+		cc, err := c.InCurrency(req.Currency)
+		if err != nil {
+			return nil, err
+		}
+		req.Currency = ""
+		return cc.KeywordSearch(ctx, req)
+	}
 
 	cacheKey := c.getCacheKeySearch(keyword)
 	if c.cache != nil {
-		if cached, ok := c.cache.Get(cacheKey); ok {
+		if cached, ok := c.cacheGet(ctx, "POST", "/search/v2/global", cacheKey); ok {
 			var resp SearchResponse
 			if err := json.Unmarshal(cached, &resp); err == nil {
+				resp.Products = applyListOptions(resp.Products, req.Filters, req.Sort)
 				return &resp, nil
 			}
 		}
@@ -55,10 +63,11 @@ func (c *Client) KeywordSearch(ctx context.Context, req SearchRequest) (*SearchR
 
 	if c.cache != nil {
 		if cacheData, err := json.Marshal(resp); err == nil {
-			c.cache.Set(cacheKey, cacheData, 5*time.Minute)
+			c.cache.Set(cacheKey, cacheData, c.cacheTTL)
 		}
 	}
 
+	resp.Products = applyListOptions(resp.Products, req.Filters, req.Sort)
 	return resp, nil
 }
 
@@ -72,7 +81,7 @@ func (c *Client) GetProductDetails(ctx context.Context, productCode string) (*Pr
 
 	cacheKey := c.getCacheKeyProduct(productCode)
 	if c.cache != nil {
-		if cached, ok := c.cache.Get(cacheKey); ok {
+		if cached, ok := c.cacheGet(ctx, "GET", "/product/detail", cacheKey); ok {
 			var product Product
 			if err := json.Unmarshal(cached, &product); err == nil {
 				return &product, nil
@@ -95,19 +104,42 @@ func (c *Client) GetProductDetails(ctx context.Context, productCode string) (*Pr
 
 	if c.cache != nil {
 		if cacheData, err := json.Marshal(product); err == nil {
-			c.cache.Set(cacheKey, cacheData, 5*time.Minute)
+			c.cache.Set(cacheKey, cacheData, c.cacheTTL)
 		}
 	}
 
 	return &product, nil
 }
 
+// GetProductDetailsIn retrieves a product's details priced in the given
+// currency, as GetProductDetails on a client from InCurrency. An empty
+// currency uses the client's.
+func (c *Client) GetProductDetailsIn(ctx context.Context, productCode, currency string) (*Product, error) {
+	if currency == "" {
+		return c.GetProductDetails(ctx, productCode)
+	}
+	cc, err := c.InCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	return cc.GetProductDetails(ctx, productCode)
+}
+
 // getCacheKeySearch generates a cache key for search requests.
 func (c *Client) getCacheKeySearch(keyword string) string {
-	return fmt.Sprintf("search:%s:%s", c.currency, keyword)
+	return fmt.Sprintf("search:%s:%s", c.cacheScope(), keyword)
 }
 
 // getCacheKeyProduct generates a cache key for product detail requests.
 func (c *Client) getCacheKeyProduct(productCode string) string {
-	return fmt.Sprintf("product:%s:%s", c.currency, productCode)
+	return fmt.Sprintf("product:%s:%s", c.cacheScope(), productCode)
+}
+
+// invalidateProduct removes the cached details of a product so the next
+// GetProductDetails call reaches the API.
+func (c *Client) invalidateProduct(productCode string) {
+	if c.cache == nil {
+		return
+	}
+	c.cache.Delete(c.getCacheKeyProduct(productCode))
 }
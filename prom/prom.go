@@ -0,0 +1,356 @@
+// Package prom exports LCSC client metrics in the Prometheus text
+// exposition format, without depending on the Prometheus client library.
+//
+// # Usage
+//
+//	exporter := prom.NewExporter(prom.Options{Cache: cache})
+//	client := lcsc.NewClient(lcsc.WithCache(cache), lcsc.WithObserver(exporter))
+//	http.Handle("/metrics", exporter)
+//
+// The exporter serves:
+//
+//	lcsc_requests_total{endpoint,status}          completed requests
+//	lcsc_request_duration_seconds{endpoint}       request latency histogram, including retries
+//	lcsc_retries_total{endpoint}                  retried attempts
+//	lcsc_rate_limit_wait_seconds_total            time spent waiting for the rate limiter
+//	lcsc_cache_hits_total, lcsc_cache_misses_total
+//	lcsc_cache_hit_ratio                          hits over lookups since start
+//	lcsc_cache_entries                            MemoryCache size, if Options.Cache is set
+//	lcsc_circuit_open                             1 while the circuit is open, if Options.CircuitOpen is set
+//
+// Requests that fail without an HTTP response are counted with status
+// "error". Further gauges can be added with AddGauge.
+package prom
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	lcsc "github.com/PatrickWalther/go-lcsc"
+)
+
+// ContentType is the content type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the latency histogram buckets in seconds.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+var metricName = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// Options configures an Exporter.
+type Options struct {
+	// Namespace prefixes metric names (default: "lcsc").
+	Namespace string
+	// Buckets are the upper bounds of the latency histogram in seconds
+	// (default: DefaultBuckets).
+	Buckets []float64
+	// Cache, if set, is reported as lcsc_cache_entries.
+	Cache *lcsc.MemoryCache
+	// CircuitOpen, if set, reports the state of a circuit breaker wrapping
+	// the client as lcsc_circuit_open. It is called on every scrape.
+	CircuitOpen func() bool
+}
+
+// Exporter collects client request events and serves them as Prometheus
+// metrics. It implements lcsc.Observer and http.Handler, and is safe for
+// concurrent use.
+type Exporter struct {
+	namespace   string
+	buckets     []float64
+	cache       *lcsc.MemoryCache
+	circuitOpen func() bool
+
+	mu            sync.Mutex
+	requests      map[requestKey]uint64
+	durations     map[string]*histogram
+	retries       map[string]uint64
+	rateLimitWait float64
+	cacheHits     uint64
+	cacheMisses   uint64
+	gauges        []gauge
+}
+
+type requestKey struct {
+	endpoint string
+	status   string
+}
+
+type histogram struct {
+	counts []uint64 // Per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+type gauge struct {
+	name string
+	help string
+	fn   func() float64
+}
+
+// NewExporter creates an exporter.
+func NewExporter(opts Options) *Exporter {
+	if opts.Namespace == "" {
+		opts.Namespace = "lcsc"
+	}
+	buckets := append([]float64(nil), opts.Buckets...)
+	if len(buckets) == 0 {
+		buckets = append(buckets, DefaultBuckets...)
+	}
+	sort.Float64s(buckets)
+
+	return &Exporter{
+		namespace:   opts.Namespace,
+		buckets:     buckets,
+		cache:       opts.Cache,
+		circuitOpen: opts.CircuitOpen,
+		requests:    make(map[requestKey]uint64),
+		durations:   make(map[string]*histogram),
+		retries:     make(map[string]uint64),
+	}
+}
+
+// builtinMetrics are the names of the exporter's own metrics, without the
+// namespace.
+var builtinMetrics = []string{
+	"requests_total", "request_duration_seconds", "retries_total", "rate_limit_wait_seconds_total",
+	"cache_hits_total", "cache_misses_total", "cache_hit_ratio", "cache_entries", "circuit_open",
+}
+
+// AddGauge adds a gauge whose value is read from fn on every scrape. name
+// is prefixed with the namespace and must not be one of the built-in
+// metrics.
+func (e *Exporter) AddGauge(name, help string, fn func() float64) error {
+	full := e.namespace + "_" + name
+	if !metricName.MatchString(full) {
+		return fmt.Errorf("invalid metric name %q", full)
+	}
+	for _, builtin := range builtinMetrics {
+		if name == builtin {
+			return fmt.Errorf("duplicate metric %q", full)
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, g := range e.gauges {
+		if g.name == full {
+			return fmt.Errorf("duplicate metric %q", full)
+		}
+	}
+	e.gauges = append(e.gauges, gauge{name: full, help: help, fn: fn})
+	return nil
+}
+
+// Observe records a client request event.
+func (e *Exporter) Observe(_ context.Context, event lcsc.RequestEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch event.Type {
+	case lcsc.EventRequestDone:
+		status := "error"
+		if event.Status > 0 {
+			status = strconv.Itoa(event.Status)
+		}
+		e.requests[requestKey{event.Endpoint, status}]++
+
+		h := e.durations[event.Endpoint]
+		if h == nil {
+			h = &histogram{counts: make([]uint64, len(e.buckets))}
+			e.durations[event.Endpoint] = h
+		}
+		seconds := event.Duration.Seconds()
+		if i := sort.SearchFloat64s(e.buckets, seconds); i < len(e.buckets) {
+			h.counts[i]++
+		}
+		h.count++
+		h.sum += seconds
+	case lcsc.EventRetry:
+		e.retries[event.Endpoint]++
+	case lcsc.EventRateLimitWait:
+		e.rateLimitWait += event.Duration.Seconds()
+	case lcsc.EventCacheHit:
+		e.cacheHits++
+	case lcsc.EventCacheMiss:
+		e.cacheMisses++
+	}
+}
+
+// ServeHTTP writes the metrics in text exposition format.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = e.WriteTo(w)
+}
+
+// WriteTo writes the metrics in text exposition format to w.
+func (e *Exporter) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	e.write(cw)
+	if err := cw.w.Flush(); err != nil && cw.err == nil {
+		cw.err = err
+	}
+	return cw.n, cw.err
+}
+
+// write writes all metrics. Gauge functions are called without holding
+// e.mu so they may use the client.
+func (e *Exporter) write(w *countingWriter) {
+	e.mu.Lock()
+	gauges := append([]gauge(nil), e.gauges...)
+
+	name := e.namespace + "_requests_total"
+	w.header(name, "counter", "Completed LCSC requests by endpoint and final status.")
+	keys := make([]requestKey, 0, len(e.requests))
+	for k := range e.requests {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].endpoint != keys[j].endpoint {
+			return keys[i].endpoint < keys[j].endpoint
+		}
+		return keys[i].status < keys[j].status
+	})
+	for _, k := range keys {
+		w.sample(name, labels("endpoint", k.endpoint, "status", k.status), float64(e.requests[k]))
+	}
+
+	name = e.namespace + "_request_duration_seconds"
+	w.header(name, "histogram", "LCSC request latency including retries.")
+	for _, endpoint := range sortedKeys(e.durations) {
+		h := e.durations[endpoint]
+		var cumulative uint64
+		for i, le := range e.buckets {
+			cumulative += h.counts[i]
+			w.sample(name+"_bucket", labels("endpoint", endpoint, "le", formatFloat(le)), float64(cumulative))
+		}
+		w.sample(name+"_bucket", labels("endpoint", endpoint, "le", "+Inf"), float64(h.count))
+		w.sample(name+"_sum", labels("endpoint", endpoint), h.sum)
+		w.sample(name+"_count", labels("endpoint", endpoint), float64(h.count))
+	}
+
+	name = e.namespace + "_retries_total"
+	w.header(name, "counter", "Retried LCSC request attempts by endpoint.")
+	for _, endpoint := range sortedKeys(e.retries) {
+		w.sample(name, labels("endpoint", endpoint), float64(e.retries[endpoint]))
+	}
+
+	name = e.namespace + "_rate_limit_wait_seconds_total"
+	w.header(name, "counter", "Time spent waiting for the client rate limiter.")
+	w.sample(name, "", e.rateLimitWait)
+
+	name = e.namespace + "_cache_hits_total"
+	w.header(name, "counter", "Responses served from the client cache.")
+	w.sample(name, "", float64(e.cacheHits))
+	name = e.namespace + "_cache_misses_total"
+	w.header(name, "counter", "Client cache lookups that found no response.")
+	w.sample(name, "", float64(e.cacheMisses))
+
+	ratio := math.NaN()
+	if lookups := e.cacheHits + e.cacheMisses; lookups > 0 {
+		ratio = float64(e.cacheHits) / float64(lookups)
+	}
+	name = e.namespace + "_cache_hit_ratio"
+	w.header(name, "gauge", "Fraction of client cache lookups that were hits.")
+	w.sample(name, "", ratio)
+	e.mu.Unlock()
+
+	if e.cache != nil {
+		name = e.namespace + "_cache_entries"
+		w.header(name, "gauge", "Entries in the client memory cache.")
+		w.sample(name, "", float64(e.cache.Size()))
+	}
+	if e.circuitOpen != nil {
+		name = e.namespace + "_circuit_open"
+		w.header(name, "gauge", "Whether the circuit breaker around the client is open.")
+		open := 0.0
+		if e.circuitOpen() {
+			open = 1
+		}
+		w.sample(name, "", open)
+	}
+
+	for _, g := range gauges {
+		w.header(g.name, "gauge", g.help)
+		w.sample(g.name, "", g.fn())
+	}
+}
+
+// countingWriter writes exposition lines, keeping the first error.
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (w *countingWriter) printf(format string, args ...interface{}) {
+	if w.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(w.w, format, args...)
+	w.n += int64(n)
+	w.err = err
+}
+
+func (w *countingWriter) header(name, typ, help string) {
+	w.printf("# HELP %s %s\n# TYPE %s %s\n", name, escapeHelp(help), name, typ)
+}
+
+func (w *countingWriter) sample(name, labels string, value float64) {
+	w.printf("%s%s %s\n", name, labels, formatFloat(value))
+}
+
+// labels formats label name and value pairs as {name="value",...}.
+func labels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i])
+		b.WriteString(`="`)
+		b.WriteString(escapeLabel(pairs[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+// formatFloat formats a sample value.
+func formatFloat(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,135 @@
+package prom
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	lcsc "github.com/PatrickWalther/go-lcsc"
+)
+
+// newTestClient returns a client observed by e whose server fails the
+// first product detail request with 503.
+func newTestClient(t *testing.T, e *Exporter, cache *lcsc.MemoryCache) *lcsc.Client {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Query().Get("productCode") == "C404" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		result, _ := json.Marshal(lcsc.Product{ProductCode: r.URL.Query().Get("productCode")})
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 200, "result": json.RawMessage(result)})
+	}))
+	t.Cleanup(server.Close)
+
+	return lcsc.NewClient(
+		lcsc.WithBaseURL(server.URL),
+		lcsc.WithRetryConfig(lcsc.RetryConfig{MaxRetries: 1, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Multiplier: 1}),
+		lcsc.WithRateLimit(1000),
+		lcsc.WithCache(cache),
+		lcsc.WithObserver(e),
+	)
+}
+
+// scrape returns the exporter's response body.
+func scrape(t *testing.T, e *Exporter) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("unexpected content type: %s", ct)
+	}
+	return rec.Body.String()
+}
+
+// TestExporter tests metrics collected from client requests.
+func TestExporter(t *testing.T) {
+	cache := lcsc.NewMemoryCache(time.Minute)
+	defer cache.Close()
+	e := NewExporter(Options{Cache: cache, Buckets: []float64{1, 0.001}, CircuitOpen: func() bool { return true }})
+	if err := e.AddGauge("workers", "Busy lookup workers.", func() float64 { return 3 }); err != nil {
+		t.Fatal(err)
+	}
+	client := newTestClient(t, e, cache)
+
+	ctx := context.Background()
+	for _, code := range []string{"C1", "C1", "C404"} {
+		_, _ = client.GetProductDetails(ctx, code)
+	}
+
+	out := scrape(t, e)
+	for _, want := range []string{
+		"# TYPE lcsc_requests_total counter\n",
+		`lcsc_requests_total{endpoint="/product/detail",status="200"} 1` + "\n",
+		`lcsc_requests_total{endpoint="/product/detail",status="404"} 1` + "\n",
+		"# TYPE lcsc_request_duration_seconds histogram\n",
+		`lcsc_request_duration_seconds_bucket{endpoint="/product/detail",le="1"} 2` + "\n",
+		`lcsc_request_duration_seconds_bucket{endpoint="/product/detail",le="+Inf"} 2` + "\n",
+		`lcsc_request_duration_seconds_count{endpoint="/product/detail"} 2` + "\n",
+		`lcsc_retries_total{endpoint="/product/detail"} 1` + "\n",
+		"lcsc_rate_limit_wait_seconds_total ",
+		"lcsc_cache_hits_total 1\n",
+		"lcsc_cache_misses_total 2\n",
+		"lcsc_cache_hit_ratio 0.3333333333333333\n",
+		"lcsc_cache_entries 1\n",
+		"# TYPE lcsc_circuit_open gauge\n",
+		"lcsc_circuit_open 1\n",
+		"# HELP lcsc_workers Busy lookup workers.\n",
+		"lcsc_workers 3\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	if strings.Index(out, `le="0.001"`) > strings.Index(out, `le="1"`) {
+		t.Error("expected buckets in ascending order")
+	}
+}
+
+// TestExporterEmpty tests output before any request.
+func TestExporterEmpty(t *testing.T) {
+	out := scrape(t, NewExporter(Options{Namespace: "parts"}))
+	if !strings.Contains(out, "parts_cache_hit_ratio NaN\n") || !strings.Contains(out, "parts_rate_limit_wait_seconds_total 0\n") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if strings.Contains(out, "cache_entries") || strings.Contains(out, "circuit_open") {
+		t.Error("expected no cache size or circuit state without their options")
+	}
+}
+
+// TestAddGaugeInvalid tests rejection of invalid and duplicate gauges.
+func TestAddGaugeInvalid(t *testing.T) {
+	e := NewExporter(Options{})
+	if err := e.AddGauge("bad name", "", func() float64 { return 0 }); err == nil {
+		t.Error("expected error for invalid name")
+	}
+	if err := e.AddGauge("up", "", func() float64 { return 1 }); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.AddGauge("up", "", func() float64 { return 1 }); err == nil {
+		t.Error("expected error for duplicate gauge")
+	}
+	if err := e.AddGauge("circuit_open", "", func() float64 { return 1 }); err == nil {
+		t.Error("expected error for a built-in metric name")
+	}
+}
+
+// TestLabels tests label value escaping.
+func TestLabels(t *testing.T) {
+	got := labels("endpoint", `a"b\c`+"\n", "status", "200")
+	expected := `{endpoint="a\"b\\c\n",status="200"}`
+	if got != expected {
+		t.Errorf("labels() = %s, expected %s", got, expected)
+	}
+}
@@ -0,0 +1,376 @@
+package lcsc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRateProduct is the reference product exchange rates are derived
+	// from: a part with stable multi-tier pricing.
+	DefaultRateProduct = "C8734"
+	// DefaultRateMaxAge is how long a Converter uses a rate table before
+	// fetching a new one.
+	DefaultRateMaxAge = 24 * time.Hour
+	// DefaultRateRetryInterval is how long a Converter waits after a failed
+	// refresh before fetching rates again.
+	DefaultRateRetryInterval = 15 * time.Minute
+)
+
+// ErrNoRate is returned when a rate table has no rate for a currency.
+var ErrNoRate = errors.New("lcsc: no exchange rate")
+
+// RateTable holds exchange rates derived from LCSC prices.
+type RateTable struct {
+	Base      string             `json:"base"`      // Currency the rates are relative to
+	Rates     map[string]float64 `json:"rates"`     // Units of each currency per unit of Base
+	Reference string             `json:"reference"` // Product code the rates were derived from
+	Time      time.Time          `json:"time"`      // When the prices were fetched
+	// Updated holds the fetch times of rates kept from an earlier table
+	// because a refresh could not fetch them; rates not listed are from Time.
+	Updated map[string]time.Time `json:"updated,omitempty"`
+}
+
+// RateTime returns when the rate for a currency was fetched.
+func (t *RateTable) RateTime(code string) time.Time {
+	if updated, ok := t.Updated[strings.ToUpper(strings.TrimSpace(code))]; ok {
+		return updated
+	}
+	return t.Time
+}
+
+// Rate returns the number of units of to per unit of from.
+func (t *RateTable) Rate(from, to string) (float64, error) {
+	from, to = strings.ToUpper(strings.TrimSpace(from)), strings.ToUpper(strings.TrimSpace(to))
+	if from == to {
+		return 1, nil
+	}
+	fromRate, ok := t.Rates[from]
+	if !ok || fromRate <= 0 {
+		return 0, fmt.Errorf("%w: %s", ErrNoRate, from)
+	}
+	toRate, ok := t.Rates[to]
+	if !ok || toRate <= 0 {
+		return 0, fmt.Errorf("%w: %s", ErrNoRate, to)
+	}
+	return toRate / fromRate, nil
+}
+
+// Currencies returns the codes of the currencies in the table, sorted.
+func (t *RateTable) Currencies() []string {
+	codes := make([]string, 0, len(t.Rates))
+	for code := range t.Rates {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Convert converts an amount between currencies.
+func (t *RateTable) Convert(amount float64, from, to string) (float64, error) {
+	rate, err := t.Rate(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate, nil
+}
+
+// ConvertPriceList returns a copy of a price list with prices converted
+// between currencies and the currency symbol of to.
+func (t *RateTable) ConvertPriceList(prices []PriceBreak, from, to string) ([]PriceBreak, error) {
+	rate, err := t.Rate(from, to)
+	if err != nil {
+		return nil, err
+	}
+	symbol := strings.ToUpper(strings.TrimSpace(to))
+	if cur, ok := LookupCurrency(to); ok {
+		symbol = cur.Symbol
+	}
+
+	converted := make([]PriceBreak, len(prices))
+	for i, pb := range prices {
+		converted[i] = PriceBreak{
+			Ladder:         pb.Ladder,
+			ProductPrice:   FlexFloat64(float64(pb.ProductPrice) * rate),
+			CurrencySymbol: symbol,
+		}
+	}
+	return converted, nil
+}
+
+// FetchRates derives exchange rates relative to the client currency by
+// fetching a reference product in each currency and comparing its prices
+// at the price breaks all currencies share. An empty reference uses
+// DefaultRateProduct, and no currencies means all supported currencies.
+// If only some currencies fail, the table is returned along with an error
+// naming them.
+func (c *Client) FetchRates(ctx context.Context, reference string, currencies ...string) (*RateTable, error) {
+	if strings.TrimSpace(reference) == "" {
+		reference = DefaultRateProduct
+	}
+	if len(currencies) == 0 {
+		for _, cur := range Currencies() {
+			currencies = append(currencies, cur.Code)
+		}
+	}
+
+	base := c.currency
+	products, err := c.GetProductInCurrencies(ctx, reference, append([]string{base}, currencies...)...)
+	baseProduct := products[base]
+	if baseProduct == nil {
+		if err == nil {
+			err = fmt.Errorf("%w: %s", ErrNoRate, base)
+		}
+		return nil, err
+	}
+
+	table := &RateTable{Base: base, Rates: map[string]float64{base: 1}, Reference: reference, Time: time.Now()}
+	errs := []error{err}
+	for code, product := range products {
+		if code == base {
+			continue
+		}
+		rate, ok := priceRatio(baseProduct.ProductPriceList, product.ProductPriceList)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: %s: no matching prices", ErrNoRate, code))
+			continue
+		}
+		table.Rates[code] = rate
+	}
+	return table, errors.Join(errs...)
+}
+
+// priceRatio returns the ratio of the sums of two price lists over their
+// common ladders.
+func priceRatio(base, other []PriceBreak) (float64, bool) {
+	basePrices := make(map[FlexInt]float64, len(base))
+	for _, pb := range base {
+		basePrices[pb.Ladder] = float64(pb.ProductPrice)
+	}
+	var baseSum, otherSum float64
+	for _, pb := range other {
+		if bp, ok := basePrices[pb.Ladder]; ok && bp > 0 && pb.ProductPrice > 0 {
+			baseSum += bp
+			otherSum += float64(pb.ProductPrice)
+		}
+	}
+	if baseSum == 0 {
+		return 0, false
+	}
+	return otherSum / baseSum, true
+}
+
+// ConverterOptions configures a Converter.
+type ConverterOptions struct {
+	// ReferenceProduct is the product rates are derived from (default: DefaultRateProduct).
+	ReferenceProduct string
+	// Currencies are the currencies to fetch rates for (default: all supported).
+	Currencies []string
+	// MaxAge is how long a rate table is used before it is refreshed
+	// (default: DefaultRateMaxAge).
+	MaxAge time.Duration
+	// RetryInterval is how long to wait after a failed refresh before
+	// trying again (default: DefaultRateRetryInterval).
+	RetryInterval time.Duration
+	// File, if set, persists the rate table as JSON so it survives restarts
+	// and can be used offline.
+	File string
+	// OnSaveError, if set, is called when File cannot be written. The
+	// fetched table is used regardless.
+	OnSaveError func(error)
+}
+
+// Converter converts prices between currencies using a cached rate table.
+// When the table is older than MaxAge a new one is fetched; if that fails,
+// the old table keeps being used and no new fetch is tried for
+// RetryInterval, so conversion works offline once rates have been fetched.
+// Rates a refresh fails to fetch are kept from the old table, so rates in
+// one table may differ in age; see RateTable.RateTime. It is safe for
+// concurrent use, and concurrent refreshes are shared.
+type Converter struct {
+	client *Client
+	opts   ConverterOptions
+
+	mu       sync.Mutex
+	table    *RateTable
+	fetch    *rateFetch // Refresh in progress, if any
+	failed   time.Time  // When the last refresh failed
+	fetchErr error      // Why the last refresh failed
+}
+
+// rateFetch is a refresh shared by concurrent callers.
+type rateFetch struct {
+	done  chan struct{}
+	table *RateTable
+	err   error
+}
+
+// NewConverter creates a converter fetching rates through client, in the
+// client's currency as base.
+func NewConverter(client *Client, opts ConverterOptions) *Converter {
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = DefaultRateMaxAge
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = DefaultRateRetryInterval
+	}
+	return &Converter{client: client, opts: opts}
+}
+
+// Rates returns the current rate table, loading or fetching it as needed.
+func (cv *Converter) Rates(ctx context.Context) (*RateTable, error) {
+	cv.mu.Lock()
+	if cv.table == nil && cv.opts.File != "" {
+		table, err := loadRateTable(cv.opts.File)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			cv.mu.Unlock()
+			return nil, err
+		}
+		cv.table = table
+	}
+	if cv.table != nil && time.Since(cv.table.Time) < cv.opts.MaxAge {
+		table := cv.table
+		cv.mu.Unlock()
+		return table, nil
+	}
+	if time.Since(cv.failed) < cv.opts.RetryInterval {
+		table, err := cv.table, cv.fetchErr
+		cv.mu.Unlock()
+		if table != nil {
+			return table, nil
+		}
+		return nil, err
+	}
+	if f := cv.fetch; f != nil {
+		cv.mu.Unlock()
+		select {
+		case <-f.done:
+			return f.table, f.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	f := &rateFetch{done: make(chan struct{})}
+	cv.fetch = f
+	cv.mu.Unlock()
+
+	defer func() {
+		cv.mu.Lock()
+		cv.fetch = nil
+		cv.mu.Unlock()
+		close(f.done)
+	}()
+	f.table, f.err = cv.refresh(ctx)
+	return f.table, f.err
+}
+
+// refresh fetches a new rate table and merges it into the current one. On
+// failure it returns the current table, if any.
+func (cv *Converter) refresh(ctx context.Context) (*RateTable, error) {
+	fetched, err := cv.client.FetchRates(ctx, cv.opts.ReferenceProduct, cv.opts.Currencies...)
+
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	if fetched == nil {
+		// A canceled caller says nothing about whether rates are available.
+		if ctx.Err() == nil {
+			cv.failed, cv.fetchErr = time.Now(), err
+		}
+		if cv.table != nil {
+			return cv.table, nil
+		}
+		return nil, err
+	}
+
+	cv.failed, cv.fetchErr = time.Time{}, nil
+	cv.table = mergeRates(cv.table, fetched)
+	if cv.opts.File != "" {
+		if err := saveRateTable(cv.opts.File, cv.table); err != nil && cv.opts.OnSaveError != nil {
+			cv.opts.OnSaveError(err)
+		}
+	}
+	return cv.table, nil
+}
+
+// mergeRates adds the rates of old missing from fetched to fetched, which
+// is returned. Rates relative to another base are not merged.
+func mergeRates(old, fetched *RateTable) *RateTable {
+	if old == nil || old.Base != fetched.Base {
+		return fetched
+	}
+	for code, rate := range old.Rates {
+		if _, ok := fetched.Rates[code]; ok {
+			continue
+		}
+		if fetched.Updated == nil {
+			fetched.Updated = make(map[string]time.Time)
+		}
+		fetched.Rates[code] = rate
+		fetched.Updated[code] = old.RateTime(code)
+	}
+	return fetched
+}
+
+// Convert converts an amount between currencies.
+func (cv *Converter) Convert(ctx context.Context, amount float64, from, to string) (float64, error) {
+	table, err := cv.Rates(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return table.Convert(amount, from, to)
+}
+
+// ConvertPriceList converts a price list between currencies.
+func (cv *Converter) ConvertPriceList(ctx context.Context, prices []PriceBreak, from, to string) ([]PriceBreak, error) {
+	table, err := cv.Rates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return table.ConvertPriceList(prices, from, to)
+}
+
+// loadRateTable reads a rate table file.
+func loadRateTable(path string) (*RateTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var table RateTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("failed to decode rate table: %w", err)
+	}
+	return &table, nil
+}
+
+// saveRateTable writes a rate table file atomically.
+func saveRateTable(path string, table *RateTable) error {
+	data, err := json.MarshalIndent(table, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode rate table: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".rates-*")
+	if err != nil {
+		return fmt.Errorf("failed to write rate table: %w", err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write rate table: %w", err)
+	}
+	return nil
+}
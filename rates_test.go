@@ -0,0 +1,222 @@
+package lcsc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestFetchRates tests deriving exchange rates from reference product prices.
+func TestFetchRates(t *testing.T) {
+	var requests int32
+	client := newTestClient(t, currencyHandler(t, &requests))
+
+	table, err := client.FetchRates(context.Background(), "", "EUR", "CNY")
+	if err != nil {
+		t.Fatalf("FetchRates failed: %v", err)
+	}
+	if table.Base != "USD" || table.Reference != DefaultRateProduct {
+		t.Errorf("unexpected table: %+v", table)
+	}
+	if table.Rates["EUR"] != 0.9 || table.Rates["CNY"] != 7 {
+		t.Errorf("unexpected rates: %v", table.Rates)
+	}
+
+	rate, err := table.Rate("eur", "CNY")
+	if err != nil || math.Abs(rate-7/0.9) > 1e-9 {
+		t.Errorf("unexpected EUR to CNY rate %v: %v", rate, err)
+	}
+	if _, err := table.Convert(1, "USD", "GBP"); !errors.Is(err, ErrNoRate) {
+		t.Errorf("expected ErrNoRate, got %v", err)
+	}
+
+	prices, err := table.ConvertPriceList([]PriceBreak{{Ladder: 10, ProductPrice: 2, CurrencySymbol: "US$"}}, "USD", "EUR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prices[0].Ladder != 10 || math.Abs(float64(prices[0].ProductPrice)-1.8) > 1e-9 || prices[0].CurrencySymbol != "€" {
+		t.Errorf("unexpected converted price: %+v", prices[0])
+	}
+}
+
+// TestConverterOffline tests rate table persistence and stale fallback.
+func TestConverterOffline(t *testing.T) {
+	var requests int32
+	var offline atomic.Bool
+	rates := currencyHandler(t, &requests)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if offline.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rates(w, r)
+	}
+	client := newTestClient(t, handler)
+	file := filepath.Join(t.TempDir(), "rates.json")
+	ctx := context.Background()
+
+	cv := NewConverter(client, ConverterOptions{Currencies: []string{"EUR"}, File: file})
+	amount, err := cv.Convert(ctx, 10, "USD", "EUR")
+	if err != nil || math.Abs(amount-9) > 1e-9 {
+		t.Fatalf("unexpected conversion %v: %v", amount, err)
+	}
+	if _, err := cv.Convert(ctx, 10, "USD", "EUR"); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Errorf("expected rates to be fetched once, got %d requests", requests)
+	}
+
+	// A new converter with expired rates falls back to the saved table.
+	offline.Store(true)
+	cv = NewConverter(client, ConverterOptions{Currencies: []string{"EUR"}, File: file, MaxAge: time.Nanosecond})
+	amount, err = cv.Convert(ctx, 10, "EUR", "USD")
+	if err != nil || math.Abs(amount-10/0.9) > 1e-9 {
+		t.Errorf("expected conversion from saved rates, got %v: %v", amount, err)
+	}
+
+	cv = NewConverter(client, ConverterOptions{Currencies: []string{"EUR"}})
+	if _, err := cv.Rates(ctx); err == nil {
+		t.Error("expected error without rates")
+	}
+}
+
+// TestConverterSaveError tests that fetched rates are used when the rate
+// file cannot be written.
+func TestConverterSaveError(t *testing.T) {
+	var requests int32
+	client := newTestClient(t, currencyHandler(t, &requests))
+	file := filepath.Join(t.TempDir(), "missing", "rates.json")
+
+	var saveErr error
+	cv := NewConverter(client, ConverterOptions{
+		Currencies:  []string{"EUR"},
+		File:        file,
+		OnSaveError: func(err error) { saveErr = err },
+	})
+	amount, err := cv.Convert(context.Background(), 10, "USD", "EUR")
+	if err != nil || math.Abs(amount-9) > 1e-9 {
+		t.Fatalf("unexpected conversion %v: %v", amount, err)
+	}
+	if saveErr == nil {
+		t.Error("expected the save error to be reported")
+	}
+	if _, err := cv.Rates(context.Background()); err != nil || requests != 2 {
+		t.Errorf("expected the fetched table to be kept, got %v after %d requests", err, requests)
+	}
+}
+
+// TestConverterRetryInterval tests that concurrent refreshes are shared and
+// failed refreshes are not retried before RetryInterval.
+func TestConverterRetryInterval(t *testing.T) {
+	var requests, attempts int32
+	var offline atomic.Bool
+	rates := currencyHandler(t, &requests)
+	release := make(chan struct{})
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		<-release
+		if offline.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rates(w, r)
+	}
+	client := newTestClient(t, handler)
+	ctx := context.Background()
+
+	// An expired saved table is refreshed once for all callers.
+	file := filepath.Join(t.TempDir(), "rates.json")
+	expired := &RateTable{Base: "USD", Rates: map[string]float64{"USD": 1, "EUR": 0.8}, Time: time.Now().Add(-48 * time.Hour)}
+	if err := saveRateTable(file, expired); err != nil {
+		t.Fatal(err)
+	}
+	opts := ConverterOptions{Currencies: []string{"EUR"}, File: file, RetryInterval: time.Hour}
+	cv := NewConverter(client, opts)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if amount, err := cv.Convert(ctx, 10, "USD", "EUR"); err != nil || math.Abs(amount-9) > 1e-9 {
+				t.Errorf("unexpected conversion %v: %v", amount, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := atomic.LoadInt32(&attempts); n != 2 {
+		t.Errorf("expected one shared fetch of 2 requests, got %d", n)
+	}
+
+	// Offline, the stale table is used without refetching on every call.
+	if err := saveRateTable(file, expired); err != nil {
+		t.Fatal(err)
+	}
+	offline.Store(true)
+	cv = NewConverter(client, opts)
+	for i := 0; i < 3; i++ {
+		amount, err := cv.Convert(ctx, 10, "USD", "EUR")
+		if err != nil || math.Abs(amount-8) > 1e-9 {
+			t.Errorf("expected conversion from stale rates, got %v: %v", amount, err)
+		}
+	}
+	if n := atomic.LoadInt32(&attempts); n != 4 {
+		t.Errorf("expected one failed fetch of 2 requests, got %d", n-2)
+	}
+
+	// Without any table, the failure is reported until the interval passes.
+	cv = NewConverter(client, ConverterOptions{Currencies: []string{"EUR"}, RetryInterval: time.Hour})
+	for i := 0; i < 2; i++ {
+		if _, err := cv.Rates(ctx); err == nil {
+			t.Error("expected error without rates")
+		}
+	}
+	if n := atomic.LoadInt32(&attempts); n != 6 {
+		t.Errorf("expected one more failed fetch, got %d requests", n-4)
+	}
+}
+
+// TestConverterPartialRefresh tests keeping rates a refresh failed to fetch.
+func TestConverterPartialRefresh(t *testing.T) {
+	var requests int32
+	var cnyDown atomic.Bool
+	rates := currencyHandler(t, &requests)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if cookie, _ := r.Cookie("currencyCode"); cnyDown.Load() && cookie != nil && cookie.Value == "CNY" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rates(w, r)
+	}
+	client := newTestClient(t, handler)
+	ctx := context.Background()
+	cv := NewConverter(client, ConverterOptions{Currencies: []string{"EUR", "CNY"}, MaxAge: time.Nanosecond})
+
+	first, err := cv.Rates(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cnyDown.Store(true)
+	time.Sleep(time.Millisecond)
+	table, err := cv.Rates(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table == first || !table.Time.After(first.Time) {
+		t.Fatalf("expected a refreshed table, got %+v", table)
+	}
+	if amount, err := table.Convert(1, "USD", "CNY"); err != nil || amount != 7 {
+		t.Errorf("expected the old CNY rate to be kept, got %v: %v", amount, err)
+	}
+	if !table.RateTime("cny").Equal(first.Time) || !table.RateTime("EUR").Equal(table.Time) {
+		t.Errorf("unexpected rate times: %v", table.Updated)
+	}
+}
@@ -0,0 +1,167 @@
+package lcsc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MatchReason describes how a candidate was matched to a manufacturer part number.
+type MatchReason string
+
+const (
+	MatchDirect     MatchReason = "direct"     // LCSC reported a direct match for the query
+	MatchExact      MatchReason = "exact"      // ProductModel equals the MPN (case-insensitive)
+	MatchNormalized MatchReason = "normalized" // MPNs are equal after normalization
+	MatchPrefix     MatchReason = "prefix"     // One normalized MPN is a prefix of the other
+	MatchPartial    MatchReason = "partial"    // One normalized MPN contains the other
+)
+
+// Confidence scores for each MPN match type before manufacturer adjustment.
+const (
+	confidenceExact      = 1.0
+	confidenceDirect     = 0.95
+	confidenceNormalized = 0.9
+	confidencePrefix     = 0.6
+	confidencePartial    = 0.4
+
+	// Multipliers applied when a manufacturer was supplied.
+	manufacturerMismatchFactor = 0.5
+	manufacturerUnknownFactor  = 0.8
+)
+
+// packagingSuffixes are ordering/packaging suffixes that do not change the
+// part itself. They are stripped, repeatedly, from the end of an MPN during
+// normalization. Longer suffixes must come before their own suffixes.
+var packagingSuffixes = []string{
+	"#TRPBF", "-TRPBF", "#PBF", "-PBF", "/PBF",
+	"-REEL7", "-REEL13", "-REEL", "/REEL", "-RL7", "-RL",
+	"#TR", "-TR", "/TR", "-T&R", "-CT", "/CT",
+	"-ND", ",215", ",115", ",135", ",235",
+}
+
+// MPNCandidate is a product that may correspond to a requested MPN.
+type MPNCandidate struct {
+	Product             Product
+	Confidence          float64     // 0 to 1; higher is a better match
+	Reason              MatchReason // How the MPN matched
+	ManufacturerMatched bool        // Whether BrandNameEn matched the requested manufacturer
+}
+
+// NormalizeMPN returns a canonical form of a manufacturer part number for
+// comparison: upper case, without whitespace and without packaging suffixes
+// such as "-TR" or "#PBF".
+func NormalizeMPN(mpn string) string {
+	mpn = strings.ToUpper(strings.Join(strings.Fields(mpn), ""))
+	for {
+		trimmed := mpn
+		for _, suffix := range packagingSuffixes {
+			if len(trimmed) > len(suffix) && strings.HasSuffix(trimmed, suffix) {
+				trimmed = strings.TrimSuffix(trimmed, suffix)
+				break
+			}
+		}
+		if trimmed == mpn {
+			return mpn
+		}
+		mpn = trimmed
+	}
+}
+
+// ResolveMPN finds LCSC products matching a manufacturer part number.
+// The manufacturer is optional; when given, candidates from other
+// manufacturers are kept but scored lower. Candidates are returned best
+// first. An empty slice means nothing plausible was found.
+func (c *Client) ResolveMPN(ctx context.Context, mpn, manufacturer string) ([]MPNCandidate, error) {
+	mpn = strings.TrimSpace(mpn)
+	if mpn == "" {
+		return nil, fmt.Errorf("mpn is required")
+	}
+	manufacturer = strings.TrimSpace(manufacturer)
+
+	resp, err := c.KeywordSearch(ctx, SearchRequest{Keyword: mpn})
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := NormalizeMPN(mpn)
+	if len(resp.Products) == 0 && resp.DirectMatchCode == "" && normalized != strings.ToUpper(mpn) {
+		resp, err = c.KeywordSearch(ctx, SearchRequest{Keyword: normalized})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	products := resp.Products
+	if resp.DirectMatchCode != "" && !containsProductCode(products, resp.DirectMatchCode) {
+		product, err := c.GetProductDetails(ctx, resp.DirectMatchCode)
+		if err != nil {
+			return nil, err
+		}
+		products = append([]Product{*product}, products...)
+	}
+
+	candidates := make([]MPNCandidate, 0, len(products))
+	for _, p := range products {
+		candidate, ok := scoreMPNCandidate(p, mpn, manufacturer, p.ProductCode == resp.DirectMatchCode)
+		if ok {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Confidence != candidates[j].Confidence {
+			return candidates[i].Confidence > candidates[j].Confidence
+		}
+		return candidates[i].Product.StockNumber > candidates[j].Product.StockNumber
+	})
+
+	return candidates, nil
+}
+
+// scoreMPNCandidate scores p against the requested MPN and manufacturer.
+// It reports false when the product does not match the MPN at all.
+func scoreMPNCandidate(p Product, mpn, manufacturer string, direct bool) (MPNCandidate, bool) {
+	candidate := MPNCandidate{Product: p}
+
+	model := strings.TrimSpace(p.ProductModel)
+	normModel, normMPN := NormalizeMPN(model), NormalizeMPN(mpn)
+	switch {
+	case model != "" && strings.EqualFold(model, mpn):
+		candidate.Confidence, candidate.Reason = confidenceExact, MatchExact
+	case direct:
+		candidate.Confidence, candidate.Reason = confidenceDirect, MatchDirect
+	case normModel != "" && normModel == normMPN:
+		candidate.Confidence, candidate.Reason = confidenceNormalized, MatchNormalized
+	case normModel != "" && (strings.HasPrefix(normModel, normMPN) || strings.HasPrefix(normMPN, normModel)):
+		candidate.Confidence, candidate.Reason = confidencePrefix, MatchPrefix
+	case normModel != "" && (strings.Contains(normModel, normMPN) || strings.Contains(normMPN, normModel)):
+		candidate.Confidence, candidate.Reason = confidencePartial, MatchPartial
+	default:
+		return candidate, false
+	}
+
+	if manufacturer != "" {
+		switch {
+		case ManufacturersMatch(manufacturer, p.BrandNameEn):
+			candidate.ManufacturerMatched = true
+		case strings.TrimSpace(p.BrandNameEn) == "":
+			candidate.Confidence *= manufacturerUnknownFactor
+		default:
+			candidate.Confidence *= manufacturerMismatchFactor
+		}
+	}
+
+	return candidate, true
+}
+
+// containsProductCode reports whether products contains the given code.
+func containsProductCode(products []Product, code string) bool {
+	for _, p := range products {
+		if p.ProductCode == code {
+			return true
+		}
+	}
+	return false
+}
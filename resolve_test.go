@@ -0,0 +1,117 @@
+package lcsc
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+// TestNormalizeMPN tests MPN normalization.
+func TestNormalizeMPN(t *testing.T) {
+	tests := []struct {
+		mpn      string
+		expected string
+	}{
+		{"lm358dr", "LM358DR"},
+		{" NE555P ", "NE555P"},
+		{"LT1763CS8#PBF", "LT1763CS8"},
+		{"LT1763CS8#TRPBF", "LT1763CS8"},
+		{"AD8605ARTZ-REEL7", "AD8605ARTZ"},
+		{"BSS138-TR", "BSS138"},
+		{"IRLML6344TRPBF-TR", "IRLML6344TRPBF"},
+		{"STM32 F103", "STM32F103"},
+		{"-TR", "-TR"},
+	}
+
+	for _, test := range tests {
+		if got := NormalizeMPN(test.mpn); got != test.expected {
+			t.Errorf("NormalizeMPN(%q) = %q, expected %q", test.mpn, got, test.expected)
+		}
+	}
+}
+
+// TestResolveMPNRanking tests that candidates are ranked by match quality.
+func TestResolveMPNRanking(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeAPIResult(t, w, map[string]interface{}{
+			"productSearchResultVO": map[string]interface{}{
+				"productList": []Product{
+					{ProductCode: "C3", ProductModel: "LM358DR2G", BrandNameEn: "onsemi"},
+					{ProductCode: "C2", ProductModel: "LM358DR-TR", BrandNameEn: "TI(Texas Instruments)"},
+					{ProductCode: "C1", ProductModel: "LM358DR", BrandNameEn: "TI(Texas Instruments)"},
+					{ProductCode: "C4", ProductModel: "NE555", BrandNameEn: "TI(Texas Instruments)"},
+				},
+				"totalCount": 4,
+			},
+		})
+	})
+
+	candidates, err := client.ResolveMPN(context.Background(), "lm358dr", "Texas Instruments")
+	if err != nil {
+		t.Fatalf("ResolveMPN failed: %v", err)
+	}
+
+	if len(candidates) != 3 {
+		t.Fatalf("expected 3 candidates, got %d", len(candidates))
+	}
+
+	expected := []struct {
+		code   string
+		reason MatchReason
+	}{
+		{"C1", MatchExact},
+		{"C2", MatchNormalized},
+		{"C3", MatchPrefix},
+	}
+	for i, e := range expected {
+		if candidates[i].Product.ProductCode != e.code || candidates[i].Reason != e.reason {
+			t.Errorf("candidate %d: expected %s/%s, got %s/%s", i, e.code, e.reason,
+				candidates[i].Product.ProductCode, candidates[i].Reason)
+		}
+	}
+
+	if !candidates[0].ManufacturerMatched || candidates[2].ManufacturerMatched {
+		t.Errorf("unexpected manufacturer match flags: %+v", candidates)
+	}
+	if candidates[2].Confidence >= confidencePrefix {
+		t.Errorf("expected manufacturer mismatch to lower confidence, got %f", candidates[2].Confidence)
+	}
+}
+
+// TestResolveMPNDirectMatch tests that a direct match not in the result list is fetched.
+func TestResolveMPNDirectMatch(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search/v2/global":
+			writeAPIResult(t, w, map[string]interface{}{
+				"productSearchResultVO": map[string]interface{}{"productList": []Product{}},
+				"tipProductDetailUrlVO": map[string]string{"productCode": "C8734"},
+			})
+		case "/product/detail":
+			writeAPIResult(t, w, Product{ProductCode: "C8734", ProductModel: "STM32F103C8T6", BrandNameEn: "ST"})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	candidates, err := client.ResolveMPN(context.Background(), "STM32F103C8T6TR", "")
+	if err != nil {
+		t.Fatalf("ResolveMPN failed: %v", err)
+	}
+
+	if len(candidates) != 1 {
+		t.Fatalf("expected 1 candidate, got %d", len(candidates))
+	}
+	if candidates[0].Reason != MatchDirect || candidates[0].Confidence != confidenceDirect {
+		t.Errorf("expected direct match, got %+v", candidates[0])
+	}
+}
+
+// TestResolveMPNEmpty tests that an empty MPN is rejected.
+func TestResolveMPNEmpty(t *testing.T) {
+	client := NewClient()
+
+	if _, err := client.ResolveMPN(context.Background(), "  ", "TI"); err == nil {
+		t.Fatal("expected error for empty MPN")
+	}
+}
@@ -0,0 +1,207 @@
+package lcsc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// DefaultBootstrapURL is the page loaded to obtain LCSC session cookies.
+const DefaultBootstrapURL = "https://www.lcsc.com/"
+
+// SessionOptions configures cookie session handling.
+type SessionOptions struct {
+	// Jar stores server-issued cookies (default: a new in-memory jar).
+	Jar http.CookieJar
+	// BootstrapURL is a page loaded before the first request and when the
+	// session is refreshed, to obtain session cookies, like
+	// DefaultBootstrapURL. Empty skips the bootstrap request.
+	BootstrapURL string
+}
+
+// session keeps cookies across requests and refreshes them when the
+// server blocks a request.
+type session struct {
+	jar          http.CookieJar
+	bootstrapURL string
+
+	mu           sync.Mutex
+	bootstrapped bool
+	generation   int // Incremented on every refresh
+}
+
+// WithSession keeps cookies issued by the server in a cookie jar and sends
+// them with every request. Requests to the base URL host also carry the
+// client's own cookies, such as currencyCode and the language, which take
+// precedence. If a request is blocked (HTTP 403, or an HTML page where JSON
+// was expected), the session is refreshed by repeating the bootstrap request
+// and the request is retried once.
+func WithSession(opts SessionOptions) ClientOption {
+	return func(c *Client) {
+		jar := opts.Jar
+		if jar == nil {
+			// cookiejar.New only fails for invalid options.
+			jar, _ = cookiejar.New(nil)
+		}
+		c.session = &session{jar: jar, bootstrapURL: opts.BootstrapURL}
+	}
+}
+
+// CookieJar returns the session cookie jar, or nil without WithSession.
+func (c *Client) CookieJar() http.CookieJar {
+	if c.session == nil {
+		return nil
+	}
+	return c.session.jar
+}
+
+// RefreshSession repeats the session bootstrap request. It does nothing
+// without WithSession or a bootstrap URL.
+func (c *Client) RefreshSession(ctx context.Context) error {
+	if c.session == nil {
+		return nil
+	}
+	c.session.mu.Lock()
+	defer c.session.mu.Unlock()
+	return c.session.bootstrap(ctx, c)
+}
+
+// clientCookies returns the cookies the client sets itself.
+func (c *Client) clientCookies() []*http.Cookie {
+	cookies := []*http.Cookie{{Name: "currencyCode", Value: c.currency}}
+	if c.language != "" {
+		cookies = append(cookies, &http.Cookie{Name: languageCookie, Value: string(c.language)})
+	}
+	return cookies
+}
+
+// isBaseHost reports whether u is on the host of the client's base URL.
+func (c *Client) isBaseHost(u *url.URL) bool {
+	base, err := url.Parse(c.baseURL)
+	return err == nil && strings.EqualFold(u.Host, base.Host)
+}
+
+// setCookies sets the Cookie header of req from, with a session, the jar's
+// cookies for the request URL and, for requests to the base URL host, the
+// client's cookies. Other hosts, like EasyEDA or download hosts, only get
+// cookies from the jar.
+func (c *Client) setCookies(req *http.Request) {
+	var own []*http.Cookie
+	if c.isBaseHost(req.URL) {
+		own = c.clientCookies()
+	}
+	names := make(map[string]bool, len(own))
+	for _, ck := range own {
+		names[ck.Name] = true
+	}
+
+	var parts []string
+	if c.session != nil {
+		for _, ck := range c.session.jar.Cookies(req.URL) {
+			if !names[ck.Name] {
+				parts = append(parts, ck.Name+"="+ck.Value)
+			}
+		}
+	}
+	for _, ck := range own {
+		parts = append(parts, ck.Name+"="+ck.Value)
+	}
+	if len(parts) > 0 {
+		req.Header.Set("Cookie", strings.Join(parts, "; "))
+	}
+}
+
+// storeCookies saves cookies set by resp in the session jar.
+func (c *Client) storeCookies(resp *http.Response) {
+	if c.session == nil || resp.Request == nil {
+		return
+	}
+	if cookies := resp.Cookies(); len(cookies) > 0 {
+		c.session.jar.SetCookies(resp.Request.URL, cookies)
+	}
+}
+
+// sessionRequest runs send, bootstrapping the session first if needed, and
+// refreshes the session and runs send once more, after waiting on the rate
+// limiter, if the request was blocked.
+func (c *Client) sessionRequest(ctx context.Context, send func() (*http.Response, int, error)) (*http.Response, int, error) {
+	s := c.session
+	s.mu.Lock()
+	if !s.bootstrapped {
+		if err := s.bootstrap(ctx, c); err != nil {
+			s.mu.Unlock()
+			return nil, 0, err
+		}
+	}
+	generation := s.generation
+	s.mu.Unlock()
+
+	resp, statusCode, err := send()
+	if !errors.Is(err, ErrBlocked) {
+		return resp, statusCode, err
+	}
+
+	s.mu.Lock()
+	// Another request may have refreshed the session in the meantime.
+	if s.generation == generation {
+		if err := s.bootstrap(ctx, c); err != nil {
+			s.mu.Unlock()
+			return nil, statusCode, err
+		}
+	}
+	s.mu.Unlock()
+
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return nil, statusCode, fmt.Errorf("rate limiter: %w", err)
+	}
+	return send()
+}
+
+// bootstrap loads the bootstrap page to obtain session cookies. A failed
+// bootstrap is repeated before the next request. The caller must hold s.mu.
+func (s *session) bootstrap(ctx context.Context, c *Client) error {
+	s.generation++
+	if s.bootstrapURL == "" {
+		s.bootstrapped = true
+		return nil
+	}
+
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limiter: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.bootstrapURL, nil)
+	if err != nil {
+		return fmt.Errorf("session bootstrap: %w", err)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,*/*;q=0.8")
+	req.Header.Set("User-Agent", userAgent)
+	for _, ck := range c.clientCookies() {
+		req.AddCookie(ck)
+	}
+
+	// Let the HTTP client manage the jar here so cookies set on redirects
+	// are kept.
+	httpClient := *c.httpClient
+	httpClient.Jar = s.jar
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("session bootstrap: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("session bootstrap: unexpected status code: %d", resp.StatusCode)
+	}
+	s.bootstrapped = true
+	return nil
+}
@@ -0,0 +1,275 @@
+package lcsc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// sessionServer is a stand-in for LCSC that issues a session cookie from
+// its home page and blocks API requests without the current session.
+type sessionServer struct {
+	mu         sync.Mutex
+	session    string // Current valid session; none if empty
+	bootstraps int
+	cookies    []string // Cookie headers of API requests
+	challenge  bool     // Serve blocked requests an HTML page instead of 403
+}
+
+func (s *sessionServer) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		switch r.URL.Path {
+		case "/":
+			s.bootstraps++
+			http.Redirect(w, r, "/home", http.StatusFound)
+		case "/home":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: s.session, Path: "/"})
+			http.SetCookie(w, &http.Cookie{Name: "currencyCode", Value: "CNY", Path: "/"})
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html></html>"))
+		default:
+			s.cookies = append(s.cookies, r.Header.Get("Cookie"))
+			if c, err := r.Cookie("session"); err != nil || s.session == "" || c.Value != s.session {
+				if s.challenge {
+					w.Header().Set("Content-Type", "text/html; charset=utf-8")
+					_, _ = w.Write([]byte("<html>checking your browser</html>"))
+				} else {
+					w.WriteHeader(http.StatusForbidden)
+				}
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: "visit", Value: "1", Path: "/"})
+			writeAPIResult(t, w, Product{ProductCode: r.URL.Query().Get("productCode")})
+		}
+	}
+}
+
+func (s *sessionServer) rotate(session string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.session = session
+}
+
+// newSessionClient returns a client with a session bootstrapped from the
+// server's home page.
+func newSessionClient(t *testing.T, s *sessionServer, opts ...ClientOption) *Client {
+	t.Helper()
+	server := httptest.NewServer(s.handler(t))
+	t.Cleanup(server.Close)
+
+	opts = append([]ClientOption{
+		WithBaseURL(server.URL),
+		WithRetryConfig(NoRetry()),
+		WithRateLimit(1000),
+		WithSession(SessionOptions{BootstrapURL: server.URL + "/"}),
+	}, opts...)
+	return NewClient(opts...)
+}
+
+// TestSessionBootstrap tests session cookies from the bootstrap page.
+func TestSessionBootstrap(t *testing.T) {
+	s := &sessionServer{session: "abc"}
+	client := newSessionClient(t, s, WithCurrency("EUR"))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetProductDetails(context.Background(), "C1"); err != nil {
+			t.Fatalf("GetProductDetails failed: %v", err)
+		}
+	}
+
+	if s.bootstraps != 1 {
+		t.Errorf("expected 1 bootstrap, got %d", s.bootstraps)
+	}
+	if len(s.cookies) != 2 {
+		t.Fatalf("expected 2 API requests, got %d", len(s.cookies))
+	}
+	// The server's currencyCode is overridden by the client's.
+	if got := s.cookies[0]; got != "session=abc; currencyCode=EUR" {
+		t.Errorf("unexpected first Cookie header: %q", got)
+	}
+	// Cookies set by API responses are sent back.
+	if got := s.cookies[1]; !strings.Contains(got, "visit=1") || strings.Count(got, "currencyCode") != 1 {
+		t.Errorf("unexpected second Cookie header: %q", got)
+	}
+}
+
+// TestSessionRefresh tests refreshing the session when requests are blocked.
+func TestSessionRefresh(t *testing.T) {
+	for _, challenge := range []bool{false, true} {
+		s := &sessionServer{session: "abc", challenge: challenge}
+		client := newSessionClient(t, s)
+
+		if _, err := client.GetProductDetails(context.Background(), "C1"); err != nil {
+			t.Fatalf("GetProductDetails failed: %v", err)
+		}
+		s.rotate("def")
+		if _, err := client.GetProductDetails(context.Background(), "C1"); err != nil {
+			t.Fatalf("GetProductDetails after session rotation failed (challenge %v): %v", challenge, err)
+		}
+
+		if s.bootstraps != 2 || len(s.cookies) != 3 {
+			t.Errorf("expected 2 bootstraps and 3 API requests, got %d and %d", s.bootstraps, len(s.cookies))
+		}
+	}
+}
+
+// TestSessionDownload tests that downloads share the session and refresh it
+// when blocked.
+func TestSessionDownload(t *testing.T) {
+	s := &sessionServer{session: "abc"}
+	client := newSessionClient(t, s)
+
+	if _, err := client.GetProductDetails(context.Background(), "C1"); err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+	s.rotate("def")
+	var buf strings.Builder
+	if _, err := client.Download(context.Background(), client.baseURL+"/file", &buf, DownloadOptions{}); err != nil {
+		t.Fatalf("Download after session rotation failed: %v", err)
+	}
+
+	if s.bootstraps != 2 || len(s.cookies) != 3 {
+		t.Errorf("expected 2 bootstraps and 3 requests, got %d and %d", s.bootstraps, len(s.cookies))
+	}
+	if !strings.Contains(s.cookies[2], "session=def") {
+		t.Errorf("download sent stale session: %q", s.cookies[2])
+	}
+}
+
+// TestSessionRefreshOnce tests that a blocked request is retried only once.
+func TestSessionRefreshOnce(t *testing.T) {
+	s := &sessionServer{session: "abc"}
+	client := newSessionClient(t, s)
+
+	// Bootstraps issue sessions the API does not accept.
+	s.rotate("")
+	_, err := client.GetProductDetails(context.Background(), "C1")
+	if !errors.Is(err, ErrBlocked) {
+		t.Errorf("expected ErrBlocked, got %v", err)
+	}
+	if s.bootstraps != 2 || len(s.cookies) != 2 {
+		t.Errorf("expected 2 bootstraps and 2 API requests, got %d and %d", s.bootstraps, len(s.cookies))
+	}
+}
+
+// TestSessionWithoutBootstrap tests a session without a bootstrap URL.
+func TestSessionWithoutBootstrap(t *testing.T) {
+	var mu sync.Mutex
+	var requests int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		// The first response blocks the request but issues a cookie.
+		if _, err := r.Cookie("token"); err != nil {
+			http.SetCookie(w, &http.Cookie{Name: "token", Value: "t", Path: "/"})
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		writeAPIResult(t, w, Product{ProductCode: "C1"})
+	}, WithSession(SessionOptions{}))
+
+	if _, err := client.GetProductDetails(context.Background(), "C1"); err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+	if client.CookieJar() == nil {
+		t.Error("expected a cookie jar")
+	}
+}
+
+// TestSessionResendRateLimit tests that the resend after a refresh waits on
+// the rate limiter.
+func TestSessionResendRateLimit(t *testing.T) {
+	s := &sessionServer{session: "abc"}
+	client := newSessionClient(t, s, WithRateLimit(10))
+
+	if _, err := client.GetProductDetails(context.Background(), "C1"); err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+	s.rotate("def")
+	if _, err := client.GetProductDetails(context.Background(), "C1"); err != nil {
+		t.Fatalf("GetProductDetails after session rotation failed: %v", err)
+	}
+
+	// 2 bootstraps and 3 API requests each take a token.
+	client.rateLimiter.mu.Lock()
+	tokens := client.rateLimiter.tokens
+	client.rateLimiter.mu.Unlock()
+	if tokens > 5.5 {
+		t.Errorf("expected 5 tokens taken, have %.1f left of 10", tokens)
+	}
+}
+
+// TestNoSessionHTML tests that clients without a session do not treat HTML
+// responses as blocks.
+func TestNoSessionHTML(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html></html>"))
+	})
+
+	_, err := client.GetProductDetails(context.Background(), "C1")
+	if err == nil || errors.Is(err, ErrBlocked) {
+		t.Errorf("expected a non-block error, got %v", err)
+	}
+}
+
+// TestNoSession tests that clients without a session send only their own cookies.
+func TestNoSession(t *testing.T) {
+	var cookie string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		cookie = r.Header.Get("Cookie")
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+		writeAPIResult(t, w, Product{ProductCode: "C1"})
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetProductDetails(context.Background(), "C1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cookie != "currencyCode=USD" {
+		t.Errorf("unexpected Cookie header: %q", cookie)
+	}
+	if client.CookieJar() != nil || client.RefreshSession(context.Background()) != nil {
+		t.Error("expected no session")
+	}
+}
+
+// TestClientCookiesOtherHosts tests that the client's cookies and language
+// are only sent to the base URL host.
+func TestClientCookiesOtherHosts(t *testing.T) {
+	var cookie, acceptLanguage string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie = r.Header.Get("Cookie")
+		acceptLanguage = r.Header.Get("Accept-Language")
+		http.SetCookie(w, &http.Cookie{Name: "cdn", Value: "1", Path: "/"})
+		w.Header().Set("Content-Type", "application/pdf")
+		_, _ = w.Write(testPDF)
+	}))
+	t.Cleanup(other.Close)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeAPIResult(t, w, Product{ProductCode: "C1"})
+	}, WithLanguage(LanguageChinese), WithSession(SessionOptions{}))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Download(context.Background(), other.URL+"/ds.pdf", io.Discard, DownloadOptions{}); err != nil {
+			t.Fatalf("Download failed: %v", err)
+		}
+	}
+	// The second request carries the host's own cookie from the jar only.
+	if cookie != "cdn=1" || acceptLanguage != "" {
+		t.Errorf("unexpected Cookie %q and Accept-Language %q for another host", cookie, acceptLanguage)
+	}
+}
@@ -0,0 +1,177 @@
+package lcsc
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultStreamWorkers is the number of concurrent lookups of a stream.
+const DefaultStreamWorkers = 4
+
+// StreamOptions configures StreamProducts.
+type StreamOptions struct {
+	Workers int  // Concurrent lookups (default: DefaultStreamWorkers)
+	Ordered bool // Emit results in input order instead of as they complete
+}
+
+// StreamResult is the outcome of looking up one product code.
+type StreamResult struct {
+	Index   int    // Position of the code in the input, starting at 0
+	Code    string // Product code as given
+	Product *Product
+	Err     error
+}
+
+// StreamProducts looks up the product codes received from codes with a
+// pool of workers and sends a result for each on the returned channel. The
+// workers share the client's rate limiter and cache, so a stream never
+// exceeds the client's request rate however many workers it has.
+//
+// The returned channel is unbuffered: workers wait while the consumer is
+// busy, so a slow consumer slows the stream down rather than piling up
+// results. In ordered mode at most twice Workers codes are read ahead of
+// the next result to emit.
+//
+// The channel is closed once codes is closed and all results are sent, or
+// when ctx is done; results not yet sent by then are dropped, so check
+// ctx.Err() after the channel closes. A consumer that stops reading early
+// must cancel ctx to release the workers.
+func (c *Client) StreamProducts(ctx context.Context, codes <-chan string, opts StreamOptions) <-chan StreamResult {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = DefaultStreamWorkers
+	}
+
+	type job struct {
+		index int
+		code  string
+	}
+	jobs := make(chan job)
+	out := make(chan StreamResult)
+	results := out
+
+	// In ordered mode, window limits the codes read ahead of the next
+	// result to emit, so one slow lookup cannot make the reorder buffer
+	// grow without bound.
+	var window chan struct{}
+	if opts.Ordered {
+		results = make(chan StreamResult)
+		window = make(chan struct{}, 2*workers)
+	}
+
+	go func() {
+		defer close(jobs)
+		for index := 0; ; index++ {
+			var (
+				code string
+				ok   bool
+			)
+			select {
+			case <-ctx.Done():
+				return
+			case code, ok = <-codes:
+				if !ok {
+					return
+				}
+			}
+			if window != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case window <- struct{}{}:
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- job{index: index, code: code}:
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				product, err := c.GetProductDetails(ctx, j.code)
+				if ctx.Err() != nil {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case results <- StreamResult{Index: j.index, Code: j.code, Product: product, Err: err}:
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	if opts.Ordered {
+		go func() {
+			defer close(out)
+			pending := make(map[int]StreamResult)
+			next := 0
+			for r := range results {
+				pending[r.Index] = r
+				for {
+					r, ok := pending[next]
+					if !ok {
+						break
+					}
+					select {
+					case <-ctx.Done():
+						return
+					case out <- r:
+					}
+					delete(pending, next)
+					<-window
+					next++
+				}
+			}
+		}()
+	}
+
+	return out
+}
+
+// StreamProductList streams the lookups of a list of product codes. See
+// StreamProducts.
+func (c *Client) StreamProductList(ctx context.Context, codes []string, opts StreamOptions) <-chan StreamResult {
+	return c.StreamProductsFunc(ctx, func() (string, bool) {
+		if len(codes) == 0 {
+			return "", false
+		}
+		code := codes[0]
+		codes = codes[1:]
+		return code, true
+	}, opts)
+}
+
+// StreamProductsFunc streams the lookups of the product codes returned by
+// next until it reports false. next is called from a single goroutine, only
+// as codes are needed, so it may read from a file or database cursor
+// lazily. See StreamProducts.
+func (c *Client) StreamProductsFunc(ctx context.Context, next func() (string, bool), opts StreamOptions) <-chan StreamResult {
+	codes := make(chan string)
+	go func() {
+		defer close(codes)
+		for {
+			code, ok := next()
+			if !ok {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case codes <- code:
+			}
+		}
+	}()
+	return c.StreamProducts(ctx, codes, opts)
+}
@@ -0,0 +1,139 @@
+package lcsc
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// streamHandler serves products C1..Cn, answering lower codes more slowly
+// and tracking the peak number of concurrent requests. C0 is not found.
+func streamHandler(t *testing.T, active, peak *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(active, 1)
+		defer atomic.AddInt32(active, -1)
+		for {
+			p := atomic.LoadInt32(peak)
+			if n <= p || atomic.CompareAndSwapInt32(peak, p, n) {
+				break
+			}
+		}
+
+		code := r.URL.Query().Get("productCode")
+		num, _ := strconv.Atoi(strings.TrimPrefix(code, "C"))
+		if num == 0 {
+			writeAPIError(w, 404, "not found")
+			return
+		}
+		time.Sleep(time.Duration(20-num%20) * time.Millisecond)
+		writeAPIResult(t, w, Product{ProductCode: code})
+	}
+}
+
+// writeAPIError writes an LCSC-style error envelope.
+func writeAPIError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"code":` + strconv.Itoa(code) + `,"msg":"` + msg + `"}`))
+}
+
+// streamCodes returns C0..C(n-1).
+func streamCodes(n int) []string {
+	codes := make([]string, n)
+	for i := range codes {
+		codes[i] = "C" + strconv.Itoa(i)
+	}
+	return codes
+}
+
+// TestStreamProductsUnordered tests that every code yields one result.
+func TestStreamProductsUnordered(t *testing.T) {
+	var active, peak int32
+	client := newTestClient(t, streamHandler(t, &active, &peak))
+
+	seen := make(map[string]bool)
+	for r := range client.StreamProductList(context.Background(), streamCodes(20), StreamOptions{Workers: 3}) {
+		if seen[r.Code] {
+			t.Errorf("duplicate result for %s", r.Code)
+		}
+		seen[r.Code] = true
+		if r.Code == "C0" {
+			if r.Err == nil {
+				t.Error("expected an error for C0")
+			}
+			continue
+		}
+		if r.Err != nil || r.Product == nil || r.Product.ProductCode != r.Code || r.Code != "C"+strconv.Itoa(r.Index) {
+			t.Errorf("unexpected result: %+v", r)
+		}
+	}
+	if len(seen) != 20 {
+		t.Errorf("expected 20 results, got %d", len(seen))
+	}
+	if peak > 3 {
+		t.Errorf("expected at most 3 concurrent requests, got %d", peak)
+	}
+}
+
+// TestStreamProductsOrdered tests that ordered mode keeps input order.
+func TestStreamProductsOrdered(t *testing.T) {
+	var active, peak int32
+	client := newTestClient(t, streamHandler(t, &active, &peak))
+
+	codes := make(chan string)
+	go func() {
+		defer close(codes)
+		for _, code := range streamCodes(25) {
+			codes <- code
+		}
+	}()
+
+	index := 0
+	for r := range client.StreamProducts(context.Background(), codes, StreamOptions{Workers: 5, Ordered: true}) {
+		if r.Index != index {
+			t.Fatalf("expected index %d, got %d", index, r.Index)
+		}
+		index++
+	}
+	if index != 25 {
+		t.Errorf("expected 25 results, got %d", index)
+	}
+}
+
+// TestStreamProductsCancel tests that cancellation closes the stream.
+func TestStreamProductsCancel(t *testing.T) {
+	var active, peak int32
+	client := newTestClient(t, streamHandler(t, &active, &peak))
+
+	var calls int32
+	ctx, cancel := context.WithCancel(context.Background())
+	results := client.StreamProductsFunc(ctx, func() (string, bool) {
+		n := atomic.AddInt32(&calls, 1)
+		return "C" + strconv.Itoa(int(n)), true // endless
+	}, StreamOptions{Workers: 2, Ordered: true})
+
+	for i := 0; i < 5; i++ {
+		if r := <-results; r.Err != nil {
+			t.Fatalf("unexpected error: %v", r.Err)
+		}
+	}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		for range results {
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream not closed after cancel")
+	}
+	if n := atomic.LoadInt32(&calls); n > 5+2*2+2 {
+		t.Errorf("expected read-ahead to be bounded, got %d codes read", n)
+	}
+}
@@ -0,0 +1,167 @@
+package lcsc
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// suggestCacheTTL is how long suggestions are cached. Suggestions change
+	// little, but type-ahead repeats the same prefixes many times in a short
+	// span, so a short lifetime catches most repeats without going stale.
+	suggestCacheTTL = 30 * time.Second
+	// suggestCacheSize is the number of prefixes a client keeps suggestions for.
+	suggestCacheSize = 256
+)
+
+// Suggestions are type-ahead completions for a search prefix.
+type Suggestions struct {
+	Prefix     string
+	Keywords   []string             // Search keywords, most relevant first
+	Brands     []Manufacturer       // Matching manufacturers
+	Categories []SuggestionCategory // Matching catalog categories
+}
+
+// SuggestionCategory is a catalog category suggested for a prefix.
+type SuggestionCategory struct {
+	ID   FlexInt `json:"catalogId"`
+	Name string  `json:"catalogNameEn"`
+}
+
+// suggestResponseWrapper matches the suggestion endpoint's result.
+type suggestResponseWrapper struct {
+	KeywordList []string             `json:"keywordList"`
+	BrandList   []Manufacturer       `json:"brandList"`
+	CatalogList []SuggestionCategory `json:"catalogList"`
+}
+
+// Suggest returns keyword, brand and category suggestions for a search
+// prefix. Uses POST /search/v2/suggest with JSON body.
+//
+// Suggest is meant to be called on every keystroke: results are cached in
+// memory for 30 seconds per prefix, ignoring case and surrounding space,
+// whether or not the client has a cache, and a blank prefix returns empty
+// suggestions without a request. Cancel the context of the previous call
+// when a new keystroke arrives; a canceled call returns promptly with an
+// error matching context.Canceled, is not retried and is not cached.
+func (c *Client) Suggest(ctx context.Context, prefix string) (*Suggestions, error) {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" {
+		return &Suggestions{}, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	cacheKey := c.getCacheKeySuggest(prefix)
+	cached, ok := c.suggestions.get(cacheKey)
+	event := RequestEvent{Type: EventCacheMiss, Method: "POST", Endpoint: endpointName("/search/v2/suggest")}
+	if ok {
+		event.Type = EventCacheHit
+	}
+	c.observe(ctx, event)
+	if ok {
+		return cached, nil
+	}
+
+	body, err := c.doRequest(ctx, "POST", "/search/v2/suggest", nil, searchRequestBody{
+		Keyword: prefix,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var wrapper suggestResponseWrapper
+	if err := c.parseResponse(body, &wrapper); err != nil {
+		return nil, err
+	}
+
+	s := &Suggestions{
+		Prefix:     prefix,
+		Keywords:   wrapper.KeywordList,
+		Brands:     wrapper.BrandList,
+		Categories: wrapper.CatalogList,
+	}
+
+	c.suggestions.set(cacheKey, s)
+
+	return s, nil
+}
+
+// getCacheKeySuggest generates a cache key for suggestion requests.
+func (c *Client) getCacheKeySuggest(prefix string) string {
+	return fmt.Sprintf("suggest:%s:%s", c.cacheScope(), prefix)
+}
+
+// suggestCache is a small LRU cache of suggestions with a fixed TTL. Every
+// client has one, independent of WithCache, since type-ahead needs caching
+// to be usable at all.
+type suggestCache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // Most recently used first
+}
+
+type suggestEntry struct {
+	key         string
+	suggestions Suggestions
+	expiresAt   time.Time
+}
+
+func newSuggestCache() *suggestCache {
+	return &suggestCache{entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// get returns a copy of the suggestions cached for key.
+func (sc *suggestCache) get(key string) (*Suggestions, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	el, ok := sc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*suggestEntry)
+	if !time.Now().Before(entry.expiresAt) {
+		sc.order.Remove(el)
+		delete(sc.entries, key)
+		return nil, false
+	}
+	sc.order.MoveToFront(el)
+	return entry.suggestions.clone(), true
+}
+
+// set caches a copy of s for key, evicting the least recently used entry
+// when the cache is full.
+func (sc *suggestCache) set(key string, s *Suggestions) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	entry := &suggestEntry{key: key, suggestions: *s.clone(), expiresAt: time.Now().Add(suggestCacheTTL)}
+	if el, ok := sc.entries[key]; ok {
+		el.Value = entry
+		sc.order.MoveToFront(el)
+		return
+	}
+	sc.entries[key] = sc.order.PushFront(entry)
+	if sc.order.Len() > suggestCacheSize {
+		oldest := sc.order.Back()
+		sc.order.Remove(oldest)
+		delete(sc.entries, oldest.Value.(*suggestEntry).key)
+	}
+}
+
+// clone returns a copy of s that shares no slices with it.
+func (s *Suggestions) clone() *Suggestions {
+	return &Suggestions{
+		Prefix:     s.Prefix,
+		Keywords:   slices.Clone(s.Keywords),
+		Brands:     slices.Clone(s.Brands),
+		Categories: slices.Clone(s.Categories),
+	}
+}
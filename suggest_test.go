@@ -0,0 +1,147 @@
+package lcsc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestSuggest tests parsing and caching of suggestions without a
+// configured cache.
+func TestSuggest(t *testing.T) {
+	var requests int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Method != http.MethodPost || r.URL.Path != "/search/v2/suggest" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body searchRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Keyword != "stm32" {
+			t.Errorf("unexpected body %+v: %v", body, err)
+		}
+		writeAPIResult(t, w, map[string]interface{}{
+			"keywordList": []string{"stm32f103c8t6", "stm32g030"},
+			"brandList":   []map[string]interface{}{{"brandId": "42", "brandNameEn": "STMicroelectronics"}},
+			"catalogList": []map[string]interface{}{{"catalogId": 11329, "catalogNameEn": "Microcontrollers"}},
+		})
+	})
+	ctx := context.Background()
+
+	s, err := client.Suggest(ctx, " STM32 ")
+	if err != nil {
+		t.Fatalf("Suggest failed: %v", err)
+	}
+	if s.Prefix != "stm32" || len(s.Keywords) != 2 || s.Keywords[0] != "stm32f103c8t6" {
+		t.Errorf("unexpected suggestions: %+v", s)
+	}
+	if len(s.Brands) != 1 || s.Brands[0] != (Manufacturer{ID: 42, Name: "STMicroelectronics"}) {
+		t.Errorf("unexpected brands: %+v", s.Brands)
+	}
+	if len(s.Categories) != 1 || s.Categories[0].Name != "Microcontrollers" {
+		t.Errorf("unexpected categories: %+v", s.Categories)
+	}
+
+	s.Keywords[0] = "changed"
+	if s, err := client.Suggest(ctx, "stm32"); err != nil || s.Keywords[0] != "stm32f103c8t6" {
+		t.Fatalf("unexpected cached suggestions %+v: %v", s, err)
+	}
+	if requests != 1 {
+		t.Errorf("expected the second call to be cached, got %d requests", requests)
+	}
+
+	empty, err := client.Suggest(ctx, "  ")
+	if err != nil || len(empty.Keywords) != 0 || requests != 1 {
+		t.Errorf("expected empty suggestions without a request, got %+v, %v", empty, err)
+	}
+}
+
+// TestSuggestCanceled tests that canceled calls return promptly.
+func TestSuggestCanceled(t *testing.T) {
+	var requests int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Suggest(ctx, "stm"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected no request for a canceled context, got %d", requests)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	if _, err := client.Suggest(ctx, "stm3"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected prompt return, took %v", elapsed)
+	}
+}
+
+// TestSuggestNoRetryCanceled tests that requests are not retried once
+// their context is canceled.
+func TestSuggestNoRetryCanceled(t *testing.T) {
+	var requests, retries int32
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, WithRetryConfig(DefaultRetryConfig()), WithObserver(ObserverFunc(func(_ context.Context, e RequestEvent) {
+		switch e.Type {
+		case EventAttempt:
+			cancel() // The next keystroke arrives while the request fails
+		case EventRetry:
+			atomic.AddInt32(&retries, 1)
+		}
+	})))
+
+	if _, err := client.Suggest(ctx, "stm"); err == nil {
+		t.Error("expected error")
+	}
+	if requests != 1 || retries != 0 {
+		t.Errorf("expected 1 request and no retries, got %d and %d", requests, retries)
+	}
+}
+
+// TestSuggestCache tests expiry and eviction in the suggestion cache.
+func TestSuggestCache(t *testing.T) {
+	sc := newSuggestCache()
+	for i := 0; i < suggestCacheSize; i++ {
+		sc.set(fmt.Sprint(i), &Suggestions{Prefix: fmt.Sprint(i)})
+	}
+	sc.get("0")
+	sc.set("new", &Suggestions{Prefix: "new"})
+	if sc.order.Len() != suggestCacheSize {
+		t.Errorf("expected %d entries, got %d", suggestCacheSize, sc.order.Len())
+	}
+	if _, ok := sc.get("0"); !ok {
+		t.Error("expected the recently used entry to be kept")
+	}
+	if _, ok := sc.get("1"); ok {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+
+	sc.entries["2"].Value.(*suggestEntry).expiresAt = time.Now()
+	if _, ok := sc.get("2"); ok {
+		t.Error("expected the expired entry to be missed")
+	}
+	if _, ok := sc.entries["2"]; ok {
+		t.Error("expected the expired entry to be removed")
+	}
+}
@@ -0,0 +1,333 @@
+package lcsc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultWatchInterval is the polling interval used when WatchOptions.Interval is zero.
+const DefaultWatchInterval = 15 * time.Minute
+
+// WatchEventType identifies the kind of change reported by a Watcher.
+type WatchEventType string
+
+const (
+	EventStockChanged WatchEventType = "stock_changed" // Stock level changed
+	EventOutOfStock   WatchEventType = "out_of_stock"  // Stock dropped to zero
+	EventBackInStock  WatchEventType = "back_in_stock" // Stock rose from zero
+	EventStockBelow   WatchEventType = "stock_below"   // Stock fell below the product's threshold
+	EventPriceChanged WatchEventType = "price_changed" // Price breaks or prices changed
+	EventWatchError   WatchEventType = "error"         // Product could not be fetched
+)
+
+// StockSnapshot is the stock and pricing state of a product at one point in time.
+type StockSnapshot struct {
+	ProductCode string       `json:"productCode"`
+	StockNumber int          `json:"stockNumber"`
+	PriceList   []PriceBreak `json:"priceList"`
+	Currency    string       `json:"currency"`
+	Time        time.Time    `json:"time"`
+}
+
+// WatchEvent is a change detected by a Watcher.
+type WatchEvent struct {
+	Type        WatchEventType
+	ProductCode string
+	Previous    *StockSnapshot // Last known state; nil on first observation
+	Current     *StockSnapshot // New state; nil for EventWatchError
+	Threshold   int            // Threshold crossed, for EventStockBelow
+	Err         error          // Fetch error, for EventWatchError
+}
+
+// WatchOptions configures a Watcher.
+type WatchOptions struct {
+	// Interval between polls (default: DefaultWatchInterval). With a cached
+	// client, cached product details are invalidated before each poll.
+	Interval time.Duration
+	// StockBelow is the default stock threshold; EventStockBelow fires when
+	// stock drops below it. Zero disables the default threshold.
+	StockBelow int
+	// Thresholds overrides StockBelow per product code.
+	Thresholds map[string]int
+	// StateFile persists snapshots as JSON between runs. Empty keeps them in
+	// memory only.
+	StateFile string
+	// Events receives every event. Sends block until received or the
+	// context passed to Poll or Run is done.
+	Events chan<- WatchEvent
+	// OnEvent is called synchronously for every event.
+	OnEvent func(WatchEvent)
+}
+
+// Watcher polls a watch list of products and reports stock and price changes
+// against the last snapshot. Requests go through the client, so they share
+// its rate limiter and retry configuration.
+type Watcher struct {
+	client *Client
+	opts   WatchOptions
+
+	mu        sync.Mutex
+	codes     []string
+	snapshots map[string]StockSnapshot
+}
+
+// NewWatcher creates a Watcher for the given product codes. If
+// opts.StateFile exists, its snapshots are loaded as the baseline.
+func NewWatcher(client *Client, codes []string, opts WatchOptions) (*Watcher, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultWatchInterval
+	}
+
+	w := &Watcher{
+		client:    client,
+		opts:      opts,
+		snapshots: make(map[string]StockSnapshot),
+	}
+	if err := w.load(); err != nil {
+		return nil, err
+	}
+	w.Add(codes...)
+	return w, nil
+}
+
+// Add adds product codes to the watch list. Duplicates are ignored.
+func (w *Watcher) Add(codes ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, code := range codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code != "" && !containsString(w.codes, code) {
+			w.codes = append(w.codes, code)
+		}
+	}
+}
+
+// Remove removes a product code from the watch list. Its snapshot is kept
+// until the next save.
+func (w *Watcher) Remove(code string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	code = strings.ToUpper(strings.TrimSpace(code))
+	for i, c := range w.codes {
+		if c == code {
+			w.codes = append(w.codes[:i:i], w.codes[i+1:]...)
+			return
+		}
+	}
+}
+
+// Codes returns the current watch list.
+func (w *Watcher) Codes() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.codes...)
+}
+
+// Snapshot returns the last known state of a product.
+func (w *Watcher) Snapshot(code string) (StockSnapshot, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	s, ok := w.snapshots[strings.ToUpper(strings.TrimSpace(code))]
+	return s, ok
+}
+
+// Poll fetches every watched product once, delivers the resulting events
+// and persists the new snapshots. The first observation of a product only
+// records a baseline, apart from EventStockBelow when it is already below
+// its threshold. A product's snapshot is only updated once its events are
+// delivered, so changes are reported again after a failed delivery. Fetch
+// failures are reported as EventWatchError; the returned error is limited
+// to context cancellation and state file errors.
+func (w *Watcher) Poll(ctx context.Context) ([]WatchEvent, error) {
+	var events []WatchEvent
+	for _, code := range w.Codes() {
+		w.client.invalidateProduct(code)
+		product, err := w.client.GetProductDetails(ctx, code)
+		if err != nil {
+			if ctx.Err() != nil {
+				return events, ctx.Err()
+			}
+			event := WatchEvent{Type: EventWatchError, ProductCode: code, Err: err}
+			if prev, ok := w.Snapshot(code); ok {
+				event.Previous = &prev
+			}
+			events = append(events, event)
+			if err := w.deliver(ctx, event); err != nil {
+				return events, err
+			}
+			continue
+		}
+
+		current := StockSnapshot{
+			ProductCode: code,
+			StockNumber: int(product.StockNumber),
+			PriceList:   product.ProductPriceList,
+			Currency:    w.client.Currency(),
+			Time:        time.Now(),
+		}
+
+		var previous *StockSnapshot
+		if prev, ok := w.Snapshot(code); ok {
+			previous = &prev
+		}
+		for _, event := range diffSnapshots(previous, &current, w.threshold(code)) {
+			events = append(events, event)
+			if err := w.deliver(ctx, event); err != nil {
+				// Keep the old snapshot so the change is reported again.
+				return events, err
+			}
+		}
+
+		w.mu.Lock()
+		w.snapshots[code] = current
+		w.mu.Unlock()
+	}
+
+	return events, w.save()
+}
+
+// Run polls immediately and then every Interval until ctx is done, and
+// returns the context's error. State file errors stop the watcher.
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Poll(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// threshold returns the stock threshold for a product.
+func (w *Watcher) threshold(code string) int {
+	for c, n := range w.opts.Thresholds {
+		if strings.EqualFold(strings.TrimSpace(c), code) {
+			return n
+		}
+	}
+	return w.opts.StockBelow
+}
+
+// deliver passes an event to the callback and channel.
+func (w *Watcher) deliver(ctx context.Context, event WatchEvent) error {
+	if w.opts.OnEvent != nil {
+		w.opts.OnEvent(event)
+	}
+	if w.opts.Events != nil {
+		select {
+		case w.opts.Events <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// load reads snapshots from the state file, if any.
+func (w *Watcher) load() error {
+	if w.opts.StateFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(w.opts.StateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read watch state: %w", err)
+	}
+	if err := json.Unmarshal(data, &w.snapshots); err != nil {
+		return fmt.Errorf("failed to parse watch state: %w", err)
+	}
+	return nil
+}
+
+// save writes snapshots to the state file atomically.
+func (w *Watcher) save() error {
+	if w.opts.StateFile == "" {
+		return nil
+	}
+
+	w.mu.Lock()
+	data, err := json.MarshalIndent(w.snapshots, "", "  ")
+	w.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode watch state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(w.opts.StateFile), ".watch-*")
+	if err != nil {
+		return fmt.Errorf("failed to write watch state: %w", err)
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), w.opts.StateFile)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write watch state: %w", err)
+	}
+	return nil
+}
+
+// diffSnapshots returns the events between two snapshots of a product.
+// prev is nil on first observation. Prices are only compared when both
+// snapshots use the same currency.
+func diffSnapshots(prev, cur *StockSnapshot, threshold int) []WatchEvent {
+	event := func(t WatchEventType) WatchEvent {
+		return WatchEvent{Type: t, ProductCode: cur.ProductCode, Previous: prev, Current: cur}
+	}
+
+	var events []WatchEvent
+	if prev != nil && prev.StockNumber != cur.StockNumber {
+		switch {
+		case cur.StockNumber <= 0 && prev.StockNumber > 0:
+			events = append(events, event(EventOutOfStock))
+		case prev.StockNumber <= 0 && cur.StockNumber > 0:
+			events = append(events, event(EventBackInStock))
+		default:
+			events = append(events, event(EventStockChanged))
+		}
+	}
+
+	if threshold > 0 && cur.StockNumber < threshold && (prev == nil || prev.StockNumber >= threshold) {
+		e := event(EventStockBelow)
+		e.Threshold = threshold
+		events = append(events, e)
+	}
+
+	if prev != nil && prev.Currency == cur.Currency && len(diffPriceBreaks(prev.PriceList, cur.PriceList)) > 0 {
+		events = append(events, event(EventPriceChanged))
+	}
+	return events
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,320 @@
+package lcsc
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// watchServer serves product details whose stock and prices can be changed between polls.
+type watchServer struct {
+	mu       sync.Mutex
+	products map[string]Product
+	requests int
+}
+
+func (s *watchServer) set(code string, stock int, prices ...PriceBreak) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.products[code] = Product{ProductCode: code, StockNumber: FlexInt(stock), ProductPriceList: prices}
+}
+
+func (s *watchServer) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests++
+		product, ok := s.products[r.URL.Query().Get("productCode")]
+		s.mu.Unlock()
+
+		if !ok {
+			_, _ = w.Write([]byte(`{"code":404,"msg":"not found","result":null}`))
+			return
+		}
+		writeAPIResult(t, w, product)
+	}
+}
+
+// newWatchServer returns a watch server and a client pointing at it.
+func newWatchServer(t *testing.T, opts ...ClientOption) (*watchServer, *Client) {
+	s := &watchServer{products: make(map[string]Product)}
+	return s, newTestClient(t, s.handler(t), opts...)
+}
+
+// eventTypes returns the types of events in order.
+func eventTypes(events []WatchEvent) []WatchEventType {
+	types := make([]WatchEventType, len(events))
+	for i, e := range events {
+		types[i] = e.Type
+	}
+	return types
+}
+
+// assertEventTypes fails the test if events do not have the expected types.
+func assertEventTypes(t *testing.T, events []WatchEvent, expected ...WatchEventType) {
+	t.Helper()
+	got := eventTypes(events)
+	if len(got) != len(expected) {
+		t.Fatalf("expected events %v, got %v", expected, got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Fatalf("expected events %v, got %v", expected, got)
+		}
+	}
+}
+
+// TestWatcherStockEvents tests stock change, out of stock and back in stock events.
+func TestWatcherStockEvents(t *testing.T) {
+	server, client := newWatchServer(t)
+	server.set("C1", 500)
+
+	w, err := NewWatcher(client, []string{"c1"}, WatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	events, err := w.Poll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEventTypes(t, events)
+
+	server.set("C1", 400)
+	events, _ = w.Poll(ctx)
+	assertEventTypes(t, events, EventStockChanged)
+	if events[0].Previous.StockNumber != 500 || events[0].Current.StockNumber != 400 {
+		t.Errorf("unexpected snapshots: %+v -> %+v", events[0].Previous, events[0].Current)
+	}
+
+	server.set("C1", 0)
+	events, _ = w.Poll(ctx)
+	assertEventTypes(t, events, EventOutOfStock)
+
+	server.set("C1", 0)
+	events, _ = w.Poll(ctx)
+	assertEventTypes(t, events)
+
+	server.set("C1", 1000)
+	events, _ = w.Poll(ctx)
+	assertEventTypes(t, events, EventBackInStock)
+}
+
+// TestWatcherThresholds tests that EventStockBelow fires once per crossing.
+func TestWatcherThresholds(t *testing.T) {
+	server, client := newWatchServer(t)
+	server.set("C1", 150)
+	server.set("C2", 50)
+
+	w, err := NewWatcher(client, []string{"C1", "C2"}, WatchOptions{
+		StockBelow: 100,
+		Thresholds: map[string]int{"c2": 10},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	events, _ := w.Poll(ctx)
+	assertEventTypes(t, events)
+
+	server.set("C1", 90)
+	events, _ = w.Poll(ctx)
+	assertEventTypes(t, events, EventStockChanged, EventStockBelow)
+	if events[1].Threshold != 100 {
+		t.Errorf("expected threshold 100, got %d", events[1].Threshold)
+	}
+
+	server.set("C1", 80)
+	events, _ = w.Poll(ctx)
+	assertEventTypes(t, events, EventStockChanged)
+
+	server.set("C2", 5)
+	events, _ = w.Poll(ctx)
+	assertEventTypes(t, events, EventStockChanged, EventStockBelow)
+	if events[1].ProductCode != "C2" || events[1].Threshold != 10 {
+		t.Errorf("unexpected event: %+v", events[1])
+	}
+}
+
+// TestWatcherPriceEvents tests price change detection.
+func TestWatcherPriceEvents(t *testing.T) {
+	server, client := newWatchServer(t)
+	server.set("C1", 100, PriceBreak{Ladder: 1, ProductPrice: 0.1}, PriceBreak{Ladder: 100, ProductPrice: 0.05})
+
+	w, _ := NewWatcher(client, []string{"C1"}, WatchOptions{})
+	ctx := context.Background()
+	_, _ = w.Poll(ctx)
+
+	// Same breaks in a different order are not a change.
+	server.set("C1", 100, PriceBreak{Ladder: 100, ProductPrice: 0.05}, PriceBreak{Ladder: 1, ProductPrice: 0.1})
+	events, _ := w.Poll(ctx)
+	assertEventTypes(t, events)
+
+	server.set("C1", 100, PriceBreak{Ladder: 1, ProductPrice: 0.1}, PriceBreak{Ladder: 100, ProductPrice: 0.04})
+	events, _ = w.Poll(ctx)
+	assertEventTypes(t, events, EventPriceChanged)
+
+	server.set("C1", 100, PriceBreak{Ladder: 1, ProductPrice: 0.1})
+	events, _ = w.Poll(ctx)
+	assertEventTypes(t, events, EventPriceChanged)
+}
+
+// TestWatcherErrors tests that fetch failures are reported as events.
+func TestWatcherErrors(t *testing.T) {
+	server, client := newWatchServer(t)
+	server.set("C1", 100)
+
+	var callbackEvents []WatchEvent
+	w, _ := NewWatcher(client, []string{"C404", "C1"}, WatchOptions{
+		OnEvent: func(e WatchEvent) { callbackEvents = append(callbackEvents, e) },
+	})
+
+	events, err := w.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	assertEventTypes(t, events, EventWatchError)
+	if events[0].ProductCode != "C404" || events[0].Err != ErrProductNotFound {
+		t.Errorf("unexpected error event: %+v", events[0])
+	}
+	assertEventTypes(t, callbackEvents, EventWatchError)
+
+	if _, ok := w.Snapshot("C1"); !ok {
+		t.Error("expected C1 to be polled after the failure")
+	}
+}
+
+// TestWatcherStateFile tests that snapshots persist across watchers.
+func TestWatcherStateFile(t *testing.T) {
+	server, client := newWatchServer(t)
+	server.set("C1", 100, PriceBreak{Ladder: 1, ProductPrice: 0.1})
+	path := filepath.Join(t.TempDir(), "watch.json")
+	ctx := context.Background()
+
+	w, err := NewWatcher(client, []string{"C1"}, WatchOptions{StateFile: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	server.set("C1", 0, PriceBreak{Ladder: 1, ProductPrice: 0.2})
+	w, err = NewWatcher(client, []string{"C1"}, WatchOptions{StateFile: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := w.Snapshot("C1"); !ok || s.StockNumber != 100 {
+		t.Fatalf("expected loaded snapshot with stock 100, got %+v", s)
+	}
+
+	events, err := w.Poll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEventTypes(t, events, EventOutOfStock, EventPriceChanged)
+}
+
+// TestWatcherBypassesCache tests that polling is not served from the client cache.
+func TestWatcherBypassesCache(t *testing.T) {
+	server, client := newWatchServer(t, WithCache(NewMemoryCache(time.Hour)))
+	server.set("C1", 100)
+
+	w, _ := NewWatcher(client, []string{"C1"}, WatchOptions{})
+	ctx := context.Background()
+	_, _ = w.Poll(ctx)
+
+	server.set("C1", 50)
+	events, _ := w.Poll(ctx)
+	assertEventTypes(t, events, EventStockChanged)
+	if server.requests != 2 {
+		t.Errorf("expected 2 requests, got %d", server.requests)
+	}
+}
+
+// TestWatcherRun tests event delivery over a channel until cancellation.
+func TestWatcherRun(t *testing.T) {
+	server, client := newWatchServer(t)
+	server.set("C1", 0)
+
+	events := make(chan WatchEvent)
+	w, _ := NewWatcher(client, []string{"C1"}, WatchOptions{
+		Interval:   10 * time.Millisecond,
+		StockBelow: 1,
+		Events:     events,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+
+	select {
+	case e := <-events:
+		if e.Type != EventStockBelow {
+			t.Errorf("expected %s, got %s", EventStockBelow, e.Type)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not stop after cancellation")
+	}
+}
+
+// TestWatcherUndelivered tests that a change whose event could not be sent
+// is reported again on the next poll.
+func TestWatcherUndelivered(t *testing.T) {
+	server, client := newWatchServer(t)
+	server.set("C1", 500)
+
+	events := make(chan WatchEvent, 1)
+	w, _ := NewWatcher(client, []string{"C1"}, WatchOptions{Events: events})
+	if _, err := w.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fill the channel so the next send blocks until the timeout.
+	events <- WatchEvent{}
+	server.set("C1", 400)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := w.Poll(ctx); err == nil {
+		t.Fatal("expected the undelivered event to fail the poll")
+	}
+	if s, _ := w.Snapshot("C1"); s.StockNumber != 500 {
+		t.Errorf("expected the snapshot to stay at 500, got %d", s.StockNumber)
+	}
+
+	<-events
+	got, err := w.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEventTypes(t, got, EventStockChanged)
+	if s, _ := w.Snapshot("C1"); s.StockNumber != 400 {
+		t.Errorf("expected the snapshot to be updated to 400, got %d", s.StockNumber)
+	}
+}
+
+// TestWatcherAddRemove tests watch list management.
+func TestWatcherAddRemove(t *testing.T) {
+	w, _ := NewWatcher(NewClient(), []string{"C1", " c2 "}, WatchOptions{})
+	w.Add("C2", "C3")
+	w.Remove("c1")
+
+	codes := w.Codes()
+	if len(codes) != 2 || codes[0] != "C2" || codes[1] != "C3" {
+		t.Errorf("unexpected watch list: %v", codes)
+	}
+}